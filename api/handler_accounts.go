@@ -0,0 +1,192 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/pkg/errors"
+)
+
+var HandlerPathPaymentAccounts = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/accounts`
+
+// ParseAccountFilterQuery parses the filter queries of the accounts; "currency"
+// is the currency id, "deposited" selects the non-zero deposits,
+// "expire_before" is the time in unix seconds or RFC3339 and "min_limit" and
+// "max_limit" are the range of the transfer limit.
+func ParseAccountFilterQuery(q url.Values) (digest.AccountFilter, error) {
+	var f digest.AccountFilter
+
+	f.Currency = apic.ParseStringQuery(q.Get("currency"))
+	f.Deposited = apic.ParseBoolQuery(q.Get("deposited"))
+
+	if s := apic.ParseStringQuery(q.Get("expire_before")); len(s) > 0 {
+		t, err := ParseTimestampQuery(s)
+		if err != nil {
+			return f, errors.WithMessage(err, "invalid expire_before")
+		}
+
+		f.ExpireBefore = uint64(t.Unix())
+	}
+
+	for k, b := range map[string]**common.Big{"min_limit": &f.MinLimit, "max_limit": &f.MaxLimit} {
+		s := apic.ParseStringQuery(q.Get(k))
+		if len(s) < 1 {
+			continue
+		}
+
+		i, err := common.NewBigFromString(s)
+		if err != nil {
+			return f, errors.WithMessagef(err, "invalid %s", k)
+		}
+
+		*b = &i
+	}
+
+	return f, nil
+}
+
+func stringAccountFilterQuery(f digest.AccountFilter) string {
+	q := url.Values{}
+
+	if len(f.Currency) > 0 {
+		q.Set("currency", f.Currency)
+	}
+
+	if f.Deposited {
+		q.Set("deposited", "1")
+	}
+
+	if f.ExpireBefore > 0 {
+		q.Set("expire_before", fmt.Sprintf("%d", f.ExpireBefore))
+	}
+
+	if f.MinLimit != nil {
+		q.Set("min_limit", f.MinLimit.String())
+	}
+
+	if f.MaxLimit != nil {
+		q.Set("max_limit", f.MaxLimit.String())
+	}
+
+	return q.Encode()
+}
+
+func HandlePaymentAccounts(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	f, err := ParseAccountFilterQuery(r.URL.Query())
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	limit := apic.ParseLimitQuery(r.URL.Query().Get("limit"))
+	offset := apic.ParseStringQuery(r.URL.Query().Get("offset"))
+
+	cacheKey := apic.CacheKey(
+		r.URL.Path, stringAccountFilterQuery(f), apic.StringOffsetQuery(offset), fmt.Sprintf("limit=%d", limit),
+	)
+	if err := apic.LoadFromCache(hd.Cache(), cacheKey, w); err == nil {
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cacheKey, func() (interface{}, error) {
+		return handlePaymentAccountsInGroup(hd, contract, f, offset, limit)
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+
+		if !shared {
+			apic.HTTP2WriteCache(w, cacheKey, hd.ExpireShortLived())
+		}
+	}
+}
+
+func handlePaymentAccountsInGroup(
+	hd *apic.Handlers,
+	contract string,
+	f digest.AccountFilter,
+	offset string,
+	l int64,
+) ([]byte, error) {
+	limit := l
+	if l < 0 {
+		limit = hd.ItemsLimiter("payment-accounts")
+	}
+
+	var vas []apic.Hal
+	var last string
+
+	if err := digest.PaymentAccounts(
+		hd.Database(), contract, f, offset, limit,
+		func(va digest.AccountInfoValue) (bool, error) {
+			hal, err := buildAccountInfoValue(hd, contract, va)
+			if err != nil {
+				return false, err
+			}
+			vas = append(vas, hal)
+			last = va.AccountInfo().Address().String()
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	i, err := buildPaymentAccountsHal(hd, contract, f, vas, last, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return hd.Encoder().Marshal(i)
+}
+
+func buildPaymentAccountsHal(
+	hd *apic.Handlers,
+	contract string,
+	f digest.AccountFilter,
+	vas []apic.Hal,
+	last, offset string,
+) (apic.Hal, error) {
+	if len(vas) < 1 {
+		return apic.NewEmptyHal(), nil
+	}
+
+	baseSelf, err := hd.CombineURL(HandlerPathPaymentAccounts, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+	baseSelf = apic.AddQueryValue(baseSelf, stringAccountFilterQuery(f))
+
+	self := baseSelf
+	if len(offset) > 0 {
+		self = apic.AddQueryValue(self, apic.StringOffsetQuery(offset))
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(vas, apic.NewHalLink(self, nil))
+
+	h, err := hd.CombineURL(HandlerPathPaymentDesign, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("contract", apic.NewHalLink(h, nil))
+
+	if len(last) > 0 {
+		hal = hal.AddLink("next", apic.NewHalLink(apic.AddQueryValue(baseSelf, apic.StringOffsetQuery(last)), nil))
+	}
+
+	return hal, nil
+}
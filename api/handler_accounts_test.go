@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseAccountFilterQuery(t *testing.T) {
+	q := url.Values{}
+	q.Set("currency", "MCC")
+	q.Set("deposited", "true")
+	q.Set("expire_before", "1970-01-01T00:01:40Z")
+	q.Set("min_limit", "10")
+	q.Set("max_limit", "20")
+
+	f, err := ParseAccountFilterQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case f.Currency != "MCC", !f.Deposited:
+		t.Errorf("currency %q, deposited %v", f.Currency, f.Deposited)
+	case f.ExpireBefore != 100:
+		t.Errorf("expire before %d", f.ExpireBefore)
+	case f.MinLimit == nil || f.MinLimit.String() != "10", f.MaxLimit == nil || f.MaxLimit.String() != "20":
+		t.Errorf("limits %v, %v", f.MinLimit, f.MaxLimit)
+	}
+
+	// NOTE the query of the next link keeps the filter.
+	sq, err := url.ParseQuery(stringAccountFilterQuery(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := ParseAccountFilterQuery(sq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stringAccountFilterQuery(g) != stringAccountFilterQuery(f) {
+		t.Errorf("filter changed, %q", stringAccountFilterQuery(g))
+	}
+
+	for k, v := range map[string]string{"expire_before": "tomorrow", "min_limit": "a", "max_limit": "1.5"} {
+		if _, err := ParseAccountFilterQuery(url.Values{k: []string{v}}); err == nil {
+			t.Errorf("invalid %s, %q parsed", k, v)
+		}
+	}
+}
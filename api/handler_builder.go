@@ -0,0 +1,1041 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/localtime"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	HandlerPathPaymentBuildFact      = `/payment/build/fact/{operation:[a-z_]+}`
+	HandlerPathPaymentBuildOperation = `/payment/build/operation`
+)
+
+var (
+	BuildFactHint      = hint.MustNewHint("mitum-payment-build-fact-v0.0.1")
+	BuildOperationHint = hint.MustNewHint("mitum-payment-build-operation-v0.0.1")
+)
+
+// factBuilder builds the fact of an operation from the plain parameters,
+// params, and the operation from the fact.
+type factBuilder struct {
+	hint   hint.Hint
+	params interface{}
+	fact   func(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error)
+	op     func(base.Fact) (base.Operation, error)
+}
+
+var factBuilders = map[string]factBuilder{
+	"register_model": {
+		hint: payment.RegisterModelFactHint, params: buildContractParams{},
+		fact: buildRegisterModelFact, op: newBuiltOperation(payment.NewRegisterModel),
+	},
+	"deposit": {
+		hint: payment.DepositFactHint, params: buildDepositParams{},
+		fact: buildDepositFact, op: newBuiltOperation(payment.NewDeposit),
+	},
+	"deposit_items": {
+		hint: payment.DepositItemsFactHint, params: buildDepositItemsParams{},
+		fact: buildDepositItemsFact, op: newBuiltOperation(payment.NewDepositItems),
+	},
+	"update_account_setting": {
+		hint: payment.UpdateAccountSettingFactHint, params: buildUpdateAccountSettingParams{},
+		fact: buildUpdateAccountSettingFact, op: newBuiltOperation(payment.NewUpdateAccountSetting),
+	},
+	"transfer": {
+		hint: payment.TransferFactHint, params: buildTransferParams{},
+		fact: buildTransferFact, op: newBuiltOperation(payment.NewTransfer),
+	},
+	"split_transfer": {
+		hint: payment.SplitTransferFactHint, params: buildSplitTransferParams{},
+		fact: buildSplitTransferFact, op: newBuiltOperation(payment.NewSplitTransfer),
+	},
+	"withdraw": {
+		hint: payment.WithdrawFactHint, params: buildWithdrawParams{},
+		fact: buildWithdrawFact, op: newBuiltOperation(payment.NewWithdraw),
+	},
+	"withdraw_all": {
+		hint: payment.WithdrawAllFactHint, params: buildWithdrawParams{},
+		fact: buildWithdrawAllFact, op: newBuiltOperation(payment.NewWithdrawAll),
+	},
+	"lock_transfer": {
+		hint: payment.LockTransferFactHint, params: buildLockTransferParams{},
+		fact: buildLockTransferFact, op: newBuiltOperation(payment.NewLockTransfer),
+	},
+	"claim_transfer": {
+		hint: payment.ClaimTransferFactHint, params: buildClaimTransferParams{},
+		fact: buildClaimTransferFact, op: newBuiltOperation(payment.NewClaimTransfer),
+	},
+	"refund_transfer": {
+		hint: payment.RefundTransferFactHint, params: buildRefundTransferParams{},
+		fact: buildRefundTransferFact, op: newBuiltOperation(payment.NewRefundTransfer),
+	},
+	"open_channel": {
+		hint: payment.OpenChannelFactHint, params: buildOpenChannelParams{},
+		fact: buildOpenChannelFact, op: newBuiltOperation(payment.NewOpenChannel),
+	},
+	"close_channel": {
+		hint: payment.CloseChannelFactHint, params: buildContractParams{},
+		fact: buildCloseChannelFact, op: newBuiltOperation(payment.NewCloseChannel),
+	},
+	"claim_voucher": {
+		hint: payment.ClaimVoucherFactHint, params: buildClaimVoucherParams{},
+		fact: buildClaimVoucherFact, op: newBuiltOperation(payment.NewClaimVoucher),
+	},
+	"register_deposit_keys": {
+		hint: payment.RegisterDepositKeysFactHint, params: buildRegisterDepositKeysParams{},
+		fact: buildRegisterDepositKeysFact, op: newBuiltOperation(payment.NewRegisterDepositKeys),
+	},
+	"lock_deposit": {
+		hint: payment.LockDepositFactHint, params: buildDepositKeySignParams{},
+		fact: buildLockDepositFact, op: newBuiltOperation(payment.NewLockDeposit),
+	},
+	"unlock_deposit": {
+		hint: payment.UnlockDepositFactHint, params: buildDepositKeySignParams{},
+		fact: buildUnlockDepositFact, op: newBuiltOperation(payment.NewUnlockDeposit),
+	},
+	"update_merchant": {
+		hint: payment.UpdateMerchantFactHint, params: buildUpdateMerchantParams{},
+		fact: buildUpdateMerchantFact, op: newBuiltOperation(payment.NewUpdateMerchant),
+	},
+}
+
+// newBuiltOperation returns the operation constructor of the fact type, F.
+func newBuiltOperation[F base.Fact, T base.Operation](f func(F) (T, error)) func(base.Fact) (base.Operation, error) {
+	return func(fact base.Fact) (base.Operation, error) {
+		i, ok := fact.(F)
+		if !ok {
+			return nil, errors.Errorf("expected %T, not %T", *new(F), fact)
+		}
+
+		return f(i)
+	}
+}
+
+// factBuilderByHint returns the builder of the operation of the fact.
+func factBuilderByHint(ht hint.Hint) (factBuilder, bool) {
+	for _, b := range factBuilders {
+		if ht.IsCompatible(b.hint) {
+			return b, true
+		}
+	}
+
+	return factBuilder{}, false
+}
+
+// factBuilderParams returns the parameters of the builders ordered by the
+// operation name.
+func factBuilderParams() []interface{} {
+	names := make([]string, 0, len(factBuilders))
+	for k := range factBuilders {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	seen := map[reflect.Type]struct{}{}
+	params := make([]interface{}, 0, len(names))
+
+	for _, k := range names {
+		t := reflect.TypeOf(factBuilders[k].params)
+		if _, found := seen[t]; found {
+			continue
+		}
+
+		seen[t] = struct{}{}
+		params = append(params, factBuilders[k].params)
+	}
+
+	return params
+}
+
+// BuildFactValue is the unsigned fact. The signature of the signer is of
+// SignBytes, which is the network id, the fact hash and SignedAt.
+type BuildFactValue struct {
+	hint.BaseHinter
+	Fact      json.RawMessage `json:"fact"`
+	FactHash  string          `json:"fact_hash"`
+	SignedAt  localtime.Time  `json:"signed_at"`
+	SignBytes string          `json:"sign_bytes"`
+}
+
+// BuildOperationValue is the operation assembled with the signatures, which
+// can be sent to the network.
+type BuildOperationValue struct {
+	hint.BaseHinter
+	Operation json.RawMessage `json:"operation"`
+	Hash      string          `json:"hash"`
+}
+
+// HandlePaymentBuildFact builds the unsigned fact of the operation from the
+// plain parameters in the body.
+func HandlePaymentBuildFact(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	_, networkID := simulateState()
+	if networkID == nil {
+		apic.HTTP2ProblemWithError(w, errors.Errorf("builder not ready"), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	name, err, status := apic.ParseRequest(w, r, "operation")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	builder, found := factBuilders[name]
+	if !found {
+		apic.HTTP2ProblemWithError(w, errors.Errorf("unknown operation, %q", name), http.StatusNotFound)
+
+		return
+	}
+
+	body, err := readBuildBody(r)
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	var u buildTokenParams
+	if err := hd.Encoder().Unmarshal(body, &u); err != nil {
+		apic.HTTP2ProblemWithError(w, common.ErrDecodeJson.Wrap(err), http.StatusBadRequest)
+
+		return
+	}
+
+	if len(u.Token) < 1 {
+		u.Token = localtime.Now().UTC().String()
+	}
+
+	fact, err := builder.fact(body, hd.Encoder(), []byte(u.Token))
+	if err == nil {
+		err = fact.IsValid(nil)
+	}
+
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	b, err := hd.Encoder().Marshal(fact)
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	signedAt := localtime.New(localtime.Now().UTC())
+
+	va := BuildFactValue{
+		BaseHinter: hint.NewBaseHinter(BuildFactHint),
+		Fact:       b,
+		FactHash:   fact.Hash().String(),
+		SignedAt:   signedAt,
+		SignBytes:  hex.EncodeToString(util.ConcatBytesSlice(networkID, fact.Hash().Bytes(), signedAt.Bytes())),
+	}
+
+	h, err := hd.CombineURL(HandlerPathPaymentBuildFact, "operation", name)
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	apic.HTTP2WriteHal(hd.Encoder(), w, apic.NewBaseHal(va, apic.NewHalLink(h, nil)), http.StatusOK)
+}
+
+// HandlePaymentBuildOperation assembles the operation of the fact in the body
+// with the signatures, "signs"; the signatures are verified.
+func HandlePaymentBuildOperation(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	_, networkID := simulateState()
+	if networkID == nil {
+		apic.HTTP2ProblemWithError(w, errors.Errorf("builder not ready"), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	body, err := readBuildBody(r)
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	op, err := assembleOperation(hd.Encoder(), networkID, body)
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	b, err := hd.Encoder().Marshal(op)
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	va := BuildOperationValue{
+		BaseHinter: hint.NewBaseHinter(BuildOperationHint),
+		Operation:  b,
+		Hash:       op.Hash().String(),
+	}
+
+	h, err := hd.CombineURL(HandlerPathPaymentBuildOperation)
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	apic.HTTP2WriteHal(hd.Encoder(), w, apic.NewBaseHal(va, apic.NewHalLink(h, nil)), http.StatusOK)
+}
+
+func readBuildBody(r *http.Request) ([]byte, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+
+	switch {
+	case err != nil:
+		return nil, err
+	case int64(len(b)) > maxRequestBodySize:
+		return nil, errors.Errorf("body too large")
+	default:
+		return b, nil
+	}
+}
+
+// buildOperationParams is the fact built by HandlePaymentBuildFact with the
+// signatures of SignBytes.
+type buildOperationParams struct {
+	Fact  json.RawMessage   `json:"fact"`
+	Signs []json.RawMessage `json:"signs"`
+}
+
+// assembleOperation decodes the operation of the fact with the signatures and
+// the hash of them; the operation is decoded as the network does.
+func assembleOperation(enc encoder.Encoder, networkID base.NetworkID, body []byte) (base.Operation, error) {
+	var u buildOperationParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	if len(u.Signs) < 1 {
+		return nil, errors.Errorf("empty signs")
+	}
+
+	var fact base.Fact
+	if err := encoder.Decode(enc, u.Fact, &fact); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	ht, ok := fact.(hint.Hinter)
+	if !ok {
+		return nil, errors.Errorf("expected Hinter, not %T", fact)
+	}
+
+	builder, found := factBuilderByHint(ht.Hint())
+	if !found {
+		return nil, errors.Errorf("unknown fact, %T", fact)
+	}
+
+	op, err := builder.op(fact)
+	if err != nil {
+		return nil, err
+	}
+
+	decode := func(h util.Hash) (base.Operation, error) {
+		b, err := enc.Marshal(op)
+		if err != nil {
+			return nil, err
+		}
+
+		var m map[string]json.RawMessage
+		if err := enc.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+
+		if m["signs"], err = enc.Marshal(u.Signs); err != nil {
+			return nil, err
+		}
+
+		if h != nil {
+			if m["hash"], err = enc.Marshal(h); err != nil {
+				return nil, err
+			}
+		}
+
+		if b, err = enc.Marshal(m); err != nil {
+			return nil, err
+		}
+
+		var i base.Operation
+		if err := encoder.Decode(enc, b, &i); err != nil {
+			return nil, common.ErrDecodeJson.Wrap(err)
+		}
+
+		return i, nil
+	}
+
+	// NOTE the hash of the operation is of the signatures; the operation is
+	// decoded again with it.
+	signed, err := decode(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	hb, ok := signed.(interface{ HashBytes() []byte })
+	if !ok {
+		return nil, errors.Errorf("expected HashBytes, not %T", signed)
+	}
+
+	if signed, err = decode(valuehash.NewSHA256(hb.HashBytes())); err != nil {
+		return nil, err
+	}
+
+	if err := signed.IsValid(networkID); err != nil {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+func decodeBuildAddress(s string, enc encoder.Encoder, name string) (base.Address, error) {
+	a, err := base.DecodeAddress(strings.TrimSpace(s), enc)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid %s, %q", name, s)
+	}
+
+	return a, nil
+}
+
+func parseBuildBig(s, name string) (common.Big, error) {
+	b, err := common.NewBigFromString(strings.TrimSpace(s))
+	if err != nil {
+		return common.ZeroBig, errors.WithMessagef(err, "invalid %s, %q", name, s)
+	}
+
+	return b, nil
+}
+
+// buildTokenParams is the token of the fact; the current time by default.
+type buildTokenParams struct {
+	Token string `json:"token,omitempty"`
+}
+
+// buildContractParams is the sender, the contract account and the currency
+// of the fact.
+type buildContractParams struct {
+	buildTokenParams
+	Sender   string `json:"sender"`
+	Contract string `json:"contract"`
+	Currency string `json:"currency"`
+}
+
+func (u buildContractParams) decode(enc encoder.Encoder) (sender, contract base.Address, _ error) {
+	sender, err := decodeBuildAddress(u.Sender, enc, "sender")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contract, err = decodeBuildAddress(u.Contract, enc, "contract")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sender, contract, nil
+}
+
+func buildRegisterModelFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildContractParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewRegisterModelFact(token, sender, contract, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildDepositParams struct {
+	buildContractParams
+	Amount        string `json:"amount"`
+	TransferLimit string `json:"transfer_limit"`
+	StartTime     uint64 `json:"start_time"`
+	EndTime       uint64 `json:"end_time"`
+	Duration      uint64 `json:"duration"`
+	LockUp        *struct {
+		MaturityTime    uint64 `json:"maturity_time"`
+		PenaltyRate     uint64 `json:"penalty_rate"`
+		PenaltyReceiver string `json:"penalty_receiver"`
+	} `json:"lock_up"`
+}
+
+func buildDepositFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildDepositParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := parseBuildBig(u.Amount, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	transferLimit, err := parseBuildBig(u.TransferLimit, "transfer_limit")
+	if err != nil {
+		return nil, err
+	}
+
+	var lockUp *types.LockUp
+
+	if u.LockUp != nil {
+		var receiver base.Address
+
+		if len(u.LockUp.PenaltyReceiver) > 0 {
+			if receiver, err = decodeBuildAddress(u.LockUp.PenaltyReceiver, enc, "penalty_receiver"); err != nil {
+				return nil, err
+			}
+		}
+
+		i := types.NewLockUp(u.LockUp.MaturityTime, u.LockUp.PenaltyRate, receiver)
+		lockUp = &i
+	}
+
+	return payment.NewDepositFact(
+		token, sender, contract, amount, transferLimit,
+		u.StartTime, u.EndTime, u.Duration, lockUp, ctypes.CurrencyID(u.Currency),
+	), nil
+}
+
+type buildDepositItemsParams struct {
+	buildContractParams
+	Items []struct {
+		Currency      string `json:"currency"`
+		Amount        string `json:"amount"`
+		TransferLimit string `json:"transfer_limit"`
+		StartTime     uint64 `json:"start_time"`
+		EndTime       uint64 `json:"end_time"`
+		Duration      uint64 `json:"duration"`
+	} `json:"items"`
+}
+
+func buildDepositItemsFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildDepositItemsParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]payment.DepositItem, len(u.Items))
+
+	for i, it := range u.Items {
+		amount, err := parseBuildBig(it.Amount, "amount")
+		if err != nil {
+			return nil, err
+		}
+
+		transferLimit, err := parseBuildBig(it.TransferLimit, "transfer_limit")
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = payment.NewDepositItem(
+			ctypes.CurrencyID(it.Currency), amount, transferLimit, it.StartTime, it.EndTime, it.Duration)
+	}
+
+	return payment.NewDepositItemsFact(token, sender, contract, items, ctypes.CurrencyID(u.Currency)), nil
+}
+
+// buildUpdateAccountSettingParams is the setting of the account; the category
+// limits are kept without category_limits and cleared by the empty one.
+type buildUpdateAccountSettingParams struct {
+	buildContractParams
+	TransferLimit  string             `json:"transfer_limit"`
+	StartTime      uint64             `json:"start_time"`
+	EndTime        uint64             `json:"end_time"`
+	Duration       uint64             `json:"duration"`
+	CategoryLimits *map[string]string `json:"category_limits,omitempty"`
+}
+
+func buildUpdateAccountSettingFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildUpdateAccountSettingParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	transferLimit, err := parseBuildBig(u.TransferLimit, "transfer_limit")
+	if err != nil {
+		return nil, err
+	}
+
+	var limits map[string]common.Big
+
+	if u.CategoryLimits != nil {
+		limits = map[string]common.Big{}
+
+		for k, v := range *u.CategoryLimits {
+			if limits[k], err = parseBuildBig(v, "category limit"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return payment.NewUpdateAccountSettingFact(
+		token, sender, contract, transferLimit, u.StartTime, u.EndTime, u.Duration, limits,
+		ctypes.CurrencyID(u.Currency),
+	), nil
+}
+
+type buildTransferParams struct {
+	buildContractParams
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+}
+
+func buildTransferFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildTransferParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := decodeBuildAddress(u.Receiver, enc, "receiver")
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := parseBuildBig(u.Amount, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewTransferFact(token, sender, contract, receiver, amount, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildSplitTransferParams struct {
+	buildContractParams
+	Amount    string `json:"amount"`
+	Receivers []struct {
+		Receiver string `json:"receiver"`
+		Share    uint64 `json:"share"`
+	} `json:"receivers"`
+}
+
+func buildSplitTransferFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildSplitTransferParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := parseBuildBig(u.Amount, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	receivers := make([]payment.SplitReceiver, len(u.Receivers))
+
+	for i, r := range u.Receivers {
+		receiver, err := decodeBuildAddress(r.Receiver, enc, "receiver")
+		if err != nil {
+			return nil, err
+		}
+
+		receivers[i] = payment.NewSplitReceiver(receiver, r.Share)
+	}
+
+	return payment.NewSplitTransferFact(
+		token, sender, contract, amount, receivers, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildWithdrawParams struct {
+	buildContractParams
+}
+
+func buildWithdrawFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildWithdrawParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewWithdrawFact(token, sender, contract, ctypes.CurrencyID(u.Currency)), nil
+}
+
+func buildWithdrawAllFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildWithdrawParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewWithdrawAllFact(token, sender, contract, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildLockTransferParams struct {
+	buildContractParams
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+	HashLock string `json:"hash_lock"`
+	Timeout  uint64 `json:"timeout"`
+}
+
+func buildLockTransferFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildLockTransferParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := decodeBuildAddress(u.Receiver, enc, "receiver")
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := parseBuildBig(u.Amount, "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewLockTransferFact(
+		token, sender, contract, receiver, amount, u.HashLock, u.Timeout, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildClaimTransferParams struct {
+	buildContractParams
+	HashLock string `json:"hash_lock"`
+	Preimage string `json:"preimage"`
+}
+
+func buildClaimTransferFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildClaimTransferParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewClaimTransferFact(
+		token, sender, contract, u.HashLock, u.Preimage, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildRefundTransferParams struct {
+	buildContractParams
+	HashLock string `json:"hash_lock"`
+}
+
+func buildRefundTransferFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildRefundTransferParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewRefundTransferFact(token, sender, contract, u.HashLock, ctypes.CurrencyID(u.Currency)), nil
+}
+
+type buildOpenChannelParams struct {
+	buildContractParams
+	Receiver      string `json:"receiver"`
+	Capacity      string `json:"capacity"`
+	DisputeWindow uint64 `json:"dispute_window"`
+}
+
+func buildOpenChannelFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildOpenChannelParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := decodeBuildAddress(u.Receiver, enc, "receiver")
+	if err != nil {
+		return nil, err
+	}
+
+	capacity, err := parseBuildBig(u.Capacity, "capacity")
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewOpenChannelFact(
+		token, sender, contract, receiver, capacity, u.DisputeWindow, ctypes.CurrencyID(u.Currency)), nil
+}
+
+func buildCloseChannelFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildContractParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewCloseChannelFact(token, sender, contract, ctypes.CurrencyID(u.Currency)), nil
+}
+
+// buildClaimVoucherParams is the voucher signed by the depositor.
+type buildClaimVoucherParams struct {
+	buildContractParams
+	Voucher json.RawMessage `json:"voucher"`
+}
+
+func buildClaimVoucherFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildClaimVoucherParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	var voucher types.Voucher
+	if err := voucher.DecodeJSON(u.Voucher, enc); err != nil {
+		return nil, errors.WithMessage(err, "invalid voucher")
+	}
+
+	return payment.NewClaimVoucherFact(token, sender, contract, voucher, ctypes.CurrencyID(u.Currency)), nil
+}
+
+// buildDepositKeySignParams is the depositor and the sign of the deposit key
+// of the depositor. The sign is of the signed bytes of the fact with token,
+// so token should be given with it.
+type buildDepositKeySignParams struct {
+	buildContractParams
+	Depositor string          `json:"depositor"`
+	Sign      json.RawMessage `json:"sign,omitempty"`
+}
+
+func buildLockDepositFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildDepositKeySignParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	depositor, err := decodeBuildAddress(u.Depositor, enc, "depositor")
+	if err != nil {
+		return nil, err
+	}
+
+	return withBuildKeySign(enc, u.buildTokenParams, u.Sign,
+		payment.NewLockDepositFact(token, sender, contract, depositor, ctypes.CurrencyID(u.Currency)))
+}
+
+func buildUnlockDepositFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildDepositKeySignParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	depositor, err := decodeBuildAddress(u.Depositor, enc, "depositor")
+	if err != nil {
+		return nil, err
+	}
+
+	return withBuildKeySign(enc, u.buildTokenParams, u.Sign,
+		payment.NewUnlockDepositFact(token, sender, contract, depositor, ctypes.CurrencyID(u.Currency)))
+}
+
+// buildRegisterDepositKeysParams is the new deposit keys; the sign of the
+// current recovery key is needed to replace the registered keys.
+type buildRegisterDepositKeysParams struct {
+	buildContractParams
+	LockKey     string          `json:"lock_key"`
+	RecoveryKey string          `json:"recovery_key"`
+	Sign        json.RawMessage `json:"sign,omitempty"`
+}
+
+func buildRegisterDepositKeysFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildRegisterDepositKeysParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]base.Publickey, 2)
+
+	for i, k := range []string{u.LockKey, u.RecoveryKey} {
+		if keys[i], err = base.DecodePublickeyFromString(strings.TrimSpace(k), enc); err != nil {
+			return nil, errors.WithMessagef(err, "invalid %s, %q", []string{"lock_key", "recovery_key"}[i], k)
+		}
+	}
+
+	return withBuildKeySign(enc, u.buildTokenParams, u.Sign,
+		payment.NewRegisterDepositKeysFact(token, sender, contract, keys[0], keys[1], ctypes.CurrencyID(u.Currency)))
+}
+
+type buildUpdateMerchantParams struct {
+	buildContractParams
+	Merchant   string   `json:"merchant"`
+	Categories []string `json:"categories"`
+}
+
+func buildUpdateMerchantFact(body []byte, enc encoder.Encoder, token []byte) (base.Fact, error) {
+	var u buildUpdateMerchantParams
+	if err := enc.Unmarshal(body, &u); err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	sender, contract, err := u.decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant, err := decodeBuildAddress(u.Merchant, enc, "merchant")
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewUpdateMerchantFact(
+		token, sender, contract, merchant, u.Categories, ctypes.CurrencyID(u.Currency)), nil
+}
+
+// withBuildKeySign returns the fact with the sign of the deposit key. The
+// fact is decoded again with the sign and the hash of it.
+func withBuildKeySign(
+	enc encoder.Encoder, token buildTokenParams, sign json.RawMessage, fact base.Fact,
+) (base.Fact, error) {
+	if len(sign) < 1 {
+		return fact, nil
+	}
+
+	if len(token.Token) < 1 {
+		return nil, errors.Errorf("empty token with sign")
+	}
+
+	b, err := enc.Marshal(fact)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]json.RawMessage
+	if err := enc.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	m["sign"] = sign
+
+	decode := func() (base.Fact, error) {
+		b, err := enc.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+
+		var i base.Fact
+		if err := encoder.Decode(enc, b, &i); err != nil {
+			return nil, common.ErrDecodeJson.Wrap(err)
+		}
+
+		return i, nil
+	}
+
+	signed, err := decode()
+	if err != nil {
+		return nil, err
+	}
+
+	g, ok := signed.(interface{ GenerateHash() util.Hash })
+	if !ok {
+		return nil, errors.Errorf("expected GenerateHash, not %T", signed)
+	}
+
+	if m["hash"], err = enc.Marshal(g.GenerateHash()); err != nil {
+		return nil, err
+	}
+
+	return decode()
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/app/runtime/steps"
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	jsonenc "github.com/imfact-labs/mitum2/util/encoder/json"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/runtime/spec"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func newTestBuildEncoder(t *testing.T) *jsonenc.Encoder {
+	t.Helper()
+
+	enc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(enc, enc)
+
+	if err := steps.LoadHinters(encs); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range append(append([]encoder.DecodeDetail{}, spec.AddedHinters...), spec.AddedSupportedHinters...) {
+		if err := encs.AddDetail(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return enc
+}
+
+func TestFactBuilders(t *testing.T) {
+	enc := newTestBuildEncoder(t)
+	networkID := base.NetworkID("test")
+
+	sender, contract := ctypes.NewStringAddress("sender"), ctypes.NewStringAddress("contract")
+	receiver := ctypes.NewStringAddress("receiver").String()
+
+	h := sha256.Sum256([]byte("preimage"))
+	hashLock, preimage := hex.EncodeToString(h[:]), hex.EncodeToString([]byte("preimage"))
+
+	depositorKey := base.NewMPrivatekey()
+	voucher := types.NewVoucher(contract, ctypes.NewStringAddress("depositor"), sender, "MCC", 1, common.NewBig(10))
+	if err := voucher.Sign(depositorKey, networkID); err != nil {
+		t.Fatal(err)
+	}
+
+	lockKey := base.NewMPrivatekey()
+	lockSign, err := base.NewBaseSignFromBytes(lockKey, networkID,
+		payment.NewLockDepositFact([]byte("token"), sender, contract, sender, "MCC").SignedBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	common := func(m map[string]interface{}) map[string]interface{} {
+		m["token"] = "token"
+		m["sender"] = sender.String()
+		m["contract"] = contract.String()
+		m["currency"] = "MCC"
+
+		return m
+	}
+
+	params := map[string]map[string]interface{}{
+		"register_model": {},
+		"deposit": {
+			"amount": "100", "transfer_limit": "10", "start_time": 1, "end_time": 100, "duration": 1,
+		},
+		"deposit_items": {
+			"items": []map[string]interface{}{{
+				"currency": "MCC", "amount": "100", "transfer_limit": "10",
+				"start_time": 1, "end_time": 100, "duration": 1,
+			}},
+		},
+		"update_account_setting": {
+			"transfer_limit": "10", "start_time": 1, "end_time": 100, "duration": 1,
+			"category_limits": map[string]string{"food": "5"},
+		},
+		"transfer":        {"receiver": receiver, "amount": "10"},
+		"split_transfer":  {"amount": "10", "receivers": []map[string]interface{}{{"receiver": receiver, "share": 10000}}},
+		"withdraw":        {},
+		"withdraw_all":    {},
+		"lock_transfer":   {"receiver": receiver, "amount": "10", "hash_lock": hashLock, "timeout": 100},
+		"claim_transfer":  {"hash_lock": hashLock, "preimage": preimage},
+		"refund_transfer": {"hash_lock": hashLock},
+		"open_channel":    {"receiver": receiver, "capacity": "10", "dispute_window": 10},
+		"close_channel":   {},
+		"claim_voucher":   {"voucher": voucher},
+		"register_deposit_keys": {
+			"lock_key": lockKey.Publickey().String(), "recovery_key": base.NewMPrivatekey().Publickey().String(),
+		},
+		"lock_deposit":    {"depositor": sender.String(), "sign": lockSign},
+		"unlock_deposit":  {"depositor": sender.String()},
+		"update_merchant": {"merchant": receiver, "categories": []string{"food"}},
+	}
+
+	for name, b := range factBuilders {
+		p, found := params[name]
+		if !found {
+			t.Errorf("no params of %q", name)
+
+			continue
+		}
+
+		body, err := util.MarshalJSON(common(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fact, err := b.fact(body, enc, []byte("token"))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+
+			continue
+		}
+
+		// NOTE the unlock deposit without the sign is rejected by the fact.
+		if err := fact.IsValid(nil); err != nil && name != "unlock_deposit" {
+			t.Errorf("%s: invalid fact: %v", name, err)
+		}
+
+		ht, ok := fact.(hint.Hinter)
+		if !ok {
+			t.Fatalf("%s: not Hinter, %T", name, fact)
+		}
+
+		if i, found := factBuilderByHint(ht.Hint()); !found || !i.hint.Equal(b.hint) {
+			t.Errorf("%s: builder not found by hint, %v", name, ht.Hint())
+		}
+
+		op, err := b.op(fact)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+
+			continue
+		}
+
+		if !op.Fact().Hash().Equal(fact.Hash()) {
+			t.Errorf("%s: fact of operation changed", name)
+		}
+	}
+}
+
+func TestFactBuilderKeySign(t *testing.T) {
+	enc := newTestBuildEncoder(t)
+	networkID := base.NetworkID("test")
+
+	sender, contract := ctypes.NewStringAddress("sender"), ctypes.NewStringAddress("contract")
+	lockKey := base.NewMPrivatekey()
+
+	unsigned := payment.NewLockDepositFact([]byte("token"), sender, contract, sender, "MCC")
+
+	sign, err := base.NewBaseSignFromBytes(lockKey, networkID, unsigned.SignedBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(token string) (base.Fact, error) {
+		body, err := util.MarshalJSON(map[string]interface{}{
+			"token": token, "sender": sender.String(), "contract": contract.String(),
+			"depositor": sender.String(), "currency": "MCC", "sign": sign,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return buildLockDepositFact(body, enc, []byte("token"))
+	}
+
+	if _, err := build(""); err == nil {
+		t.Error("sign without token built")
+	}
+
+	fact, err := build("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, ok := fact.(payment.LockDepositFact)
+	if !ok {
+		t.Fatalf("expected LockDepositFact, not %T", fact)
+	}
+
+	switch {
+	case signed.KeySign() == nil:
+		t.Fatal("empty sign")
+	case !signed.KeySign().Signer().Equal(lockKey.Publickey()):
+		t.Error("other signer")
+	case signed.Hash().Equal(unsigned.Hash()):
+		t.Error("hash not updated with sign")
+	}
+
+	if err := signed.KeySign().Verify(networkID, signed.SignedBytes()); err != nil {
+		t.Error(err)
+	}
+
+	if err := signed.IsValid(nil); err != nil {
+		t.Error(err)
+	}
+}
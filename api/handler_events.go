@@ -0,0 +1,141 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/pkg/errors"
+)
+
+var HandlerPathPaymentEvents = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/events`
+
+var (
+	eventStreamBufferSize = 256
+	eventStreamHeartbeat  = time.Second * 15
+)
+
+// HandlePaymentEvents streams the payment events of the contract account as
+// Server-Sent Events. The id of the event is the offset of the payment
+// operations; with the "Last-Event-ID" header or the "from_height" query, the
+// events after it are sent from the digest before the new ones.
+func HandlePaymentEvents(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	f, err := ParseEventFilterQuery(r.URL.Query())
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	f.Address = apic.ParseStringQuery(r.URL.Query().Get("account"))
+
+	lastHeight, lastAddress, lastSequence := base.NilHeight, "", uint64(0)
+
+	offset := apic.ParseStringQuery(r.Header.Get("Last-Event-ID"))
+	if len(offset) > 0 {
+		lastHeight, lastAddress, lastSequence, err = digest.ParseEventOffset(offset)
+		if err != nil {
+			apic.HTTP2ProblemWithError(w, errors.WithMessage(err, "invalid Last-Event-ID"), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	// NOTE subscribe before reading the digest, so the events digested in the
+	// meantime are not missed.
+	ch, cancel := digest.PaymentEventBroker().Subscribe(contract, f, eventStreamBufferSize)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	send := func(va digest.EventValue) error {
+		if lastHeight > base.NilHeight && va.Compare(lastHeight, lastAddress, lastSequence) <= 0 {
+			return nil
+		}
+
+		b, err := hd.Encoder().Marshal(va)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", va.Offset(), va.Event().Type, b); err != nil {
+			return err
+		}
+
+		lastHeight, lastAddress, lastSequence = base.Height(va.Event().Height), va.Address(), va.Event().Sequence
+
+		return rc.Flush()
+	}
+
+	if len(offset) > 0 || f.FromHeight > base.NilHeight {
+		if err := sendDigestedEvents(hd, contract, f, offset, send); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case va, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			if err := send(va); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func sendDigestedEvents(
+	hd *apic.Handlers,
+	contract string,
+	f digest.EventFilter,
+	offset string,
+	send func(digest.EventValue) error,
+) error {
+	return digest.PaymentEvents(
+		hd.Database(), contract, f, false, offset, 0,
+		func(va digest.EventValue) (bool, error) {
+			if err := send(va); err != nil {
+				return false, err
+			}
+
+			return true, nil
+		},
+	)
+}
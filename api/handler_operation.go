@@ -0,0 +1,288 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	HandlerPathPaymentOperations        = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/operations`
+	HandlerPathPaymentAccountOperations = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/account/{address:(?i)` + ctypes.REStringAddressString + `}/operations`
+)
+
+// ParseEventFilterQuery parses the filter queries of the payment operations;
+// "type" is the comma separated event types, "currency" is the currency id,
+// "from_height" and "to_height" are the height range and "from" and "to" are
+// the time range in unix seconds or RFC3339.
+func ParseEventFilterQuery(q url.Values) (digest.EventFilter, error) {
+	f := digest.NewEventFilter()
+
+	if s := apic.ParseStringQuery(q.Get("type")); len(s) > 0 {
+		for _, t := range apic.ParseCSVStringQuery(s) {
+			t = strings.TrimSpace(t)
+			if !types.IsEventType(t) {
+				return f, errors.Errorf("unknown operation type, %q", t)
+			}
+
+			f.Types = append(f.Types, t)
+		}
+	}
+
+	f.Currency = apic.ParseStringQuery(q.Get("currency"))
+
+	for k, h := range map[string]*base.Height{"from_height": &f.FromHeight, "to_height": &f.ToHeight} {
+		s := apic.ParseStringQuery(q.Get(k))
+		if len(s) < 1 {
+			continue
+		}
+
+		i, err := base.ParseHeightString(s)
+		if err != nil {
+			return f, errors.WithMessagef(err, "invalid %s", k)
+		}
+
+		*h = i
+	}
+
+	for k, t := range map[string]*uint64{"from": &f.FromTime, "to": &f.ToTime} {
+		s := apic.ParseStringQuery(q.Get(k))
+		if len(s) < 1 {
+			continue
+		}
+
+		i, err := ParseTimestampQuery(s)
+		if err != nil {
+			return f, errors.WithMessagef(err, "invalid %s", k)
+		}
+
+		*t = uint64(i.Unix())
+	}
+
+	return f, nil
+}
+
+func stringEventFilterQuery(f digest.EventFilter) string {
+	q := url.Values{}
+
+	if len(f.Types) > 0 {
+		q.Set("type", strings.Join(f.Types, ","))
+	}
+
+	if len(f.Currency) > 0 {
+		q.Set("currency", f.Currency)
+	}
+
+	if f.FromHeight > base.NilHeight {
+		q.Set("from_height", f.FromHeight.String())
+	}
+
+	if f.ToHeight > base.NilHeight {
+		q.Set("to_height", f.ToHeight.String())
+	}
+
+	if f.FromTime > 0 {
+		q.Set("from", fmt.Sprintf("%d", f.FromTime))
+	}
+
+	if f.ToTime > 0 {
+		q.Set("to", fmt.Sprintf("%d", f.ToTime))
+	}
+
+	return q.Encode()
+}
+
+func HandlePaymentOperations(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	handlePaymentOperations(hd, w, r, false)
+}
+
+func HandlePaymentAccountOperations(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	handlePaymentOperations(hd, w, r, true)
+}
+
+func handlePaymentOperations(hd *apic.Handlers, w http.ResponseWriter, r *http.Request, byAccount bool) {
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	f, err := ParseEventFilterQuery(r.URL.Query())
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	if byAccount {
+		account, err, status := apic.ParseRequest(w, r, "address")
+		if err != nil {
+			apic.HTTP2ProblemWithError(w, err, status)
+
+			return
+		}
+
+		f.Address = account
+	}
+
+	limit := apic.ParseLimitQuery(r.URL.Query().Get("limit"))
+	offset := apic.ParseStringQuery(r.URL.Query().Get("offset"))
+	reverse := apic.ParseBoolQuery(r.URL.Query().Get("reverse"))
+
+	cacheKey := apic.CacheKey(
+		r.URL.Path, stringEventFilterQuery(f), apic.StringOffsetQuery(offset),
+		apic.StringBoolQuery("reverse", reverse), fmt.Sprintf("limit=%d", limit),
+	)
+	if err := apic.LoadFromCache(hd.Cache(), cacheKey, w); err == nil {
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cacheKey, func() (interface{}, error) {
+		i, filled, err := handlePaymentOperationsInGroup(hd, contract, f, offset, reverse, limit)
+
+		return []interface{}{i, filled}, err
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		l := v.([]interface{})
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, l[0].([]byte), http.StatusOK)
+
+		if !shared {
+			expire := hd.ExpireNotFilled()
+			if len(offset) > 0 && l[1].(bool) {
+				expire = time.Hour * 30
+			}
+
+			apic.HTTP2WriteCache(w, cacheKey, expire)
+		}
+	}
+}
+
+func handlePaymentOperationsInGroup(
+	hd *apic.Handlers,
+	contract string,
+	f digest.EventFilter,
+	offset string,
+	reverse bool,
+	l int64,
+) ([]byte, bool, error) {
+	limit := l
+	if l < 0 {
+		limit = hd.ItemsLimiter("payment-operations")
+	}
+
+	var vas []apic.Hal
+	var last *digest.EventValue
+
+	if err := digest.PaymentEvents(
+		hd.Database(), contract, f, reverse, offset, limit,
+		func(va digest.EventValue) (bool, error) {
+			hal, err := buildPaymentEventHal(hd, va)
+			if err != nil {
+				return false, err
+			}
+			vas = append(vas, hal)
+			last = &va
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, err
+	}
+
+	i, err := buildPaymentOperationsHal(hd, contract, f, vas, last, offset, reverse)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.Encoder().Marshal(i)
+
+	return b, int64(len(vas)) == limit, err
+}
+
+func buildPaymentEventHal(hd *apic.Handlers, va digest.EventValue) (apic.Hal, error) {
+	h, err := hd.CombineURL(apic.HandlerPathOperation, "hash", va.Event().FactHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(va, apic.NewHalLink(h, nil))
+	hal = hal.AddLink("operation", apic.NewHalLink(h, nil))
+
+	h, err = hd.CombineURL(apic.HandlerPathBlockByHeight, "height", base.Height(va.Event().Height).String())
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("block", apic.NewHalLink(h, nil))
+
+	return hal, nil
+}
+
+func buildPaymentOperationsHal(
+	hd *apic.Handlers,
+	contract string,
+	f digest.EventFilter,
+	vas []apic.Hal,
+	last *digest.EventValue,
+	offset string,
+	reverse bool,
+) (apic.Hal, error) {
+	if len(vas) < 1 {
+		return apic.NewEmptyHal(), nil
+	}
+
+	var baseSelf string
+	var err error
+	if len(f.Address) > 0 {
+		baseSelf, err = hd.CombineURL(HandlerPathPaymentAccountOperations, "contract", contract, "address", f.Address)
+	} else {
+		baseSelf, err = hd.CombineURL(HandlerPathPaymentOperations, "contract", contract)
+	}
+	if err != nil {
+		return nil, err
+	}
+	baseSelf = apic.AddQueryValue(baseSelf, stringEventFilterQuery(f))
+
+	self := baseSelf
+	if len(offset) > 0 {
+		self = apic.AddQueryValue(self, apic.StringOffsetQuery(offset))
+	}
+	if reverse {
+		self = apic.AddQueryValue(self, apic.StringBoolQuery("reverse", reverse))
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(vas, apic.NewHalLink(self, nil))
+
+	h, err := hd.CombineURL(HandlerPathPaymentDesign, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+
+	hal = hal.AddLink("contract", apic.NewHalLink(h, nil))
+
+	if last != nil {
+		next := apic.AddQueryValue(baseSelf, apic.StringOffsetQuery(last.Offset()))
+		if reverse {
+			next = apic.AddQueryValue(next, apic.StringBoolQuery("reverse", reverse))
+		}
+
+		hal = hal.AddLink("next", apic.NewHalLink(next, nil))
+	}
+
+	hal = hal.AddLink("reverse", apic.NewHalLink(
+		apic.AddQueryValue(baseSelf, apic.StringBoolQuery("reverse", !reverse)), nil))
+
+	return hal, nil
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestParseEventFilterQuery(t *testing.T) {
+	q := url.Values{}
+	q.Set("type", types.EventTypeDeposit+", "+types.EventTypeTransfer)
+	q.Set("currency", "MCC")
+	q.Set("from_height", "2")
+	q.Set("to_height", "3")
+	q.Set("from", "100")
+	q.Set("to", "1970-01-01T00:03:20Z")
+
+	f, err := ParseEventFilterQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case len(f.Types) != 2 || f.Types[1] != types.EventTypeTransfer:
+		t.Errorf("types %v", f.Types)
+	case f.Currency != "MCC":
+		t.Errorf("currency %q", f.Currency)
+	case f.FromHeight != 2 || f.ToHeight != 3:
+		t.Errorf("heights %v, %v", f.FromHeight, f.ToHeight)
+	case f.FromTime != 100 || f.ToTime != 200:
+		t.Errorf("times %d, %d", f.FromTime, f.ToTime)
+	}
+
+	// NOTE the query of the next link keeps the filter.
+	sq, err := url.ParseQuery(stringEventFilterQuery(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := ParseEventFilterQuery(sq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stringEventFilterQuery(g) != stringEventFilterQuery(f) {
+		t.Errorf("filter changed, %q", stringEventFilterQuery(g))
+	}
+
+	for k, v := range map[string]string{
+		"type": "unknown", "from_height": "a", "to": "yesterday",
+	} {
+		if _, err := ParseEventFilterQuery(url.Values{k: []string{v}}); err == nil {
+			t.Errorf("invalid %s, %q parsed", k, v)
+		}
+	}
+}
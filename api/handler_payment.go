@@ -1,30 +1,114 @@
 package api
 
 import (
-	"github.com/imfact-labs/payment-model/digest"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	apic "github.com/imfact-labs/currency-model/api"
+	cdigest "github.com/imfact-labs/currency-model/digest"
 	ctypes "github.com/imfact-labs/currency-model/types"
 	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/digest"
 	"github.com/imfact-labs/payment-model/types"
 )
 
 var (
 	HandlerPathPaymentDesign      = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}`
 	HandlerPathPaymentAccountInfo = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/account/{address:(?i)` + ctypes.REStringAddressString + `}`
+	HandlerPathPaymentLock        = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/lock/{hash_lock:(?i)[0-9a-f]{64}}`
 )
 
 func SetHandlers(hd *apic.Handlers) {
 	get := 1000
+	post := 100
+	_ = hd.SetHandler(HandlerPathPaymentOpenAPI, HandlePaymentOpenAPI, false, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentSimulate, HandlePaymentSimulate, false, post, post).
+		Methods(http.MethodOptions, http.MethodPost)
+	_ = hd.SetHandler(HandlerPathPaymentBuildFact, HandlePaymentBuildFact, false, post, post).
+		Methods(http.MethodOptions, http.MethodPost)
+	_ = hd.SetHandler(HandlerPathPaymentBuildOperation, HandlePaymentBuildOperation, false, post, post).
+		Methods(http.MethodOptions, http.MethodPost)
 	_ = hd.SetHandler(HandlerPathPaymentAccountInfo, HandlePaymentAccountInfo, true, get, get).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentLock, HandlePaymentLock, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentReceived, HandlePaymentReceived, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentStats, HandlePaymentStats, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentAccounts, HandlePaymentAccounts, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentAccountOperations, HandlePaymentAccountOperations, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentOperations, HandlePaymentOperations, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentEvents, HandlePaymentEvents, false, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathPaymentAccountStatement, HandlePaymentAccountStatement, false, get, get).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.SetHandler(HandlerPathPaymentDesign, HandlePaymentDesign, true, get, get).
 		Methods(http.MethodOptions, "GET")
 }
 
+// parseHeightQuery returns the height of the "height" query, or of the last
+// block at the "at" query in unix seconds or RFC3339; base.NilHeight when
+// neither is given.
+func parseHeightQuery(hd *apic.Handlers, q url.Values) (base.Height, error) {
+	height := apic.ParseStringQuery(q.Get("height"))
+	at := apic.ParseStringQuery(q.Get("at"))
+
+	switch {
+	case len(height) > 0 && len(at) > 0:
+		return base.NilHeight, cdigest.ErrBadRequest.Errorf("height and at can not be given together")
+	case len(height) > 0:
+		h, err := base.ParseHeightString(height)
+		if err != nil {
+			return base.NilHeight, cdigest.ErrBadRequest.WithMessage(err, "invalid height")
+		}
+
+		return h, nil
+	case len(at) > 0:
+		t, err := ParseTimestampQuery(at)
+		if err != nil {
+			return base.NilHeight, cdigest.ErrBadRequest.WithMessage(err, "invalid at")
+		}
+
+		return digest.HeightAt(hd.Database(), t)
+	default:
+		return base.NilHeight, nil
+	}
+}
+
+func stringHeightQuery(height base.Height) string {
+	if height <= base.NilHeight {
+		return ""
+	}
+
+	return "height=" + height.String()
+}
+
+// heightQueryExpire returns the cache expire; the states of the past height
+// are not changed.
+func heightQueryExpire(hd *apic.Handlers, height base.Height) time.Duration {
+	if height > base.NilHeight {
+		return hd.ExpireLongLived()
+	}
+
+	return hd.ExpireShortLived()
+}
+
 func HandlePaymentDesign(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
-	cacheKey := apic.CacheKeyPath(r)
+	height, err := parseHeightQuery(hd, r.URL.Query())
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	cacheKey := apic.CacheKey(apic.CacheKeyPath(r), stringHeightQuery(height))
 	if err := apic.LoadFromCache(hd.Cache(), cacheKey, w); err == nil {
 		return
 	}
@@ -37,39 +121,42 @@ func HandlePaymentDesign(hd *apic.Handlers, w http.ResponseWriter, r *http.Reque
 	}
 
 	if v, err, shared := hd.RG().Do(cacheKey, func() (interface{}, error) {
-		return handlePaymentDesignInGroup(hd, contract)
+		return handlePaymentDesignInGroup(hd, contract, height)
 	}); err != nil {
 		apic.HTTP2HandleError(w, err)
 	} else {
 		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
 
 		if !shared {
-			apic.HTTP2WriteCache(w, cacheKey, hd.ExpireShortLived())
+			apic.HTTP2WriteCache(w, cacheKey, heightQueryExpire(hd, height))
 		}
 	}
 }
 
-func handlePaymentDesignInGroup(hd *apic.Handlers, contract string) ([]byte, error) {
+func handlePaymentDesignInGroup(hd *apic.Handlers, contract string, height base.Height) ([]byte, error) {
 	var de *types.Design
 	var st base.State
 
-	de, st, err := digest.PaymentDesign(hd.Database(), contract)
+	de, st, err := digest.PaymentDesignByHeight(hd.Database(), contract, height)
 	if err != nil {
 		return nil, err
 	}
 
-	i, err := buildPaymentDesign(hd, contract, *de, st)
+	i, err := buildPaymentDesign(hd, contract, *de, st, height)
 	if err != nil {
 		return nil, err
 	}
 	return hd.Encoder().Marshal(i)
 }
 
-func buildPaymentDesign(hd *apic.Handlers, contract string, de types.Design, st base.State) (apic.Hal, error) {
+func buildPaymentDesign(
+	hd *apic.Handlers, contract string, de types.Design, st base.State, height base.Height,
+) (apic.Hal, error) {
 	h, err := hd.CombineURL(HandlerPathPaymentDesign, "contract", contract)
 	if err != nil {
 		return nil, err
 	}
+	h = apic.AddQueryValue(h, stringHeightQuery(height))
 
 	var hal apic.Hal
 	hal = apic.NewBaseHal(de, apic.NewHalLink(h, nil))
@@ -92,7 +179,14 @@ func buildPaymentDesign(hd *apic.Handlers, contract string, de types.Design, st
 }
 
 func HandlePaymentAccountInfo(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
-	cachekey := apic.CacheKeyPath(r)
+	height, err := parseHeightQuery(hd, r.URL.Query())
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	cachekey := apic.CacheKey(apic.CacheKeyPath(r), stringHeightQuery(height))
 	if err := apic.LoadFromCache(hd.Cache(), cachekey, w); err == nil {
 		return
 	}
@@ -112,22 +206,24 @@ func HandlePaymentAccountInfo(hd *apic.Handlers, w http.ResponseWriter, r *http.
 	}
 
 	if v, err, shared := hd.RG().Do(cachekey, func() (interface{}, error) {
-		return handlePaymentAccountInfoInGroup(hd, contract, account)
+		return handlePaymentAccountInfoInGroup(hd, contract, account, height)
 	}); err != nil {
 		apic.HTTP2HandleError(w, err)
 	} else {
 		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
 
 		if !shared {
-			apic.HTTP2WriteCache(w, cachekey, hd.ExpireShortLived())
+			apic.HTTP2WriteCache(w, cachekey, heightQueryExpire(hd, height))
 		}
 	}
 }
 
-func handlePaymentAccountInfoInGroup(hd *apic.Handlers, contract, account string) ([]byte, error) {
+func handlePaymentAccountInfoInGroup(
+	hd *apic.Handlers, contract, account string, height base.Height,
+) ([]byte, error) {
 	var accountInfoValue *digest.AccountInfoValue
 
-	accountInfoValue, err := digest.AccountInfo(hd.Database(), contract, account)
+	accountInfoValue, err := digest.AccountInfoByHeight(hd.Database(), contract, account, height)
 	if err != nil {
 		return nil, err
 	}
@@ -153,3 +249,75 @@ func buildAccountInfoValue(hd *apic.Handlers, contract string, it digest.Account
 
 	return hal, nil
 }
+
+func HandlePaymentLock(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	cacheKey := apic.CacheKeyPath(r)
+	if err := apic.LoadFromCache(hd.Cache(), cacheKey, w); err == nil {
+		return
+	}
+
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	hashLock, err, status := apic.ParseRequest(w, r, "hash_lock")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cacheKey, func() (interface{}, error) {
+		return handlePaymentLockInGroup(hd, contract, strings.ToLower(hashLock))
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+
+		if !shared {
+			apic.HTTP2WriteCache(w, cacheKey, hd.ExpireShortLived())
+		}
+	}
+}
+
+func handlePaymentLockInGroup(hd *apic.Handlers, contract, hashLock string) ([]byte, error) {
+	lock, st, err := digest.PaymentLock(hd.Database(), contract, hashLock)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := buildPaymentLock(hd, contract, *lock, st)
+	if err != nil {
+		return nil, err
+	}
+	return hd.Encoder().Marshal(i)
+}
+
+func buildPaymentLock(hd *apic.Handlers, contract string, lock types.Lock, st base.State) (apic.Hal, error) {
+	h, err := hd.CombineURL(HandlerPathPaymentLock, "contract", contract, "hash_lock", lock.HashLock())
+	if err != nil {
+		return nil, err
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(lock, apic.NewHalLink(h, nil))
+
+	h, err = hd.CombineURL(apic.HandlerPathBlockByHeight, "height", st.Height().String())
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("block", apic.NewHalLink(h, nil))
+
+	for i := range st.Operations() {
+		h, err := hd.CombineURL(apic.HandlerPathOperation, "hash", st.Operations()[i].String())
+		if err != nil {
+			return nil, err
+		}
+		hal = hal.AddLink("operations", apic.NewHalLink(h, nil))
+	}
+
+	return hal, nil
+}
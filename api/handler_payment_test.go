@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/imfact-labs/mitum2/base"
+)
+
+func TestParseHeightQuery(t *testing.T) {
+	height, err := parseHeightQuery(nil, url.Values{})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case height != base.NilHeight:
+		t.Errorf("height %v without query", height)
+	}
+
+	q, err := url.ParseQuery(stringHeightQuery(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if height, err = parseHeightQuery(nil, q); err != nil || height != 3 {
+		t.Errorf("height %v, %v", height, err)
+	}
+
+	if stringHeightQuery(base.NilHeight) != "" {
+		t.Error("query of nil height")
+	}
+
+	for name, q := range map[string]url.Values{
+		"height and at":  {"height": []string{"3"}, "at": []string{"100"}},
+		"invalid height": {"height": []string{"a"}},
+		"invalid at":     {"at": []string{"yesterday"}},
+	} {
+		if _, err := parseHeightQuery(nil, q); err == nil {
+			t.Errorf("%s: parsed", name)
+		}
+	}
+}
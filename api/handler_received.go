@@ -0,0 +1,142 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/digest"
+)
+
+var HandlerPathPaymentReceived = `/payment/received/{address:(?i)` + ctypes.REStringAddressString + `}`
+
+func HandlePaymentReceived(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	address, err, status := apic.ParseRequest(w, r, "address")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	limit := apic.ParseLimitQuery(r.URL.Query().Get("limit"))
+	offset := apic.ParseStringQuery(r.URL.Query().Get("offset"))
+	reverse := apic.ParseBoolQuery(r.URL.Query().Get("reverse"))
+
+	cacheKey := apic.CacheKey(
+		r.URL.Path, apic.StringOffsetQuery(offset),
+		apic.StringBoolQuery("reverse", reverse), fmt.Sprintf("limit=%d", limit),
+	)
+	if err := apic.LoadFromCache(hd.Cache(), cacheKey, w); err == nil {
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cacheKey, func() (interface{}, error) {
+		i, filled, err := handlePaymentReceivedInGroup(hd, address, offset, reverse, limit)
+
+		return []interface{}{i, filled}, err
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		l := v.([]interface{})
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, l[0].([]byte), http.StatusOK)
+
+		if !shared {
+			expire := hd.ExpireNotFilled()
+			if len(offset) > 0 && l[1].(bool) {
+				expire = time.Hour * 30
+			}
+
+			apic.HTTP2WriteCache(w, cacheKey, expire)
+		}
+	}
+}
+
+func handlePaymentReceivedInGroup(
+	hd *apic.Handlers,
+	address, offset string,
+	reverse bool,
+	l int64,
+) ([]byte, bool, error) {
+	limit := l
+	if l < 0 {
+		limit = hd.ItemsLimiter("payment-received")
+	}
+
+	var vas []apic.Hal
+	var nextOffset string
+
+	if err := digest.PaymentsReceived(
+		hd.Database(), address, reverse, offset, limit,
+		func(va digest.EventValue) (bool, error) {
+			hal, err := buildPaymentEventHal(hd, va)
+			if err != nil {
+				return false, err
+			}
+			vas = append(vas, hal)
+			nextOffset = digest.BuildReceivedOffset(va)
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, err
+	}
+
+	i, err := buildPaymentReceivedHal(hd, address, vas, nextOffset, offset, reverse)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := hd.Encoder().Marshal(i)
+
+	return b, int64(len(vas)) == limit, err
+}
+
+func buildPaymentReceivedHal(
+	hd *apic.Handlers,
+	address string,
+	vas []apic.Hal,
+	nextOffset, offset string,
+	reverse bool,
+) (apic.Hal, error) {
+	if len(vas) < 1 {
+		return apic.NewEmptyHal(), nil
+	}
+
+	baseSelf, err := hd.CombineURL(HandlerPathPaymentReceived, "address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	self := baseSelf
+	if len(offset) > 0 {
+		self = apic.AddQueryValue(self, apic.StringOffsetQuery(offset))
+	}
+	if reverse {
+		self = apic.AddQueryValue(self, apic.StringBoolQuery("reverse", reverse))
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(vas, apic.NewHalLink(self, nil))
+
+	h, err := hd.CombineURL(apic.HandlerPathAccount, "address", address)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("account", apic.NewHalLink(h, nil))
+
+	if len(nextOffset) > 0 {
+		next := apic.AddQueryValue(baseSelf, apic.StringOffsetQuery(nextOffset))
+		if reverse {
+			next = apic.AddQueryValue(next, apic.StringBoolQuery("reverse", reverse))
+		}
+
+		hal = hal.AddLink("next", apic.NewHalLink(next, nil))
+	}
+
+	hal = hal.AddLink("reverse", apic.NewHalLink(
+		apic.AddQueryValue(baseSelf, apic.StringBoolQuery("reverse", !reverse)), nil))
+
+	return hal, nil
+}
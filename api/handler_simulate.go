@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/util/localtime"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/pkg/errors"
+)
+
+var HandlerPathPaymentSimulate = `/payment/simulate`
+
+// maxRequestBodySize is the maximum size of the body of the POST requests.
+var maxRequestBodySize int64 = 1 << 20
+
+var (
+	simulateLock      sync.RWMutex
+	simulateDatabase  isaac.Database
+	simulateNetworkID base.NetworkID
+)
+
+// SetSimulateDatabase sets the state database of the local node, which the
+// simulation runs against.
+func SetSimulateDatabase(db isaac.Database, networkID base.NetworkID) {
+	simulateLock.Lock()
+	defer simulateLock.Unlock()
+
+	simulateDatabase = db
+	simulateNetworkID = networkID
+}
+
+func simulateState() (isaac.Database, base.NetworkID) {
+	simulateLock.RLock()
+	defer simulateLock.RUnlock()
+
+	return simulateDatabase, simulateNetworkID
+}
+
+// ParseTimestampQuery parses the unix seconds or the RFC3339 time.
+func ParseTimestampQuery(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return localtime.Now().UTC(), nil
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(i, 0).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid timestamp, %q", s)
+	}
+
+	return t.UTC(), nil
+}
+
+func HandlePaymentSimulate(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	db, networkID := simulateState()
+	if db == nil {
+		apic.HTTP2ProblemWithError(w, errors.Errorf("simulation not ready"), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	proposedAt, err := ParseTimestampQuery(r.URL.Query().Get("timestamp"))
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	defer r.Body.Close()
+	body := &bytes.Buffer{}
+	defer body.Reset()
+	if _, err := io.Copy(body, http.MaxBytesReader(w, r.Body, maxRequestBodySize)); err != nil {
+		status := http.StatusInternalServerError
+
+		var merr *http.MaxBytesError
+		if errors.As(err, &merr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	var op base.Operation
+	switch hinter, err := hd.Encoder().Decode(body.Bytes()); {
+	case err != nil:
+		apic.HTTP2ProblemWithError(w, common.ErrDecodeJson.Wrap(err), http.StatusBadRequest)
+
+		return
+	default:
+		i, ok := hinter.(base.Operation)
+		if !ok {
+			apic.HTTP2ProblemWithError(w, errors.Errorf("expected Operation, not %T", hinter), http.StatusBadRequest)
+
+			return
+		}
+
+		op = i
+	}
+
+	result, err := simulateOperation(r.Context(), db, networkID, op, proposedAt)
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	h, err := hd.CombineURL(HandlerPathPaymentSimulate)
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	apic.HTTP2WriteHal(hd.Encoder(), w, apic.NewBaseHal(result, apic.NewHalLink(h, nil)), http.StatusOK)
+}
+
+func simulateOperation(
+	ctx context.Context, db isaac.Database, networkID base.NetworkID, op base.Operation, proposedAt time.Time,
+) (payment.SimulateResult, error) {
+	height := base.GenesisHeight
+
+	switch bm, found, err := db.LastBlockMap(); {
+	case err != nil:
+		return payment.SimulateResult{}, err
+	case found:
+		height = bm.Manifest().Height() + 1
+	}
+
+	return payment.Simulate(ctx, op, networkID, height, proposedAt, db.State)
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/pkg/errors"
+)
+
+var HandlerPathPaymentAccountStatement = `/payment/{contract:(?i)` + ctypes.REStringAddressString +
+	`}/account/{address:(?i)` + ctypes.REStringAddressString + `}/statement`
+
+// HandlePaymentAccountStatement returns the statement of the deposit of the
+// account in the "currency" query; the "from" and "to" queries are the time
+// range in unix seconds or RFC3339. With "format=csv", the statement is
+// returned in CSV.
+func HandlePaymentAccountStatement(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	account, err, status := apic.ParseRequest(w, r, "address")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	cid := apic.ParseStringQuery(r.URL.Query().Get("currency"))
+	if len(cid) < 1 {
+		apic.HTTP2ProblemWithError(w, errors.Errorf("empty currency"), http.StatusBadRequest)
+
+		return
+	}
+
+	format := apic.ParseStringQuery(r.URL.Query().Get("format"))
+	switch format {
+	case "":
+		format = "json"
+	case "json", "csv":
+	default:
+		apic.HTTP2ProblemWithError(w, errors.Errorf("unknown format, %q", format), http.StatusBadRequest)
+
+		return
+	}
+
+	var from, to uint64
+	for k, t := range map[string]*uint64{"from": &from, "to": &to} {
+		s := apic.ParseStringQuery(r.URL.Query().Get(k))
+		if len(s) < 1 {
+			continue
+		}
+
+		i, err := ParseTimestampQuery(s)
+		if err != nil {
+			apic.HTTP2ProblemWithError(w, errors.WithMessagef(err, "invalid %s", k), http.StatusBadRequest)
+
+			return
+		}
+
+		*t = uint64(i.Unix())
+	}
+
+	key := apic.CacheKey(r.URL.Path, "currency="+cid, fmt.Sprintf("from=%d", from), fmt.Sprintf("to=%d", to), format)
+
+	v, err, _ := hd.RG().Do(key, func() (interface{}, error) {
+		return handlePaymentAccountStatementInGroup(hd, contract, account, cid, from, to, format)
+	})
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="statement-%s-%s.csv"`, account, cid))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(v.([]byte))
+
+		return
+	}
+
+	apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+}
+
+func handlePaymentAccountStatementInGroup(
+	hd *apic.Handlers, contract, account, cid string, from, to uint64, format string,
+) ([]byte, error) {
+	va, err := digest.PaymentStatement(hd.Database(), contract, account, cid, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "csv" {
+		var b bytes.Buffer
+		if err := va.WriteCSV(&b); err != nil {
+			return nil, err
+		}
+
+		return b.Bytes(), nil
+	}
+
+	h, err := hd.CombineURL(HandlerPathPaymentAccountStatement, "contract", contract, "address", account)
+	if err != nil {
+		return nil, err
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(va, apic.NewHalLink(h, nil))
+
+	h, err = hd.CombineURL(HandlerPathPaymentAccountInfo, "contract", contract, "address", account)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("account", apic.NewHalLink(h, nil))
+
+	return hd.Encoder().Marshal(hal)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/pkg/errors"
+)
+
+var HandlerPathPaymentStats = `/payment/{contract:(?i)` + ctypes.REStringAddressString + `}/stats`
+
+// HandlePaymentStats returns the stats of the contract account; the "from"
+// and "to" queries are the time range in unix seconds or RFC3339.
+func HandlePaymentStats(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	var from, to uint64
+	for k, t := range map[string]*uint64{"from": &from, "to": &to} {
+		s := apic.ParseStringQuery(r.URL.Query().Get(k))
+		if len(s) < 1 {
+			continue
+		}
+
+		i, err := ParseTimestampQuery(s)
+		if err != nil {
+			apic.HTTP2ProblemWithError(w, errors.WithMessagef(err, "invalid %s", k), http.StatusBadRequest)
+
+			return
+		}
+
+		*t = uint64(i.Unix())
+	}
+
+	cacheKey := apic.CacheKey(r.URL.Path, fmt.Sprintf("from=%d", from), fmt.Sprintf("to=%d", to))
+	if err := apic.LoadFromCache(hd.Cache(), cacheKey, w); err == nil {
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cacheKey, func() (interface{}, error) {
+		return handlePaymentStatsInGroup(hd, contract, from, to)
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+
+		if !shared {
+			apic.HTTP2WriteCache(w, cacheKey, hd.ExpireShortLived())
+		}
+	}
+}
+
+func handlePaymentStatsInGroup(hd *apic.Handlers, contract string, from, to uint64) ([]byte, error) {
+	va, err := digest.PaymentStats(hd.Database(), contract, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := hd.CombineURL(HandlerPathPaymentStats, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(va, apic.NewHalLink(h, nil))
+
+	h, err = hd.CombineURL(apic.HandlerPathBlockByHeight, "height", va.Height.String())
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("block", apic.NewHalLink(h, nil))
+
+	h, err = hd.CombineURL(HandlerPathPaymentDesign, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("contract", apic.NewHalLink(h, nil))
+
+	return hd.Encoder().Marshal(hal)
+}
@@ -0,0 +1,633 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/localtime"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var HandlerPathPaymentOpenAPI = `/payment/openapi.json`
+
+const (
+	OpenAPIVersion        = "3.0.3"
+	PaymentOpenAPIVersion = "v0.0.1"
+	OpenAPIMimetype       = "application/json; charset=utf-8"
+	openAPISchemaRef      = "#/components/schemas/"
+)
+
+// APIOperation is a payment route in the OpenAPI document. Response is the
+// value embedded in the HAL response; with List, the list of the HAL of them
+// is embedded. With Stream, the response is the Server-Sent Events of
+// Response.
+type APIOperation struct {
+	Path     string
+	Method   string
+	Summary  string
+	Query    []APIParameter
+	Header   []APIParameter
+	Bodies   []interface{}
+	Response interface{}
+	List     bool
+	Stream   bool
+	CSV      bool
+}
+
+// APIParameter is a query or header parameter; Type is the JSON schema type.
+type APIParameter struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+var (
+	heightAPIParameters = []APIParameter{
+		{Name: "height", Type: "integer", Description: "block height"},
+		{Name: "at", Type: "string", Description: "time of the block in unix seconds or RFC3339"},
+	}
+	timeRangeAPIParameters = []APIParameter{
+		{Name: "from", Type: "string", Description: "start of the time range in unix seconds or RFC3339"},
+		{Name: "to", Type: "string", Description: "end of the time range in unix seconds or RFC3339"},
+	}
+	pageAPIParameters = []APIParameter{
+		{Name: "limit", Type: "integer", Description: "number of the items"},
+		{Name: "offset", Type: "string", Description: "offset of the next page"},
+		{Name: "reverse", Type: "boolean", Description: "in the reverse order"},
+	}
+	eventFilterAPIParameters = append([]APIParameter{
+		{Name: "type", Type: "string", Description: "comma separated event types"},
+		{Name: "currency", Type: "string", Description: "currency id"},
+		{Name: "from_height", Type: "integer", Description: "start of the height range"},
+		{Name: "to_height", Type: "integer", Description: "end of the height range"},
+	}, timeRangeAPIParameters...)
+	accountFilterAPIParameters = []APIParameter{
+		{Name: "currency", Type: "string", Description: "currency id"},
+		{Name: "deposited", Type: "boolean", Description: "only the non-zero deposits"},
+		{Name: "expire_before", Type: "string", Description: "end time before it in unix seconds or RFC3339"},
+		{Name: "min_limit", Type: "string", Description: "minimum transfer limit"},
+		{Name: "max_limit", Type: "string", Description: "maximum transfer limit"},
+		{Name: "limit", Type: "integer", Description: "number of the items"},
+		{Name: "offset", Type: "string", Description: "offset of the next page"},
+	}
+)
+
+var openAPIPathParameters = map[string]string{
+	"contract":  "address of the contract account",
+	"address":   "address of the account",
+	"hash_lock": "hex of the hash lock",
+	"operation": "operation of the fact",
+}
+
+// PaymentAPIOperations returns the payment routes of SetHandlers in the
+// OpenAPI document.
+func PaymentAPIOperations() []APIOperation {
+	return []APIOperation{
+		{
+			Path: HandlerPathPaymentOpenAPI, Method: http.MethodGet,
+			Summary: "OpenAPI document of the payment API",
+		},
+		{
+			Path: HandlerPathPaymentSimulate, Method: http.MethodPost,
+			Summary: "simulate the operation against the state of the last block",
+			Query: []APIParameter{
+				{Name: "timestamp", Type: "string", Description: "proposed time in unix seconds or RFC3339"},
+			},
+			Bodies:   []interface{}{new(base.Operation)},
+			Response: payment.SimulateResult{},
+		},
+		{
+			Path: HandlerPathPaymentBuildFact, Method: http.MethodPost,
+			Summary:  "build the unsigned fact of the operation",
+			Bodies:   factBuilderParams(),
+			Response: BuildFactValue{},
+		},
+		{
+			Path: HandlerPathPaymentBuildOperation, Method: http.MethodPost,
+			Summary:  "assemble the operation of the fact with the signatures",
+			Bodies:   []interface{}{buildOperationParams{}},
+			Response: BuildOperationValue{},
+		},
+		{
+			Path: HandlerPathPaymentAccountInfo, Method: http.MethodGet,
+			Summary:  "transfer setting and deposit of the account",
+			Query:    heightAPIParameters,
+			Response: digest.AccountInfoValue{},
+		},
+		{
+			Path: HandlerPathPaymentLock, Method: http.MethodGet,
+			Summary:  "hash lock",
+			Response: types.Lock{},
+		},
+		{
+			Path: HandlerPathPaymentReceived, Method: http.MethodGet,
+			Summary:  "payments received by the account",
+			Query:    pageAPIParameters,
+			Response: digest.EventValue{},
+			List:     true,
+		},
+		{
+			Path: HandlerPathPaymentStats, Method: http.MethodGet,
+			Summary:  "stats of the contract account",
+			Query:    timeRangeAPIParameters,
+			Response: digest.StatsValue{},
+		},
+		{
+			Path: HandlerPathPaymentAccounts, Method: http.MethodGet,
+			Summary:  "accounts of the contract account",
+			Query:    accountFilterAPIParameters,
+			Response: digest.AccountInfoValue{},
+			List:     true,
+		},
+		{
+			Path: HandlerPathPaymentAccountOperations, Method: http.MethodGet,
+			Summary:  "payment operations of the account",
+			Query:    append(append([]APIParameter{}, eventFilterAPIParameters...), pageAPIParameters...),
+			Response: digest.EventValue{},
+			List:     true,
+		},
+		{
+			Path: HandlerPathPaymentOperations, Method: http.MethodGet,
+			Summary:  "payment operations of the contract account",
+			Query:    append(append([]APIParameter{}, eventFilterAPIParameters...), pageAPIParameters...),
+			Response: digest.EventValue{},
+			List:     true,
+		},
+		{
+			Path: HandlerPathPaymentEvents, Method: http.MethodGet,
+			Summary: "stream of the payment events",
+			Query: append([]APIParameter{
+				{Name: "account", Type: "string", Description: "address of the account"},
+			}, eventFilterAPIParameters...),
+			Header: []APIParameter{
+				{Name: "Last-Event-ID", Type: "string", Description: "id of the last received event"},
+			},
+			Response: digest.EventValue{},
+			Stream:   true,
+		},
+		{
+			Path: HandlerPathPaymentAccountStatement, Method: http.MethodGet,
+			Summary: "statement of the deposit of the account",
+			Query: append([]APIParameter{
+				{Name: "currency", Type: "string", Description: "currency id", Required: true},
+				{Name: "format", Type: "string", Description: "statement format", Enum: []string{"json", "csv"}},
+			}, timeRangeAPIParameters...),
+			Response: digest.StatementValue{},
+			CSV:      true,
+		},
+		{
+			Path: HandlerPathPaymentDesign, Method: http.MethodGet,
+			Summary:  "design of the contract account",
+			Query:    heightAPIParameters,
+			Response: types.Design{},
+		},
+	}
+}
+
+// openAPIJSONTypes is the types with MarshalJSON and the types they are
+// marshaled as.
+var openAPIJSONTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(digest.AccountInfoValue{}): reflect.TypeOf(digest.AccountInfoValueJSONMarshaler{}),
+	reflect.TypeOf(digest.EventValue{}):       reflect.TypeOf(digest.EventValueJSONMarshaler{}),
+	reflect.TypeOf(types.Design{}):            reflect.TypeOf(types.DesignJSONMarshaler{}),
+	reflect.TypeOf(types.Setting{}):           reflect.TypeOf(types.SettingJSONMarshaler{}),
+	reflect.TypeOf(types.DepositRecord{}):     reflect.TypeOf(types.DepositRecordJSONMarshaler{}),
+	reflect.TypeOf(types.Lock{}):              reflect.TypeOf(types.LockJSONMarshaler{}),
+	reflect.TypeOf(payment.SimulateResult{}):  reflect.TypeOf(payment.SimulateResultJSONMarshaler{}),
+}
+
+// openAPIHints is the hints of the hinted values.
+var openAPIHints = map[reflect.Type]hint.Hint{
+	reflect.TypeOf(digest.AccountInfoValue{}): digest.AccountInfoValueHint,
+	reflect.TypeOf(digest.EventValue{}):       digest.EventValueHint,
+	reflect.TypeOf(digest.StatsValue{}):       digest.StatsValueHint,
+	reflect.TypeOf(digest.StatementValue{}):   digest.StatementValueHint,
+	reflect.TypeOf(types.Design{}):            types.DesignHint,
+	reflect.TypeOf(types.Setting{}):           types.SettingHint,
+	reflect.TypeOf(types.DepositRecord{}):     types.DepositRecordHint,
+	reflect.TypeOf(types.Lock{}):              types.LockHint,
+	reflect.TypeOf(BuildFactValue{}):          BuildFactHint,
+	reflect.TypeOf(BuildOperationValue{}):     BuildOperationHint,
+}
+
+var openAPISchemas = map[reflect.Type]map[string]interface{}{
+	reflect.TypeOf(common.Big{}):                {"type": "string", "pattern": `^-?[0-9]+$`},
+	reflect.TypeOf(localtime.Time{}):            {"type": "string", "format": "date-time"},
+	reflect.TypeOf(json.RawMessage{}):           {"type": "object"},
+	reflect.TypeOf(hint.Hint{}):                 {"type": "string"},
+	reflect.TypeOf((*base.Address)(nil)).Elem(): {"type": "string"},
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+var (
+	openAPIDocumentOnce sync.Once
+	openAPIDocument     []byte
+	openAPIDocumentErr  error
+)
+
+// PaymentOpenAPI returns the OpenAPI document of the payment routes.
+func PaymentOpenAPI() ([]byte, error) {
+	openAPIDocumentOnce.Do(func() {
+		openAPIDocument, openAPIDocumentErr = util.MarshalJSON(NewOpenAPIDocument(PaymentAPIOperations()))
+	})
+
+	return openAPIDocument, openAPIDocumentErr
+}
+
+func HandlePaymentOpenAPI(_ *apic.Handlers, w http.ResponseWriter, _ *http.Request) {
+	b, err := PaymentOpenAPI()
+	if err != nil {
+		apic.HTTP2HandleError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", OpenAPIMimetype)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// NewOpenAPIDocument builds the OpenAPI document of the operations; the
+// schemas of the values are generated from the json tags of the types.
+func NewOpenAPIDocument(ops []APIOperation) map[string]interface{} {
+	g := &openAPISchemaGenerator{schemas: map[string]interface{}{}, names: map[reflect.Type]string{}}
+
+	g.schemas["HalLink"] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"href":       map[string]interface{}{"type": "string"},
+			"properties": map[string]interface{}{"type": "object"},
+		},
+		"required": []string{"href"},
+	}
+
+	paths := map[string]interface{}{}
+
+	for i := range ops {
+		p := OpenAPIPath(ops[i].Path)
+
+		item, found := paths[p].(map[string]interface{})
+		if !found {
+			item = map[string]interface{}{}
+			paths[p] = item
+		}
+
+		item[strings.ToLower(ops[i].Method)] = g.operation(ops[i])
+	}
+
+	return map[string]interface{}{
+		"openapi": OpenAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "payment API",
+			"version": PaymentOpenAPIVersion,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.schemas,
+		},
+	}
+}
+
+// OpenAPIPath converts the route path to the OpenAPI path by removing the
+// patterns of the variables.
+func OpenAPIPath(s string) string {
+	var sb strings.Builder
+
+	var depth int
+	var skip bool
+
+	for _, c := range s {
+		switch {
+		case c == '{':
+			depth++
+
+			if depth == 1 {
+				skip = false
+				sb.WriteRune(c)
+
+				continue
+			}
+		case c == '}':
+			depth--
+
+			if depth == 0 {
+				sb.WriteRune(c)
+
+				continue
+			}
+		case c == ':' && depth == 1:
+			skip = true
+		}
+
+		if depth < 1 || !skip {
+			sb.WriteRune(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// openAPIPathVariables returns the names of the variables in the OpenAPI
+// path.
+func openAPIPathVariables(p string) []string {
+	var names []string
+
+	for _, i := range strings.Split(p, "{")[1:] {
+		names = append(names, i[:strings.Index(i, "}")])
+	}
+
+	return names
+}
+
+type openAPISchemaGenerator struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func (g *openAPISchemaGenerator) operation(op APIOperation) map[string]interface{} {
+	var params []interface{}
+
+	for _, name := range openAPIPathVariables(OpenAPIPath(op.Path)) {
+		schema := map[string]interface{}{"type": "string"}
+
+		if name == "operation" && op.Path == HandlerPathPaymentBuildFact {
+			enum := make([]string, 0, len(factBuilders))
+			for k := range factBuilders {
+				enum = append(enum, k)
+			}
+
+			sort.Strings(enum)
+			schema["enum"] = enum
+		}
+
+		params = append(params, map[string]interface{}{
+			"name":        name,
+			"in":          "path",
+			"required":    true,
+			"description": openAPIPathParameters[name],
+			"schema":      schema,
+		})
+	}
+
+	for i := range op.Query {
+		params = append(params, openAPIParameter("query", op.Query[i]))
+	}
+
+	for i := range op.Header {
+		params = append(params, openAPIParameter("header", op.Header[i]))
+	}
+
+	o := map[string]interface{}{
+		"summary":   op.Summary,
+		"responses": g.responses(op),
+	}
+
+	if len(params) > 0 {
+		o["parameters"] = params
+	}
+
+	switch len(op.Bodies) {
+	case 0:
+	case 1:
+		o["requestBody"] = openAPIRequestBody(g.schema(reflect.TypeOf(op.Bodies[0])))
+	default:
+		oneOf := make([]interface{}, len(op.Bodies))
+		for i := range op.Bodies {
+			oneOf[i] = g.schema(reflect.TypeOf(op.Bodies[i]))
+		}
+
+		o["requestBody"] = openAPIRequestBody(map[string]interface{}{"oneOf": oneOf})
+	}
+
+	return o
+}
+
+func (g *openAPISchemaGenerator) responses(op APIOperation) map[string]interface{} {
+	content := map[string]interface{}{}
+
+	switch {
+	case op.Response == nil:
+		content["application/json"] = map[string]interface{}{
+			"schema": map[string]interface{}{"type": "object"},
+		}
+	case op.Stream:
+		content["text/event-stream"] = map[string]interface{}{
+			"schema": map[string]interface{}{
+				"type":        "string",
+				"description": "the data of the events is the JSON of x-event-data",
+			},
+			"x-event-data": g.schema(reflect.TypeOf(op.Response)),
+		}
+	default:
+		va := g.schema(reflect.TypeOf(op.Response))
+		if op.List {
+			va = map[string]interface{}{"type": "array", "items": openAPIHal(va)}
+		}
+
+		content[strings.Split(apic.HALMimetype, ";")[0]] = map[string]interface{}{"schema": openAPIHal(va)}
+	}
+
+	if op.CSV {
+		content["text/csv"] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+	}
+
+	return map[string]interface{}{
+		"200": map[string]interface{}{"description": "OK", "content": content},
+		"default": map[string]interface{}{
+			"description": "problem",
+			"content": map[string]interface{}{
+				strings.Split(apic.ProblemMimetype, ";")[0]: map[string]interface{}{
+					"schema": map[string]interface{}{"type": "string", "description": "title of the problem"},
+				},
+			},
+		},
+	}
+}
+
+// schema returns the schema of the type; the named structs are added to the
+// components.
+func (g *openAPISchemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	if s, found := openAPISchemas[t]; found {
+		return s
+	}
+
+	switch t.Kind() { //nolint:exhaustive //...
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Interface:
+		return map[string]interface{}{"type": "object"}
+	}
+
+	named, found := openAPIJSONTypes[t]
+
+	switch {
+	case found:
+	case t.Kind() == reflect.Struct && t.Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Struct && t.Implements(jsonMarshalerType):
+		return map[string]interface{}{}
+	default:
+		named = t
+	}
+
+	switch t.Kind() { //nolint:exhaustive //...
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+	default:
+		return map[string]interface{}{}
+	}
+
+	if len(t.Name()) < 1 {
+		return g.object(named, t)
+	}
+
+	name, found := g.names[t]
+	if !found {
+		name = g.componentName(t)
+		g.names[t] = name
+		g.schemas[name] = g.object(named, t)
+	}
+
+	return map[string]interface{}{"$ref": openAPISchemaRef + name}
+}
+
+// object returns the schema of the fields of the struct type, t; the hint of
+// the original type, ot, is the example of "_hint".
+func (g *openAPISchemaGenerator) object(t, ot reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	g.fields(t, properties, &required)
+
+	if ht, found := openAPIHints[ot]; found {
+		properties[hint.HintedJSONTag] = map[string]interface{}{"type": "string", "example": ht.String()}
+	}
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+
+	return s
+}
+
+func (g *openAPISchemaGenerator) fields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && len(name) < 1 && ft.Kind() == reflect.Struct {
+			g.fields(ft, properties, required)
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if len(name) < 1 {
+			name = f.Name
+		}
+
+		properties[name] = g.schema(f.Type)
+
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func (g *openAPISchemaGenerator) componentName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+
+	if _, found := g.schemas[name]; found {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	return name
+}
+
+func openAPIHal(embedded map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"_embedded": embedded,
+			"_links": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"$ref": openAPISchemaRef + "HalLink"},
+			},
+			"_extra": map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+func openAPIRequestBody(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+func openAPIParameter(in string, p APIParameter) map[string]interface{} {
+	schema := map[string]interface{}{"type": p.Type}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+
+	return map[string]interface{}{
+		"name":        p.Name,
+		"in":          in,
+		"required":    p.Required,
+		"description": p.Description,
+		"schema":      schema,
+	}
+}
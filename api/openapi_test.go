@@ -0,0 +1,92 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/currency-model/app/modulekit"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/network/quicstream"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/payment-model/api"
+	"github.com/imfact-labs/payment-model/module"
+)
+
+// TestPaymentOpenAPIRoutes checks every route of SetHandlers and of the module
+// is in the OpenAPI document and the document has no other route.
+func TestPaymentOpenAPIRoutes(t *testing.T) {
+	b, err := api.PaymentOpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := map[string]bool{}
+	for p, ops := range doc.Paths {
+		for m := range ops {
+			spec[strings.ToUpper(m)+" "+p] = false
+		}
+	}
+
+	check := func(from, path string, methods []string) {
+		for _, m := range methods {
+			if m == http.MethodOptions {
+				continue
+			}
+
+			k := strings.ToUpper(m) + " " + api.OpenAPIPath(path)
+			if _, found := spec[k]; !found {
+				t.Errorf("%s route, %q not in the OpenAPI document", from, k)
+
+				continue
+			}
+
+			spec[k] = true
+		}
+	}
+
+	ctx := util.ContextWithValues(context.Background(), map[util.ContextKey]interface{}{
+		launch.LoggingContextKey: logging.NewLogging(nil),
+	})
+
+	hd := apic.NewHandlers(ctx, nil, nil, nil, nil, nil, mux.NewRouter(), nil, quicstream.ConnInfo{})
+	api.SetHandlers(hd)
+
+	for path, route := range hd.Routes() {
+		methods, err := route.GetMethods()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		check("handler", path, methods)
+	}
+
+	reg := modulekit.NewRegistry()
+	if err := reg.Register(module.Module{}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, _ := reg.Module(module.ID)
+	for i := range entry.APIRoutes {
+		check("module", entry.APIRoutes[i].Path, entry.APIRoutes[i].Methods)
+	}
+
+	for k, found := range spec {
+		if !found {
+			t.Errorf("%q in the OpenAPI document not registered", k)
+		}
+	}
+}
@@ -7,22 +7,27 @@ import (
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/types"
 	"github.com/pkg/errors"
 )
 
 type DepositCommand struct {
 	BaseCommand
 	ccmds.OperationFlags
-	Sender        ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
-	Contract      ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
-	Amount        ccmds.BigFlag        `arg:"" name:"amount" help:"deposit amount" required:"true"`
-	TransferLimit ccmds.BigFlag        `arg:"" name:"transfer limit" help:"transfer limit" required:"true"`
-	StartTime     uint64               `arg:"" name:"start time" help:"start time" required:"true"`
-	EndTime       uint64               `arg:"" name:"end time" help:"end time" required:"true"`
-	Duration      uint64               `arg:"" name:"duration" help:"duration" required:"true"`
-	Currency      ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
-	sender        base.Address
-	contract      base.Address
+	Sender          ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
+	Contract        ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
+	Amount          ccmds.BigFlag        `arg:"" name:"amount" help:"deposit amount" required:"true"`
+	TransferLimit   ccmds.BigFlag        `arg:"" name:"transfer limit" help:"transfer limit" required:"true"`
+	StartTime       uint64               `arg:"" name:"start time" help:"start time" required:"true"`
+	EndTime         uint64               `arg:"" name:"end time" help:"end time" required:"true"`
+	Duration        uint64               `arg:"" name:"duration" help:"duration" required:"true"`
+	Currency        ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
+	MaturityTime    uint64               `name:"maturity-time" help:"lock-up maturity time; withdraw is blocked before it"`
+	PenaltyRate     uint64               `name:"penalty-rate" help:"early withdraw penalty in basis points"`
+	PenaltyReceiver ccmds.AddressFlag    `name:"penalty-receiver" help:"early withdraw penalty receiver; early withdraw is allowed only with it"`
+	sender          base.Address
+	contract        base.Address
+	lockUp          *types.LockUp
 }
 
 func (cmd *DepositCommand) Run(pctx context.Context) error { // nolint:dupl
@@ -63,6 +68,22 @@ func (cmd *DepositCommand) parseFlags() error {
 		cmd.contract = a
 	}
 
+	if cmd.MaturityTime > 0 {
+		var receiver base.Address
+		if len(cmd.PenaltyReceiver.String()) > 0 {
+			a, err = cmd.PenaltyReceiver.Encode(cmd.Encoders.JSON())
+			if err != nil {
+				return errors.Wrapf(err, "invalid penalty receiver format, %q", cmd.PenaltyReceiver)
+			}
+			receiver = a
+		}
+
+		lockUp := types.NewLockUp(cmd.MaturityTime, cmd.PenaltyRate, receiver)
+		cmd.lockUp = &lockUp
+	} else if cmd.PenaltyRate > 0 || len(cmd.PenaltyReceiver.String()) > 0 {
+		return errors.Errorf("penalty without maturity time")
+	}
+
 	return nil
 }
 
@@ -71,7 +92,7 @@ func (cmd *DepositCommand) createOperation() (base.Operation, error) { // nolint
 
 	fact := payment.NewDepositFact(
 		[]byte(cmd.Token), cmd.sender, cmd.contract, cmd.Amount.Big, cmd.TransferLimit.Big,
-		cmd.StartTime, cmd.EndTime, cmd.Duration, cmd.Currency.CID,
+		cmd.StartTime, cmd.EndTime, cmd.Duration, cmd.lockUp, cmd.Currency.CID,
 	)
 	if err := fact.IsValid(nil); err != nil {
 		return nil, err
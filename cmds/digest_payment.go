@@ -0,0 +1,358 @@
+package cmds
+
+import (
+	"context"
+
+	csteps "github.com/imfact-labs/currency-model/app/runtime/steps"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	isaacblock "github.com/imfact-labs/mitum2/isaac/block"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/imfact-labs/payment-model/runtime/steps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var (
+	PNameRebuildPaymentDigest = ps.Name("rebuild-payment-digest")
+	PNameReplayPaymentWebhook = ps.Name("replay-payment-webhook")
+	PNamePaymentStatement     = ps.Name("payment-statement")
+)
+
+type DigestPaymentCommand struct { //nolint:govet //...
+	Rebuild       RebuildPaymentDigestCommand `cmd:"" help:"rebuild payment digest collections from local block data"`
+	ReplayWebhook ReplayPaymentWebhookCommand `cmd:"" name:"replay-webhook" help:"deliver the dead letters of payment webhooks again"`
+	Statement     PaymentStatementCommand     `cmd:"" help:"export the deposit statement of an account"`
+}
+
+// newDigestPaymentPS returns the process which loads the local storage and the
+// digest database for the digest-payment commands.
+func newDigestPaymentPS(name string, log *logging.Logging) *ps.PS {
+	pps := ps.NewPS(name)
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, csteps.PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(csteps.PNameDigestDesign, csteps.PLoadDigestDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal).
+		AddOK(cdigest.PNameDigesterDataBase, cdigest.ProcessDigesterDatabase, nil,
+			csteps.PNameDigestDesign, launch.PNameStorage)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, steps.PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(launch.PNameLoadFromDatabase, launch.PLoadFromDatabase).
+		PostAddOK(launch.PNameCheckBlocksOfStorage, launch.PCheckBlocksOfStorage).
+		PostAddOK(launch.PNamePatchBlockItemReaders, launch.PPatchBlockItemReaders)
+
+	return pps
+}
+
+type RebuildPaymentDigestCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	HeightRange     launch.RangeFlag `name:"range" help:"<from>-<to>" default:""`
+	Restart         bool             `name:"restart" help:"ignore the progress of the last rebuild"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+	fromHeight      base.Height
+	toHeight        base.Height
+}
+
+func (cmd *RebuildPaymentDigestCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	cmd.fromHeight, cmd.toHeight = base.GenesisHeight, base.NilHeight
+
+	if h := cmd.HeightRange.From(); h != nil {
+		cmd.fromHeight = base.Height(*h)
+
+		if err := cmd.fromHeight.IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid from height; from=%d", *h)
+		}
+	}
+
+	if h := cmd.HeightRange.To(); h != nil {
+		cmd.toHeight = base.Height(*h)
+
+		if err := cmd.toHeight.IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid to height; to=%d", *h)
+		}
+
+		if cmd.fromHeight > cmd.toHeight {
+			return errors.Errorf("from height is higher than to; from=%d to=%d", cmd.fromHeight, cmd.toHeight)
+		}
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("from_height", cmd.fromHeight).
+		Interface("to_height", cmd.toHeight).
+		Bool("restart", cmd.Restart).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := newDigestPaymentPS("cmd-rebuild-payment-digest", log)
+
+	_ = pps.AddOK(PNameRebuildPaymentDigest, cmd.pRebuild, nil,
+		cdigest.PNameDigesterDataBase, launch.PNameBlockItemReaders)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *RebuildPaymentDigestCommand) pRebuild(pctx context.Context) (context.Context, error) {
+	e := util.StringError("rebuild payment digest")
+
+	var design launch.NodeDesign
+	var isaacparams *isaac.Params
+	var db isaac.Database
+	var newReaders func(context.Context, string, *isaac.BlockItemReadersArgs) (*isaac.BlockItemReaders, error)
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.DesignContextKey, &design,
+		launch.ISAACParamsContextKey, &isaacparams,
+		launch.CenterDatabaseContextKey, &db,
+		launch.NewBlockItemReadersFuncContextKey, &newReaders,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var st *cdigest.Database
+	if err := util.LoadFromContext(pctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if st == nil {
+		return pctx, e.Errorf("digest not enabled in design")
+	}
+
+	switch bm, found, err := db.LastBlockMap(); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case !found:
+		return pctx, e.Errorf("empty storage")
+	case cmd.toHeight > bm.Manifest().Height():
+		return pctx, e.Errorf("to height higher than last; to=%d last=%d", cmd.toHeight, bm.Manifest().Height())
+	case cmd.toHeight <= base.NilHeight:
+		cmd.toHeight = bm.Manifest().Height()
+	}
+
+	if cmd.fromHeight > cmd.toHeight {
+		return pctx, e.Errorf("from height is higher than last; from=%d last=%d", cmd.fromHeight, cmd.toHeight)
+	}
+
+	var prev *digest.RebuildProgress
+
+	switch i, found, err := digest.LoadRebuildProgress(st); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case found && !cmd.Restart:
+		prev = &i
+	}
+
+	progress := digest.ResumeRebuildProgress(cmd.fromHeight, cmd.toHeight, prev)
+	if progress.Height >= cmd.fromHeight {
+		cmd.log.Debug().Interface("progress", prev).Msg("resume rebuild")
+	}
+
+	readers, err := newReaders(pctx, launch.LocalFSDataDirectory(design.Storage.Base), nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	// NOTE the current collections follow the rebuilt blocks only when the
+	// rebuild reaches the last digested block.
+	current := cmd.toHeight >= st.LastBlock()
+
+	for height := progress.Height + 1; height <= cmd.toHeight; height++ {
+		if err := pctx.Err(); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		if err := cmd.rebuildBlock(pctx, st, readers, isaacparams.NetworkID(), height, current); err != nil {
+			return pctx, e.WithMessage(err, "height %d", height)
+		}
+
+		progress.Height = height
+		if err := digest.SaveRebuildProgress(st, progress); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		cmd.log.Debug().Interface("height", height).Msg("block rebuilt")
+	}
+
+	// NOTE the stats after the rebuilt blocks accumulate the rebuilt ones.
+	if err := digest.RecomputePaymentStats(pctx, st, cmd.toHeight); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Info().
+		Interface("from_height", cmd.fromHeight).
+		Interface("to_height", cmd.toHeight).
+		Bool("current", current).
+		Msg("payment digest rebuilt")
+
+	return pctx, nil
+}
+
+func (cmd *RebuildPaymentDigestCommand) rebuildBlock(
+	ctx context.Context,
+	st *cdigest.Database,
+	readers *isaac.BlockItemReaders,
+	networkID base.NetworkID,
+	height base.Height,
+	current bool,
+) error {
+	var bm base.BlockMap
+
+	switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](readers.Item, height, base.BlockItemMap, nil); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("blockmap")
+	default:
+		if err := i.IsValid(networkID); err != nil {
+			return err
+		}
+
+		bm = i
+	}
+
+	pr, ops, sts, opsTree, _, _, err := isaacblock.LoadBlockItemsFromReader(bm, readers.Item, height)
+	if err != nil {
+		return err
+	}
+
+	return digest.RebuildPaymentBlock(ctx, st, bm, pr, ops, opsTree, sts, current)
+}
+
+type ReplayPaymentWebhookCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	URL             string `name:"url" help:"webhook url; all the webhooks by default" default:""`
+	Limit           int64  `name:"limit" help:"maximum number of dead letters" default:"0"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *ReplayPaymentWebhookCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Str("url", cmd.URL).
+		Int64("limit", cmd.Limit).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := newDigestPaymentPS("cmd-replay-payment-webhook", log)
+
+	_ = pps.
+		AddOK(steps.PNameWebhookDesign, steps.PLoadWebhookDesign, nil, launch.PNameDesign).
+		AddOK(PNameReplayPaymentWebhook, cmd.pReplay, nil,
+			cdigest.PNameDigesterDataBase, steps.PNameWebhookDesign)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *ReplayPaymentWebhookCommand) pReplay(pctx context.Context) (context.Context, error) {
+	e := util.StringError("replay payment webhook")
+
+	var design digest.WebhookDesign
+	if err := util.LoadFromContext(pctx, digest.ContextValueWebhookDesign, &design); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if !design.Enabled() {
+		return pctx, e.Errorf("webhook not enabled in design")
+	}
+
+	var st *cdigest.Database
+	if err := util.LoadFromContext(pctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if st == nil {
+		return pctx, e.Errorf("digest not enabled in design")
+	}
+
+	d := digest.NewWebhookDispatcher(design, nil, nil, util.MarshalJSON)
+
+	delivered, failed, err := digest.ReplayWebhookDeadLetters(
+		pctx, d, digest.NewMongoWebhookDeadLetters(st), cmd.URL, cmd.Limit)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Info().
+		Uint64("delivered", delivered).
+		Uint64("failed", failed).
+		Msg("payment webhook dead letters replayed")
+
+	return pctx, nil
+}
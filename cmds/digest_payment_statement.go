@@ -0,0 +1,169 @@
+package cmds
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/payment-model/api"
+	"github.com/imfact-labs/payment-model/digest"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type PaymentStatementCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	Contract        string `name:"contract" help:"contract account" required:""`
+	Account         string `name:"account" help:"account" required:""`
+	Currency        string `name:"currency" help:"currency id" required:""`
+	From            string `name:"from" help:"start of the statement; the first block by default" placeholder:"unix seconds or RFC3339"`
+	To              string `name:"to" help:"end of the statement; the last block by default" placeholder:"unix seconds or RFC3339"`
+	Format          string `name:"format" help:"statement format" enum:"json,csv" default:"json"`
+	Output          string `name:"output" help:"output file; stdout by default" default:""`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+	from            uint64
+	to              uint64
+}
+
+func (cmd *PaymentStatementCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	var err error
+
+	if cmd.from, err = parseTimestampFlag(cmd.From); err != nil {
+		return errors.WithMessage(err, "invalid from")
+	}
+
+	if cmd.to, err = parseTimestampFlag(cmd.To); err != nil {
+		return errors.WithMessage(err, "invalid to")
+	}
+
+	if cmd.to > 0 && cmd.from > cmd.to {
+		return errors.Errorf("from is later than to; from=%d to=%d", cmd.from, cmd.to)
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Str("contract", cmd.Contract).
+		Str("account", cmd.Account).
+		Str("currency", cmd.Currency).
+		Uint64("from", cmd.from).
+		Uint64("to", cmd.to).
+		Str("format", cmd.Format).
+		Str("output", cmd.Output).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := newDigestPaymentPS("cmd-payment-statement", log)
+
+	_ = pps.AddOK(PNamePaymentStatement, cmd.pStatement, nil, cdigest.PNameDigesterDataBase)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err = pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+// parseTimestampFlag returns the unix seconds of the timestamp flag; 0 when it
+// is empty.
+func parseTimestampFlag(s string) (uint64, error) {
+	if s = strings.TrimSpace(s); len(s) < 1 {
+		return 0, nil
+	}
+
+	t, err := api.ParseTimestampQuery(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(t.Unix()), nil
+}
+
+func (cmd *PaymentStatementCommand) pStatement(pctx context.Context) (context.Context, error) {
+	e := util.StringError("payment statement")
+
+	var st *cdigest.Database
+	if err := util.LoadFromContext(pctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if st == nil {
+		return pctx, e.Errorf("digest not enabled in design")
+	}
+
+	va, err := digest.PaymentStatement(st, cmd.Contract, cmd.Account, cmd.Currency, cmd.from, cmd.to)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var out io.Writer = os.Stdout
+
+	if len(cmd.Output) > 0 {
+		f, err := os.Create(filepath.Clean(cmd.Output))
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		out = f
+	}
+
+	switch cmd.Format {
+	case "csv":
+		err = va.WriteCSV(out)
+	default:
+		var b []byte
+		if b, err = util.MarshalJSONIndent(va); err == nil {
+			_, err = out.Write(append(b, '\n'))
+		}
+	}
+
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if !va.Reconciled {
+		return pctx, e.Errorf(
+			"not reconciled; closing balance %v at height %d differs from the entries",
+			va.ClosingBalance, va.ToHeight)
+	}
+
+	cmd.log.Debug().
+		Interface("from_height", va.FromHeight).
+		Interface("to_height", va.ToHeight).
+		Int("entries", len(va.Entries)).
+		Msg("payment statement exported")
+
+	return pctx, nil
+}
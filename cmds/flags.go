@@ -0,0 +1,128 @@
+package cmds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/pkg/errors"
+)
+
+type DepositItemsFlag struct {
+	items []payment.DepositItem
+}
+
+func (v *DepositItemsFlag) UnmarshalText(b []byte) error {
+	arr := strings.SplitN(string(b), "@", -1)
+	for i := range arr {
+		l := strings.SplitN(arr[i], ",", 6)
+		if len(l) != 6 {
+			return fmt.Errorf("invalid deposit item, %q", arr[i])
+		}
+
+		cid := ctypes.CurrencyID(l[0])
+		if err := cid.IsValid(nil); err != nil {
+			return err
+		}
+
+		am, err := common.NewBigFromString(l[1])
+		if err != nil {
+			return errors.Wrapf(err, "invalid big string, %q", l[1])
+		}
+
+		tl, err := common.NewBigFromString(l[2])
+		if err != nil {
+			return errors.Wrapf(err, "invalid big string, %q", l[2])
+		}
+
+		var ts [3]uint64
+		for j := range ts {
+			n, err := strconv.ParseUint(l[3+j], 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid time, %q", l[3+j])
+			}
+			ts[j] = n
+		}
+
+		v.items = append(v.items, payment.NewDepositItem(cid, am, tl, ts[0], ts[1], ts[2]))
+	}
+
+	return nil
+}
+
+func (v *DepositItemsFlag) Items() []payment.DepositItem {
+	return v.items
+}
+
+type SplitReceiversFlag struct {
+	receivers []string
+	shares    []uint64
+}
+
+func (v *SplitReceiversFlag) UnmarshalText(b []byte) error {
+	arr := strings.SplitN(string(b), "@", -1)
+	for i := range arr {
+		l := strings.SplitN(arr[i], ",", 2)
+		if len(l) != 2 {
+			return fmt.Errorf("invalid split receiver, %q", arr[i])
+		}
+
+		share, err := strconv.ParseUint(l[1], 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid share, %q", l[1])
+		}
+
+		v.receivers = append(v.receivers, l[0])
+		v.shares = append(v.shares, share)
+	}
+
+	return nil
+}
+
+func (v *SplitReceiversFlag) Receivers(enc encoder.Encoder) ([]payment.SplitReceiver, error) {
+	receivers := make([]payment.SplitReceiver, len(v.receivers))
+	for i := range v.receivers {
+		a, err := base.DecodeAddress(v.receivers[i], enc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid receiver format, %q", v.receivers[i])
+		}
+
+		receivers[i] = payment.NewSplitReceiver(a, v.shares[i])
+	}
+
+	return receivers, nil
+}
+
+type CategoryLimitsFlag struct {
+	limits map[string]common.Big
+}
+
+func (v *CategoryLimitsFlag) UnmarshalText(b []byte) error {
+	v.limits = map[string]common.Big{}
+
+	arr := strings.SplitN(string(b), "@", -1)
+	for i := range arr {
+		l := strings.SplitN(arr[i], ",", 2)
+		if len(l) != 2 {
+			return fmt.Errorf("invalid category limit, %q", arr[i])
+		}
+
+		limit, err := common.NewBigFromString(l[1])
+		if err != nil {
+			return errors.Wrapf(err, "invalid limit, %q", l[1])
+		}
+
+		v.limits[l[0]] = limit
+	}
+
+	return nil
+}
+
+func (v CategoryLimitsFlag) Limits() map[string]common.Big {
+	return v.limits
+}
@@ -21,6 +21,7 @@ type NetworkClientCommand struct { //nolint:govet //...
 	//revive:disable:nested-structs
 	NodeInfo      launchcmd.NetworkClientNodeInfoCommand     `cmd:"" name:"node-info" help:"remote node info"`
 	SendOperation NetworkClientSendOperationCommand          `cmd:"" name:"send-operation" help:"send operation"`
+	Simulate      NetworkClientSimulateOperationCommand      `cmd:"" name:"simulate-operation" help:"simulate payment operation"`
 	State         launchcmd.NetworkClientStateCommand        `cmd:"" name:"state" help:"get state"`
 	LastBlockMap  launchcmd.NetworkClientLastBlockMapCommand `cmd:"" name:"last-blockmap" help:"get last blockmap"`
 	Design        struct {
@@ -0,0 +1,75 @@
+package cmds
+
+import (
+	"context"
+	"os"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	modapi "github.com/imfact-labs/payment-model/api"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/pkg/errors"
+)
+
+type NetworkClientSimulateOperationCommand struct { //nolint:govet //...
+	BaseNetworkClientCommand
+	Input     string `arg:"" name:"input" help:"input; default is stdin" default:"-"`
+	IsString  bool   `name:"input.is-string" help:"input is string, not file"`
+	Timestamp string `name:"timestamp" help:"proposed time; unix seconds or RFC3339, default is now"`
+}
+
+func (cmd *NetworkClientSimulateOperationCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	proposedAt, err := modapi.ParseTimestampQuery(cmd.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	var op base.Operation
+
+	switch i, err := launch.LoadInputFlag(cmd.Input, !cmd.IsString); {
+	case err != nil:
+		return err
+	case len(i) < 1:
+		return errors.Errorf("empty input")
+	default:
+		cmd.Log.Debug().
+			Str("input", string(i)).
+			Msg("input")
+
+		if err := encoder.Decode(cmd.Encoder, i, &op); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
+	defer cancel()
+
+	height := base.GenesisHeight
+
+	switch bm, found, err := cmd.Client.LastBlockMap(ctx, cmd.Remote.ConnInfo(), nil); {
+	case err != nil:
+		return err
+	case found:
+		height = bm.Manifest().Height() + 1
+	}
+
+	getStateFunc := func(key string) (base.State, bool, error) {
+		return cmd.Client.State(ctx, cmd.Remote.ConnInfo(), key, nil)
+	}
+
+	result, err := payment.Simulate(ctx, op, base.NetworkID(cmd.NetworkID), height, proposedAt, getStateFunc)
+	if err != nil {
+		return err
+	}
+
+	return cmd.Print(result, os.Stdout)
+}
@@ -1,9 +1,23 @@
 package cmds
 
 type PaymentCommand struct {
-	Deposit              DepositCommand           `cmd:"" name:"deposit" help:"deposit"`
-	Withdraw             WithdrawCommand          `cmd:"" name:"withdraw" help:"withdraw"`
-	Transfer             TransferCommand          `cmd:"" name:"transfer" help:"transfer"`
-	UpdateAccountSetting UpdateAccountInfoCommand `cmd:"" name:"update-account-setting" help:"update account setting"`
-	RegisterModel        RegisterModelCommand     `cmd:"" name:"register-model" help:"register payment model"`
+	Deposit              DepositCommand             `cmd:"" name:"deposit" help:"deposit"`
+	DepositItems         DepositItemsCommand        `cmd:"" name:"deposit-items" help:"deposit multiple currencies"`
+	Withdraw             WithdrawCommand            `cmd:"" name:"withdraw" help:"withdraw"`
+	WithdrawAll          WithdrawAllCommand         `cmd:"" name:"withdraw-all" help:"withdraw all currencies"`
+	Transfer             TransferCommand            `cmd:"" name:"transfer" help:"transfer"`
+	SplitTransfer        SplitTransferCommand       `cmd:"" name:"split-transfer" help:"transfer to multiple receivers by shares"`
+	UpdateAccountSetting UpdateAccountInfoCommand   `cmd:"" name:"update-account-setting" help:"update account setting"`
+	UpdateMerchant       UpdateMerchantCommand      `cmd:"" name:"update-merchant" help:"register merchant with categories"`
+	RegisterModel        RegisterModelCommand       `cmd:"" name:"register-model" help:"register payment model"`
+	OpenChannel          OpenChannelCommand         `cmd:"" name:"open-channel" help:"open payment channel"`
+	CloseChannel         CloseChannelCommand        `cmd:"" name:"close-channel" help:"close payment channel"`
+	SignVoucher          SignVoucherCommand         `cmd:"" name:"sign-voucher" help:"sign voucher of payment channel"`
+	ClaimVoucher         ClaimVoucherCommand        `cmd:"" name:"claim-voucher" help:"claim voucher of payment channel"`
+	LockTransfer         LockTransferCommand        `cmd:"" name:"lock-transfer" help:"lock transfer with hash and timeout"`
+	ClaimTransfer        ClaimTransferCommand       `cmd:"" name:"claim-transfer" help:"claim locked transfer with preimage"`
+	RefundTransfer       RefundTransferCommand      `cmd:"" name:"refund-transfer" help:"refund locked transfer after timeout"`
+	RegisterDepositKeys  RegisterDepositKeysCommand `cmd:"" name:"register-deposit-keys" help:"register lock key and recovery key of deposit"`
+	LockDeposit          LockDepositCommand         `cmd:"" name:"lock-deposit" help:"lock deposit with lock key"`
+	UnlockDeposit        UnlockDepositCommand       `cmd:"" name:"unlock-deposit" help:"unlock deposit with recovery key"`
 }
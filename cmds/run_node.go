@@ -2,6 +2,8 @@ package cmds
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	apic "github.com/imfact-labs/currency-model/api"
 	ccmds "github.com/imfact-labs/currency-model/app/cmds"
@@ -12,6 +14,7 @@ import (
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/logging"
 	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/imfact-labs/payment-model/api"
 	"github.com/imfact-labs/payment-model/digest"
 	"github.com/imfact-labs/payment-model/runtime/steps"
 	"github.com/pkg/errors"
@@ -19,6 +22,11 @@ import (
 
 type RunCommand struct { //nolint:govet //...
 	ccmds.RunCommand
+	NoPaymentHistory        bool          `name:"no-payment-history" help:"keep only the current payment designs and deposit records in digest"`
+	PaymentRetentionHeights uint64        `name:"payment-retention-heights" help:"keep the payment history of the last heights in digest"`
+	PaymentRetentionSince   string        `name:"payment-retention-since" help:"keep the payment history after the time in digest" placeholder:"unix seconds or RFC3339"`
+	PaymentSnapshotInterval uint64        `name:"payment-snapshot-interval" help:"keep the pruned payment history at every interval heights"`
+	PaymentPruneInterval    time.Duration `name:"payment-prune-interval" help:"interval to prune the payment history" default:"10m"`
 }
 
 func (cmd *RunCommand) Run(pctx context.Context) error {
@@ -35,8 +43,20 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		Interface("http_state", cmd.HTTPState).
 		Interface("dev", cmd.DevFlags).
 		Interface("acl", cmd.ACLFlags).
+		Bool("no_payment_history", cmd.NoPaymentHistory).
+		Uint64("payment_retention_heights", cmd.PaymentRetentionHeights).
+		Str("payment_retention_since", cmd.PaymentRetentionSince).
+		Uint64("payment_snapshot_interval", cmd.PaymentSnapshotInterval).
+		Dur("payment_prune_interval", cmd.PaymentPruneInterval).
 		Msg("flags")
 
+	digest.SetPaymentHistory(!cmd.NoPaymentHistory)
+
+	retention, err := cmd.retentionPolicy()
+	if err != nil {
+		return err
+	}
+
 	cmd.RunCommand.SetLog(log.Log())
 
 	if len(cmd.HTTPState) > 0 {
@@ -51,13 +71,22 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		launch.DiscoveryFlagContextKey: cmd.Discovery,
 		launch.PrivatekeyContextKey:    string(cmd.PrivatekeyFlags.Flag.Body()),
 		launch.ACLFlagsContextKey:      cmd.ACLFlags,
+
+		digest.ContextValuePaymentRetention: retention,
 	})
 
 	pps := cpipeline.DefaultRunPS()
 	registry := mustBuildModuleRegistry()
 
 	_ = pps.AddOK(cdigest.PNameDigester, digest.ProcessDigester, nil, cdigest.PNameDigesterDataBase).
-		AddOK(cdigest.PNameStartDigester, cdigest.ProcessStartDigester, nil, apic.PNameStartAPI)
+		AddOK(steps.PNameWebhookDesign, steps.PLoadWebhookDesign, nil, launch.PNameDesign).
+		AddOK(digest.PNameStartWebhookDispatcher,
+			digest.ProcessStartWebhookDispatcher, digest.ProcessCloseWebhookDispatcher,
+			cdigest.PNameDigesterDataBase, steps.PNameWebhookDesign).
+		AddOK(cdigest.PNameStartDigester, cdigest.ProcessStartDigester, nil,
+			apic.PNameStartAPI, digest.PNameStartWebhookDispatcher).
+		AddOK(digest.PNameStartPaymentPruner,
+			digest.ProcessStartPaymentPruner, digest.ProcessClosePaymentPruner, cdigest.PNameStartDigester)
 	_ = pps.POK(launch.PNameStorage).PostAddOK(ps.Name("check-hold"), cmd.RunCommand.PCheckHold)
 	pstates := pps.POK(launch.PNameStates)
 	entries := registry.Entries()
@@ -87,7 +116,7 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 
 	log.Log().Debug().Interface("process", pps.Verbose()).Msg("process ready")
 
-	nctx, err := pps.Run(nctx) //revive:disable-line:modifies-parameter
+	nctx, err = pps.Run(nctx) //revive:disable-line:modifies-parameter
 	defer func() {
 		log.Log().Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
 
@@ -108,6 +137,25 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 	return cmd.RunCommand.RunNode(nctx)
 }
 
+func (cmd *RunCommand) retentionPolicy() (digest.RetentionPolicy, error) {
+	policy := digest.RetentionPolicy{
+		Heights:          cmd.PaymentRetentionHeights,
+		SnapshotInterval: cmd.PaymentSnapshotInterval,
+		Interval:         cmd.PaymentPruneInterval,
+	}
+
+	if s := strings.TrimSpace(cmd.PaymentRetentionSince); len(s) > 0 {
+		t, err := api.ParseTimestampQuery(s)
+		if err != nil {
+			return policy, errors.WithMessage(err, "invalid payment-retention-since")
+		}
+
+		policy.Since = uint64(t.Unix())
+	}
+
+	return policy, nil
+}
+
 func (cmd *RunCommand) pDigestAPIHandlers(ctx context.Context) (context.Context, error) {
 	var params *launch.LocalParams
 	var local base.LocalNode
@@ -0,0 +1,76 @@
+package cmds
+
+import (
+	"context"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+type SignVoucherCommand struct {
+	BaseCommand
+	Privatekey ccmds.PrivatekeyFlag `arg:"" name:"privatekey" help:"privatekey of depositor" required:"true"`
+	Contract   ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
+	Depositor  ccmds.AddressFlag    `arg:"" name:"depositor" help:"depositor address" required:"true"`
+	Receiver   ccmds.AddressFlag    `arg:"" name:"receiver" help:"receiver address" required:"true"`
+	Nonce      uint64               `arg:"" name:"nonce" help:"nonce of channel" required:"true"`
+	Amount     ccmds.BigFlag        `arg:"" name:"amount" help:"cumulative amount" required:"true"`
+	Currency   ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
+	NetworkID  ccmds.NetworkIDFlag  `name:"network-id" help:"network-id" required:"true" default:"${network_id}"`
+	contract   base.Address
+	depositor  base.Address
+	receiver   base.Address
+}
+
+func (cmd *SignVoucherCommand) Run(pctx context.Context) error { // nolint:dupl
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	e := util.StringError("failed to sign voucher")
+
+	voucher := types.NewVoucher(cmd.contract, cmd.depositor, cmd.receiver, cmd.Currency.CID, cmd.Nonce, cmd.Amount.Big)
+	if err := voucher.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+		return e.Wrap(err)
+	}
+
+	ccmds.PrettyPrint(cmd.Out, voucher)
+
+	return nil
+}
+
+func (cmd *SignVoucherCommand) parseFlags() error {
+	if err := cmd.NetworkID.NetworkID().IsValid(nil); err != nil {
+		return err
+	}
+
+	a, err := cmd.Contract.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid contract format, %q", cmd.Contract)
+	} else {
+		cmd.contract = a
+	}
+
+	a, err = cmd.Depositor.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid depositor format, %q", cmd.Depositor)
+	} else {
+		cmd.depositor = a
+	}
+
+	a, err = cmd.Receiver.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid receiver format, %q", cmd.Receiver)
+	} else {
+		cmd.receiver = a
+	}
+
+	return nil
+}
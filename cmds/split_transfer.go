@@ -0,0 +1,93 @@
+package cmds
+
+import (
+	"context"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/pkg/errors"
+)
+
+type SplitTransferCommand struct {
+	BaseCommand
+	ccmds.OperationFlags
+	Sender    ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
+	Contract  ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
+	Amount    ccmds.BigFlag        `arg:"" name:"amount" help:"total amount" required:"true"`
+	Receivers SplitReceiversFlag   `arg:"" name:"receivers" help:"receivers (ex: \"<receiver>,<share in basis points>\") separator @" required:"true"`
+	Currency  ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
+	sender    base.Address
+	contract  base.Address
+	receivers []payment.SplitReceiver
+}
+
+func (cmd *SplitTransferCommand) Run(pctx context.Context) error { // nolint:dupl
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	op, err := cmd.createOperation()
+	if err != nil {
+		return err
+	}
+
+	ccmds.PrettyPrint(cmd.Out, op)
+
+	return nil
+}
+
+func (cmd *SplitTransferCommand) parseFlags() error {
+	if err := cmd.OperationFlags.IsValid(nil); err != nil {
+		return err
+	}
+
+	a, err := cmd.Sender.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid sender format, %q", cmd.Sender)
+	} else {
+		cmd.sender = a
+	}
+
+	a, err = cmd.Contract.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid contract format, %q", cmd.Contract)
+	} else {
+		cmd.contract = a
+	}
+
+	receivers, err := cmd.Receivers.Receivers(cmd.Encoders.JSON())
+	if err != nil {
+		return err
+	}
+	cmd.receivers = receivers
+
+	return nil
+}
+
+func (cmd *SplitTransferCommand) createOperation() (base.Operation, error) { // nolint:dupl
+	e := util.StringError("failed to create split-transfer operation")
+
+	fact := payment.NewSplitTransferFact(
+		[]byte(cmd.Token), cmd.sender, cmd.contract, cmd.Amount.Big, cmd.receivers, cmd.Currency.CID,
+	)
+	if err := fact.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	op, err := payment.NewSplitTransfer(fact)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+	err = op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID())
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return op, nil
+}
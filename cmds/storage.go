@@ -8,4 +8,5 @@ type Storage struct { //nolint:govet //...
 	ValidateBlocks ValidateBlocksCommand          `cmd:"" help:"validate blocks in storage"`
 	Status         launchcmd.StorageStatusCommand `cmd:"" help:"storage status"`
 	Database       launchcmd.DatabaseCommand      `cmd:"" help:""`
+	DigestPayment  DigestPaymentCommand           `cmd:"" name:"digest-payment" help:"payment digest"`
 }
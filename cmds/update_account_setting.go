@@ -4,6 +4,7 @@ import (
 	"context"
 
 	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/payment-model/operation/payment"
@@ -13,15 +14,17 @@ import (
 type UpdateAccountInfoCommand struct {
 	BaseCommand
 	ccmds.OperationFlags
-	Sender        ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
-	Contract      ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
-	TransferLimit ccmds.BigFlag        `arg:"" name:"transfer limit" help:"transfer limit" required:"true"`
-	StartTime     uint64               `arg:"" name:"start time" help:"start time" required:"true"`
-	EndTime       uint64               `arg:"" name:"end time" help:"end time" required:"true"`
-	Duration      uint64               `arg:"" name:"duration" help:"duration" required:"true"`
-	Currency      ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
-	sender        base.Address
-	contract      base.Address
+	Sender         ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
+	Contract       ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
+	TransferLimit  ccmds.BigFlag        `arg:"" name:"transfer limit" help:"transfer limit" required:"true"`
+	StartTime      uint64               `arg:"" name:"start time" help:"start time" required:"true"`
+	EndTime        uint64               `arg:"" name:"end time" help:"end time" required:"true"`
+	Duration       uint64               `arg:"" name:"duration" help:"duration" required:"true"`
+	Currency       ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
+	CategoryLimits CategoryLimitsFlag   `name:"category-limits" help:"limits by merchant category (ex: \"<category>,<limit>\") separator @"`
+	ClearLimits    bool                 `name:"clear-category-limits" help:"clear the limits by merchant category"`
+	sender         base.Address
+	contract       base.Address
 }
 
 func (cmd *UpdateAccountInfoCommand) Run(pctx context.Context) error { // nolint:dupl
@@ -48,6 +51,10 @@ func (cmd *UpdateAccountInfoCommand) parseFlags() error {
 		return err
 	}
 
+	if cmd.ClearLimits && cmd.CategoryLimits.Limits() != nil {
+		return errors.Errorf("category limits with clearing category limits")
+	}
+
 	a, err := cmd.Sender.Encode(cmd.Encoders.JSON())
 	if err != nil {
 		return errors.Wrapf(err, "invalid sender format, %q", cmd.Sender)
@@ -68,8 +75,14 @@ func (cmd *UpdateAccountInfoCommand) parseFlags() error {
 func (cmd *UpdateAccountInfoCommand) createOperation() (base.Operation, error) { // nolint:dupl
 	e := util.StringError("failed to create update account setting operation")
 
+	// NOTE without category limits, the current limits are kept.
+	limits := cmd.CategoryLimits.Limits()
+	if cmd.ClearLimits {
+		limits = map[string]common.Big{}
+	}
+
 	fact := payment.NewUpdateAccountSettingFact([]byte(cmd.Token), cmd.sender, cmd.contract, cmd.TransferLimit.Big,
-		cmd.StartTime, cmd.EndTime, cmd.Duration, cmd.Currency.CID)
+		cmd.StartTime, cmd.EndTime, cmd.Duration, limits, cmd.Currency.CID)
 
 	op, err := payment.NewUpdateAccountSetting(fact)
 	if err != nil {
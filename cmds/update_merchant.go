@@ -0,0 +1,96 @@
+package cmds
+
+import (
+	"context"
+	"strings"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/pkg/errors"
+)
+
+type UpdateMerchantCommand struct {
+	BaseCommand
+	ccmds.OperationFlags
+	Sender     ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
+	Contract   ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address" required:"true"`
+	Merchant   ccmds.AddressFlag    `arg:"" name:"merchant" help:"merchant address" required:"true"`
+	Currency   ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id for fee" required:"true"`
+	Categories string               `name:"categories" help:"categories of merchant (ex: \"food,travel\")"`
+	sender     base.Address
+	contract   base.Address
+	merchant   base.Address
+	categories []string
+}
+
+func (cmd *UpdateMerchantCommand) Run(pctx context.Context) error { // nolint:dupl
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	op, err := cmd.createOperation()
+	if err != nil {
+		return err
+	}
+
+	ccmds.PrettyPrint(cmd.Out, op)
+
+	return nil
+}
+
+func (cmd *UpdateMerchantCommand) parseFlags() error {
+	if err := cmd.OperationFlags.IsValid(nil); err != nil {
+		return err
+	}
+
+	a, err := cmd.Sender.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid sender format, %q", cmd.Sender)
+	} else {
+		cmd.sender = a
+	}
+
+	a, err = cmd.Contract.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid contract format, %q", cmd.Contract)
+	} else {
+		cmd.contract = a
+	}
+
+	a, err = cmd.Merchant.Encode(cmd.Encoders.JSON())
+	if err != nil {
+		return errors.Wrapf(err, "invalid merchant format, %q", cmd.Merchant)
+	} else {
+		cmd.merchant = a
+	}
+
+	if len(cmd.Categories) > 0 {
+		cmd.categories = strings.Split(cmd.Categories, ",")
+	}
+
+	return nil
+}
+
+func (cmd *UpdateMerchantCommand) createOperation() (base.Operation, error) { // nolint:dupl
+	e := util.StringError("failed to create update-merchant operation")
+
+	fact := payment.NewUpdateMerchantFact(
+		[]byte(cmd.Token), cmd.sender, cmd.contract, cmd.merchant, cmd.categories, cmd.Currency.CID)
+
+	op, err := payment.NewUpdateMerchant(fact)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+	err = op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID())
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return op, nil
+}
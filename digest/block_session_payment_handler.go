@@ -1,14 +1,37 @@
 package digest
 
 import (
+	"sync/atomic"
+
 	cdigest "github.com/imfact-labs/currency-model/digest"
+	cstate "github.com/imfact-labs/currency-model/state"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/payment-model/state"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+var paymentHistory atomic.Bool
+
+func init() {
+	paymentHistory.Store(true)
+}
+
+// SetPaymentHistory sets whether the designs and the deposit records of every
+// height are kept in the digest. Without the history only the current
+// collections are updated and the queries by height are not served.
+func SetPaymentHistory(b bool) {
+	paymentHistory.Store(b)
+}
+
+func PaymentHistory() bool {
+	return paymentHistory.Load()
+}
+
 func PreparePayment(bs *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
 	switch {
+	case (state.IsDesignStateKey(st.Key()) || state.IsDepositRecordStateKey(st.Key())) && !PaymentHistory():
+		return "", nil, nil
 	case state.IsDesignStateKey(st.Key()):
 		j, err := handlePaymentDesignState(bs, st)
 		if err != nil {
@@ -23,11 +46,88 @@ func PreparePayment(bs *cdigest.BlockSession, st base.State) (string, []mongo.Wr
 		}
 
 		return DefaultColNamePaymentAccount, j, nil
+	case state.IsEventLogStateKey(st.Key()):
+		j, err := handlePaymentEventLogState(st)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return DefaultColNamePaymentEvent, j, nil
+	case state.IsLockStateKey(st.Key()):
+		j, err := handlePaymentLockState(bs, st)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return DefaultColNamePaymentLock, j, nil
 	}
 
 	return "", nil, nil
 }
 
+// PreparePaymentCurrent replaces the current design and deposit record
+// documents with the ones of the state unless the current ones are newer, so
+// the latest ones are looked up by the contract account and the account
+// without sorting the history.
+func PreparePaymentCurrent(bs *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
+	switch {
+	case state.IsDesignStateKey(st.Key()):
+		doc, err := NewDesignDoc(st, bs.Database().Encoder())
+		if err != nil {
+			return "", nil, err
+		}
+
+		parsedKey, err := cstate.ParseStateKey(st.Key(), state.PaymentStateKeyPrefix, 3)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return DefaultColNamePaymentCurrent, []mongo.WriteModel{
+			currentDocUpdate(bson.D{{Key: "contract", Value: parsedKey[1]}}, doc, st.Height()),
+		}, nil
+	case state.IsDepositRecordStateKey(st.Key()):
+		doc, err := NewDepositRecordDoc(st, bs.Database().Encoder())
+		if err != nil {
+			return "", nil, err
+		}
+
+		parsedKey, err := cstate.ParseStateKey(st.Key(), state.PaymentStateKeyPrefix, 4)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return DefaultColNamePaymentAccountCurrent, []mongo.WriteModel{
+			currentDocUpdate(bson.D{
+				{Key: "contract", Value: parsedKey[1]},
+				{Key: "address", Value: doc.record.Address().String()},
+			}, doc, st.Height()),
+		}, nil
+	}
+
+	return "", nil, nil
+}
+
+// currentDocUpdate replaces the current document matched by filter with doc
+// of height; the current document of the higher height is kept, so digesting
+// or rebuilding the past blocks again does not move the current state back.
+func currentDocUpdate(filter bson.D, doc interface{}, height base.Height) mongo.WriteModel {
+	newer := bson.D{{Key: "$gt", Value: bson.A{
+		bson.D{{Key: "$ifNull", Value: bson.A{"$height", base.NilHeight.Int64()}}},
+		height.Int64(),
+	}}}
+
+	return mongo.NewUpdateOneModel().
+		SetFilter(filter).
+		SetUpdate(mongo.Pipeline{
+			{{Key: "$replaceWith", Value: bson.D{{Key: "$cond", Value: bson.A{
+				newer,
+				"$$ROOT",
+				bson.D{{Key: "$literal", Value: doc}},
+			}}}}},
+		}).
+		SetUpsert(true)
+}
+
 func handlePaymentDesignState(bs *cdigest.BlockSession, st base.State) ([]mongo.WriteModel, error) {
 	if serviceDesignDoc, err := NewDesignDoc(st, bs.Database().Encoder()); err != nil {
 		return nil, err
@@ -47,3 +147,50 @@ func handlePaymentAccountRecordState(bs *cdigest.BlockSession, st base.State) ([
 		}, nil
 	}
 }
+
+func handlePaymentLockState(bs *cdigest.BlockSession, st base.State) ([]mongo.WriteModel, error) {
+	if lockDoc, err := NewLockDoc(st, bs.Database().Encoder()); err != nil {
+		return nil, err
+	} else {
+		return []mongo.WriteModel{
+			mongo.NewInsertOneModel().SetDocument(lockDoc),
+		}, nil
+	}
+}
+
+// handlePaymentEventLogState indexes only the events appended in the height of
+// the state; the older events were indexed with the previous states. The
+// events are published to the subscribers of PaymentEventBroker after the
+// block is committed.
+func handlePaymentEventLogState(st base.State) ([]mongo.WriteModel, error) {
+	log, err := state.GetEventLogFromState(st)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedKey, err := cstate.ParseStateKey(st.Key(), state.PaymentStateKeyPrefix, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, address := parsedKey[1], log.Address().String()
+
+	var models []mongo.WriteModel
+	for _, ev := range log.Events() {
+		if ev.Height != st.Height().Int64() {
+			continue
+		}
+
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{
+				{Key: "contract", Value: contract},
+				{Key: "address", Value: address},
+				{Key: "sequence", Value: ev.Sequence},
+			}).
+			SetReplacement(NewEventDoc(contract, address, ev)).
+			SetUpsert(true),
+		)
+	}
+
+	return models, nil
+}
@@ -0,0 +1,65 @@
+package digest
+
+import (
+	"testing"
+
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestPreparePaymentWithoutHistory(t *testing.T) {
+	defer SetPaymentHistory(PaymentHistory())
+
+	SetPaymentHistory(false)
+
+	account := ctypes.NewStringAddress("account")
+
+	// NOTE the block session is not used without the history.
+	if col, models, err := PreparePayment(nil, depositRecordState(account, 3, 10)); err != nil || len(col) > 0 || len(models) > 0 {
+		t.Errorf("deposit record history prepared, %q, %v", col, err)
+	}
+
+	col, models, err := PreparePayment(nil, eventLogStateAt(account,
+		newTestStatsEvent(types.EventTypeDeposit, 10, 2),
+		newTestStatsEvent(types.EventTypeTransfer, 5, 3),
+	))
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case col != DefaultColNamePaymentEvent:
+		t.Errorf("collection %q", col)
+	case len(models) != 1:
+		t.Errorf("%d events of height prepared", len(models))
+	}
+}
+
+func TestCurrentDocUpdate(t *testing.T) {
+	filter := bson.D{{Key: "contract", Value: "contract"}}
+
+	m, ok := currentDocUpdate(filter, bson.M{"height": int64(3)}, base.Height(3)).(*mongo.UpdateOneModel)
+	switch {
+	case !ok:
+		t.Fatal("not UpdateOneModel")
+	case m.Upsert == nil || !*m.Upsert:
+		t.Error("not upsert")
+	}
+
+	pipeline, ok := m.Update.(mongo.Pipeline)
+	if !ok || len(pipeline) != 1 {
+		t.Fatalf("update %v", m.Update)
+	}
+
+	cond := pipeline[0][0].Value.(bson.D)[0].Value.(bson.A)
+
+	// NOTE the current document of the higher height is kept.
+	newer := cond[0].(bson.D)[0]
+	switch {
+	case newer.Key != "$gt", newer.Value.(bson.A)[1] != int64(3):
+		t.Errorf("condition %v", newer)
+	case cond[1] != "$$ROOT":
+		t.Errorf("kept %v", cond[1])
+	}
+}
@@ -0,0 +1,130 @@
+package digest
+
+import (
+	"context"
+	"sync"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	utilc "github.com/imfact-labs/currency-model/digest/util"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// EventBroker delivers the payment events prepared by the digester to the
+// subscribers in the same process.
+type EventBroker struct {
+	sync.RWMutex
+	subs map[uint64]*eventSubscription
+	next uint64
+}
+
+type eventSubscription struct {
+	contract string
+	filter   EventFilter
+	ch       chan EventValue
+}
+
+func NewEventBroker() *EventBroker {
+	return &EventBroker{subs: map[uint64]*eventSubscription{}}
+}
+
+var paymentEventBroker = NewEventBroker()
+
+// PaymentEventBroker returns the broker fed by PublishPaymentEvents.
+func PaymentEventBroker() *EventBroker {
+	return paymentEventBroker
+}
+
+// Subscribe returns the channel of the events of the contract account which
+// match the filter and the function to cancel it; of all the contract
+// accounts with the empty contract. The channel is closed when
+// the subscriber does not receive the events in time; it should subscribe
+// again from the last event received.
+func (b *EventBroker) Subscribe(contract string, f EventFilter, size int) (<-chan EventValue, func()) {
+	b.Lock()
+	defer b.Unlock()
+
+	id := b.next
+	b.next++
+
+	sub := &eventSubscription{contract: contract, filter: f, ch: make(chan EventValue, size)}
+	b.subs[id] = sub
+
+	return sub.ch, func() {
+		b.Lock()
+		defer b.Unlock()
+
+		if _, found := b.subs[id]; found {
+			delete(b.subs, id)
+			close(sub.ch)
+		}
+	}
+}
+
+func (b *EventBroker) Publish(va EventValue) {
+	b.Lock()
+	defer b.Unlock()
+
+	for id, sub := range b.subs {
+		if (len(sub.contract) > 0 && sub.contract != va.Contract()) || !sub.filter.match(va.Address(), va.Event()) {
+			continue
+		}
+
+		select {
+		case sub.ch <- va:
+		default:
+			delete(b.subs, id)
+			close(sub.ch)
+		}
+	}
+}
+
+// PublishPaymentEvents publishes the events of the block of height, which are
+// loaded from the digest, to the subscribers of PaymentEventBroker.
+func PublishPaymentEvents(db *cdigest.Database, height base.Height) error {
+	return db.MongoClient().Find(
+		context.Background(),
+		DefaultColNamePaymentEvent,
+		bson.M{"height": height.Int64()},
+		func(cursor *mongo.Cursor) (bool, error) {
+			va, err := loadEventValue(cursor.Decode)
+			if err != nil {
+				return false, err
+			}
+
+			PaymentEventBroker().Publish(va)
+
+			return true, nil
+		},
+		options.Find().SetSort(utilc.NewBSONFilter("contract", 1).Add("address", 1).Add("sequence", 1).D()),
+	)
+}
+
+// publishDigestedPaymentEvents publishes the events of the blocks reported by
+// the digester; the digester reports the block after it is committed, so only
+// the events stored in the digest are published. The block digested again is
+// published again, so the subscribers should skip the events already
+// received.
+func publishDigestedPaymentEvents(
+	ctx context.Context, db *cdigest.Database, digested <-chan error, log *logging.Logging,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-digested:
+			var de cdigest.DigestError
+			if !errors.As(err, &de) || de.IsError() {
+				continue
+			}
+
+			if err := PublishPaymentEvents(db, de.Height()); err != nil {
+				log.Log().Error().Err(err).Int64("height", de.Height().Int64()).Msg("failed to publish payment events")
+			}
+		}
+	}
+}
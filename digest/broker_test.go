@@ -0,0 +1,131 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func newTestBrokerEvent(contract, address, t string, height base.Height) EventValue {
+	return NewEventValue(contract, address, newTestStatsEvent(t, 10, height))
+}
+
+func receiveEvents(ch <-chan EventValue) ([]EventValue, bool) {
+	var vas []EventValue
+
+	for {
+		select {
+		case va, ok := <-ch:
+			if !ok {
+				return vas, true
+			}
+
+			vas = append(vas, va)
+		default:
+			return vas, false
+		}
+	}
+}
+
+func TestEventBroker(t *testing.T) {
+	b := NewEventBroker()
+
+	f := NewEventFilter()
+	f.Types = []string{types.EventTypeTransfer}
+
+	filtered, cancelFiltered := b.Subscribe("contracta", f, 10)
+	all, cancelAll := b.Subscribe("", NewEventFilter(), 10)
+
+	b.Publish(newTestBrokerEvent("contracta", "accounta", types.EventTypeTransfer, 2))
+	b.Publish(newTestBrokerEvent("contracta", "accounta", types.EventTypeDeposit, 2))
+	b.Publish(newTestBrokerEvent("contractb", "accounta", types.EventTypeTransfer, 2))
+
+	vas, closed := receiveEvents(filtered)
+	switch {
+	case closed:
+		t.Fatal("closed")
+	case len(vas) != 1:
+		t.Fatalf("%d events", len(vas))
+	case vas[0].Contract() != "contracta", vas[0].Event().Type != types.EventTypeTransfer:
+		t.Errorf("event %v", vas[0])
+	}
+
+	if vas, _ := receiveEvents(all); len(vas) != 3 {
+		t.Errorf("%d events of all the contract accounts", len(vas))
+	}
+
+	cancelFiltered()
+	cancelFiltered()
+
+	if _, closed := receiveEvents(filtered); !closed {
+		t.Error("not closed by cancel")
+	}
+
+	b.Publish(newTestBrokerEvent("contracta", "accounta", types.EventTypeTransfer, 3))
+
+	if vas, _ := receiveEvents(all); len(vas) != 1 {
+		t.Errorf("%d events after cancel of other", len(vas))
+	}
+
+	cancelAll()
+}
+
+func TestEventBrokerSlowSubscriber(t *testing.T) {
+	b := NewEventBroker()
+
+	slow, cancel := b.Subscribe("contract", NewEventFilter(), 2)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		b.Publish(newTestBrokerEvent("contract", "account", types.EventTypeTransfer, base.Height(i)))
+	}
+
+	// NOTE the subscriber which misses the event is closed; it subscribes
+	// again from the last event received.
+	vas, closed := receiveEvents(slow)
+	switch {
+	case !closed:
+		t.Error("slow subscriber not closed")
+	case len(vas) != 2:
+		t.Errorf("%d events", len(vas))
+	}
+
+	if len(b.subs) > 0 {
+		t.Error("slow subscriber not removed")
+	}
+}
+
+func TestEventValueCompare(t *testing.T) {
+	ev := newTestStatsEvent(types.EventTypeTransfer, 1, 3)
+	ev.Sequence = 5
+
+	va := NewEventValue("contract", "accountb", ev)
+
+	height, address, sequence, err := ParseEventOffset(va.Offset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if va.Compare(height, address, sequence) != 0 {
+		t.Error("not same with offset")
+	}
+
+	for name, c := range map[string]struct {
+		height   base.Height
+		address  string
+		sequence uint64
+		r        int
+	}{
+		"lower height":    {2, "accountc", 9, 1},
+		"higher height":   {4, "accounta", 0, -1},
+		"lower address":   {3, "accounta", 9, 1},
+		"higher address":  {3, "accountc", 0, -1},
+		"lower sequence":  {3, "accountb", 4, 1},
+		"higher sequence": {3, "accountb", 6, -1},
+	} {
+		if r := va.Compare(c.height, c.address, c.sequence); r != c.r {
+			t.Errorf("%s: %d", name, r)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package digest
 
 import (
+	"context"
+	"time"
+
 	cdigest "github.com/imfact-labs/currency-model/digest"
 	utilc "github.com/imfact-labs/currency-model/digest/util"
 	"github.com/imfact-labs/mitum2/base"
@@ -8,17 +11,48 @@ import (
 	"github.com/imfact-labs/payment-model/state"
 	"github.com/imfact-labs/payment-model/types"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+var maxLimit int64 = 50
+
+var errHistoryNotKept = utilm.ErrNotFound.Errorf("payment history not kept in digest")
+
 var (
-	DefaultColNamePayment        = "digest_pmt"
-	DefaultColNamePaymentAccount = "digest_pmt_ac"
+	DefaultColNamePayment               = "digest_pmt"
+	DefaultColNamePaymentCurrent        = "digest_pmt_cur"
+	DefaultColNamePaymentAccount        = "digest_pmt_ac"
+	DefaultColNamePaymentAccountCurrent = "digest_pmt_ac_cur"
+	DefaultColNamePaymentEvent          = "digest_pmt_ev"
+	DefaultColNamePaymentLock           = "digest_pmt_lock"
 )
 
 func PaymentDesign(db *cdigest.Database, contract string) (*types.Design, base.State, error) {
+	return PaymentDesignByHeight(db, contract, base.NilHeight)
+}
+
+// PaymentDesignByHeight returns the design as of the block of height; the
+// latest design when height is base.NilHeight. The latest design is looked up
+// from the current collection and the others from the history.
+func PaymentDesignByHeight(
+	db *cdigest.Database, contract string, height base.Height,
+) (*types.Design, base.State, error) {
+	col := DefaultColNamePaymentCurrent
 	filter := utilc.NewBSONFilter("contract", contract)
+	if height > base.NilHeight {
+		if !PaymentHistory() {
+			return nil, nil, errHistoryNotKept
+		}
+
+		if err := checkPrunedHeight(db, height); err != nil {
+			return nil, nil, err
+		}
+
+		col = DefaultColNamePayment
+		filter = filter.Add("height", bson.M{"$lte": height})
+	}
 	q := filter.D()
 
 	opt := options.FindOne().SetSort(
@@ -26,7 +60,7 @@ func PaymentDesign(db *cdigest.Database, contract string) (*types.Design, base.S
 	)
 	var sta base.State
 	if err := db.MongoClient().GetByFilter(
-		DefaultColNamePayment,
+		col,
 		q,
 		func(res *mongo.SingleResult) error {
 			i, err := cdigest.LoadState(res.Decode, db.Encoders())
@@ -52,20 +86,112 @@ func PaymentDesign(db *cdigest.Database, contract string) (*types.Design, base.S
 	}
 }
 
+func PaymentLock(db *cdigest.Database, contract, hashLock string) (*types.Lock, base.State, error) {
+	filter := utilc.NewBSONFilter("contract", contract)
+	filter = filter.Add("hash_lock", hashLock)
+	q := filter.D()
+
+	opt := options.FindOne().SetSort(
+		utilc.NewBSONFilter("height", -1).D(),
+	)
+	var sta base.State
+	if err := db.MongoClient().GetByFilter(
+		DefaultColNamePaymentLock,
+		q,
+		func(res *mongo.SingleResult) error {
+			i, err := cdigest.LoadState(res.Decode, db.Encoders())
+			if err != nil {
+				return err
+			}
+			sta = i
+			return nil
+		},
+		opt,
+	); err != nil {
+		return nil, nil, utilm.ErrNotFound.WithMessage(
+			err, "payment lock by contract account %v, hash lock %v", contract, hashLock)
+	}
+
+	if sta == nil {
+		return nil, nil, errors.Errorf("state is nil")
+	}
+
+	lock, err := state.GetLockFromState(sta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return lock, sta, nil
+}
+
 func AccountInfo(db *cdigest.Database, contract, account string) (*AccountInfoValue, error) {
+	return AccountInfoByHeight(db, contract, account, base.NilHeight)
+}
+
+// AccountInfoByHeight returns the setting and the deposit record of the
+// account as of the block of height; the latest ones when height is
+// base.NilHeight.
+func AccountInfoByHeight(
+	db *cdigest.Database, contract, account string, height base.Height,
+) (*AccountInfoValue, error) {
+	var heightFilter bson.M
+	if height > base.NilHeight {
+		if !PaymentHistory() {
+			return nil, errHistoryNotKept
+		}
+
+		if err := checkPrunedHeight(db, height); err != nil {
+			return nil, err
+		}
+
+		heightFilter = bson.M{"$lte": height}
+	}
+
+	accountRecord, err := lastDepositRecord(db, contract, account, heightFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	design, _, err := PaymentDesignByHeight(db, contract, height)
+	if err != nil {
+		return nil, err
+	}
+
+	accountInfo := design.AccountSetting(account)
+	if accountInfo == nil {
+		return nil, utilm.ErrNotFound.Errorf(
+			"payment account info not found by contract account %s, account %s", contract, account)
+	}
+
+	// NOTE the allowances are left empty when the block time is not known.
+	now, _ := BlockTime(db, height)
+
+	accountInfoValue := NewAccountInfoValue(*accountInfo, *accountRecord, now)
+	return &accountInfoValue, nil
+}
+
+// lastDepositRecord returns the last deposit record of the account which
+// matches the height filter; the empty record when nothing matched. Without
+// the height filter or the history, only the current record is matched.
+func lastDepositRecord(db *cdigest.Database, contract, account string, height bson.M) (*types.DepositRecord, error) {
+	col := DefaultColNamePaymentAccountCurrent
 	filter := utilc.NewBSONFilter("contract", contract)
 	filter = filter.Add("address", account)
+	if height != nil {
+		if PaymentHistory() {
+			col = DefaultColNamePaymentAccount
+		}
+
+		filter = filter.Add("height", height)
+	}
 	q := filter.D()
 
 	opt := options.FindOne().SetSort(
 		utilc.NewBSONFilter("height", -1).D(),
 	)
 	var st base.State
-	var design *types.Design
-	var accountRecord *types.DepositRecord
-	var err error
 	if err := db.MongoClient().GetByFilter(
-		DefaultColNamePaymentAccount,
+		col,
 		q,
 		func(res *mongo.SingleResult) error {
 			i, err := cdigest.LoadState(res.Decode, db.Encoders())
@@ -79,33 +205,214 @@ func AccountInfo(db *cdigest.Database, contract, account string) (*AccountInfoVa
 	); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			emptyAccountRecord := types.NewEmptyDepositRecord()
-			accountRecord = &emptyAccountRecord
-		} else {
-			return nil,
-				utilm.ErrNotFound.WithMessage(
-					err, "payment account record by contract account %s, account %s", contract, account,
-				)
+			return &emptyAccountRecord, nil
 		}
-	} else {
-		if st != nil {
-			accountRecord, err = state.GetDepositRecordFromState(st)
+
+		return nil,
+			utilm.ErrNotFound.WithMessage(
+				err, "payment account record by contract account %s, account %s", contract, account,
+			)
+	}
+
+	if st == nil {
+		return nil, errors.Errorf("state is nil")
+	}
+
+	return state.GetDepositRecordFromState(st)
+}
+
+// PaymentEvents calls callback with the events of the contract account which
+// match the filter, ordered by height, address and sequence.
+func PaymentEvents(
+	db *cdigest.Database,
+	contract string,
+	f EventFilter,
+	reverse bool,
+	offset string,
+	limit int64,
+	callback func(EventValue) (bool, error),
+) error {
+	filter, err := buildEventsFilter(contract, f, offset, reverse)
+	if err != nil {
+		return err
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(
+		utilc.NewBSONFilter("height", sr).Add("address", sr).Add("sequence", sr).D(),
+	)
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	return db.MongoClient().Find(
+		context.Background(),
+		DefaultColNamePaymentEvent,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			va, err := loadEventValue(cursor.Decode)
 			if err != nil {
-				return nil, err
+				return false, err
 			}
+
+			return callback(va)
+		},
+		opt,
+	)
+}
+
+// PaymentAccounts calls callback with the settings and the latest deposit
+// records of the accounts in the design of the contract account, which match
+// the filter, ordered by address after offset. The current deposit records
+// are queried by the contract account, the deposited currencies and the
+// address, and the settings are matched while reading the cursor.
+func PaymentAccounts(
+	db *cdigest.Database,
+	contract string,
+	f AccountFilter,
+	offset string,
+	limit int64,
+	callback func(AccountInfoValue) (bool, error),
+) error {
+	design, _, err := PaymentDesign(db, contract)
+	if err != nil {
+		return err
+	}
+
+	filter := utilc.NewBSONFilter("contract", contract)
+	if f.Deposited {
+		if len(f.Currency) > 0 {
+			filter = filter.Add("deposited", f.Currency)
 		} else {
-			return nil, errors.Errorf("state is nil")
+			filter = filter.Add("deposited.0", bson.M{"$exists": true})
 		}
 	}
-	if design, _, err = PaymentDesign(db, contract); err != nil {
-		return nil, err
+
+	if len(offset) > 0 {
+		filter = filter.Add("address", bson.M{"$gt": offset})
 	}
 
-	accountInfo := design.AccountSetting(account)
-	if accountInfo == nil {
-		return nil, utilm.ErrNotFound.Errorf(
-			"payment account info not found by contract account %s, account %s", contract, account)
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
 	}
 
-	accountInfoValue := NewAccountInfoValue(*accountInfo, *accountRecord)
-	return &accountInfoValue, nil
+	opt := options.Find().
+		SetSort(utilc.NewBSONFilter("address", 1).D()).
+		SetBatchSize(int32(limit))
+
+	// NOTE the allowances are left empty when the block time is not known.
+	now, _ := BlockTime(db, base.NilHeight)
+
+	var count int64
+
+	return db.MongoClient().Find(
+		context.Background(),
+		DefaultColNamePaymentAccountCurrent,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			st, err := cdigest.LoadState(cursor.Decode, db.Encoders())
+			if err != nil {
+				return false, err
+			}
+
+			record, err := state.GetDepositRecordFromState(st)
+			if err != nil {
+				return false, err
+			}
+
+			// NOTE the accounts withdrawn all or not added to the design have
+			// the records without the setting.
+			setting := design.AccountSetting(record.Address().String())
+			if setting == nil || !f.matchSetting(*setting) {
+				return true, nil
+			}
+
+			switch keep, err := callback(NewAccountInfoValue(*setting, *record, now)); {
+			case err != nil:
+				return false, err
+			case !keep:
+				return false, nil
+			}
+
+			count++
+
+			return count < limit, nil
+		},
+		opt,
+	)
+}
+
+// BlockTime returns the proposed time of the block of height in unix seconds;
+// of the last block when height is base.NilHeight.
+func BlockTime(db *cdigest.Database, height base.Height) (uint64, error) {
+	if height <= base.NilHeight {
+		height = db.LastBlock()
+	}
+
+	t, err := blockProposedAt(db, height)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(t.Unix()), nil
+}
+
+func blockProposedAt(db *cdigest.Database, height base.Height) (time.Time, error) {
+	m, _, _, _, _, _, err := db.ManifestByHeight(height)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return m.ProposedAt(), nil
+}
+
+// HeightAt returns the height of the last block proposed at or before t.
+func HeightAt(db *cdigest.Database, t time.Time) (base.Height, error) {
+	return searchHeightAt(db.LastBlock(), t, func(height base.Height) (time.Time, error) {
+		return blockProposedAt(db, height)
+	})
+}
+
+// searchHeightAt finds the last height proposed at or before t in the blocks
+// up to last; the proposed times of the blocks do not decrease by height.
+func searchHeightAt(
+	last base.Height, t time.Time, proposedAt func(base.Height) (time.Time, error),
+) (base.Height, error) {
+	low, high := base.GenesisHeight, last
+	if high < low {
+		return base.NilHeight, utilm.ErrNotFound.Errorf("no block")
+	}
+
+	switch first, err := proposedAt(low); {
+	case err != nil:
+		return base.NilHeight, err
+	case first.After(t):
+		return base.NilHeight, utilm.ErrNotFound.Errorf("no block at or before %v", t)
+	}
+
+	for low < high {
+		mid := low + (high-low+1)/2
+
+		at, err := proposedAt(mid)
+		if err != nil {
+			return base.NilHeight, err
+		}
+
+		if at.After(t) {
+			high = mid - 1
+		} else {
+			low = mid
+		}
+	}
+
+	return low, nil
 }
@@ -0,0 +1,52 @@
+package digest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/imfact-labs/mitum2/base"
+)
+
+func TestSearchHeightAt(t *testing.T) {
+	genesis := time.Unix(1000, 0)
+
+	// NOTE the blocks of height 2 and 3 are proposed at the same time.
+	times := []time.Time{genesis, genesis.Add(time.Second * 10), genesis.Add(time.Second * 20), genesis.Add(time.Second * 20)}
+	proposedAt := func(height base.Height) (time.Time, error) {
+		return times[height], nil
+	}
+
+	last := base.Height(len(times) - 1)
+
+	for at, expected := range map[time.Duration]base.Height{
+		0:                0,
+		time.Second * 9:  0,
+		time.Second * 10: 1,
+		time.Second * 19: 1,
+		time.Second * 20: 3,
+		time.Hour:        3,
+	} {
+		height, err := searchHeightAt(last, genesis.Add(at), proposedAt)
+		switch {
+		case err != nil:
+			t.Errorf("%v: %v", at, err)
+		case height != expected:
+			t.Errorf("%v: height %v, not %v", at, height, expected)
+		}
+	}
+
+	if _, err := searchHeightAt(last, genesis.Add(-time.Second), proposedAt); err == nil {
+		t.Error("height before genesis found")
+	}
+
+	if _, err := searchHeightAt(base.NilHeight, genesis, proposedAt); err == nil {
+		t.Error("height found without block")
+	}
+
+	if _, err := searchHeightAt(last, genesis, func(base.Height) (time.Time, error) {
+		return time.Time{}, errors.New("manifest")
+	}); err == nil {
+		t.Error("error of manifest ignored")
+	}
+}
@@ -1,6 +1,8 @@
 package digest
 
 import (
+	"cmp"
+
 	mongodb "github.com/imfact-labs/currency-model/digest/mongodb"
 	cstate "github.com/imfact-labs/currency-model/state"
 	"github.com/imfact-labs/currency-model/utils/bsonenc"
@@ -10,6 +12,7 @@ import (
 	"github.com/imfact-labs/mitum2/util/hint"
 	"github.com/imfact-labs/payment-model/state"
 	"github.com/imfact-labs/payment-model/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type DesignDoc struct {
@@ -89,29 +92,118 @@ func (doc DepositRecordDoc) MarshalBSON() ([]byte, error) {
 
 	m["contract"] = parsedKey[1]
 	m["address"] = doc.record.Address()
+	m["deposited"] = depositedCurrencies(doc.record)
+	m["height"] = doc.st.Height()
+
+	return bsonenc.Marshal(m)
+}
+
+type LockDoc struct {
+	mongodb.BaseDoc
+	st   base.State
+	lock types.Lock
+}
+
+func NewLockDoc(st base.State, enc encoder.Encoder) (LockDoc, error) {
+	lock, err := state.GetLockFromState(st)
+	if err != nil {
+		return LockDoc{}, err
+	}
+
+	b, err := mongodb.NewBaseDoc(nil, st, enc)
+	if err != nil {
+		return LockDoc{}, err
+	}
+
+	return LockDoc{
+		BaseDoc: b,
+		st:      st,
+		lock:    *lock,
+	}, nil
+}
+
+func (doc LockDoc) MarshalBSON() ([]byte, error) {
+	m, err := doc.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	parsedKey, err := cstate.ParseStateKey(doc.st.Key(), state.PaymentStateKeyPrefix, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	m["contract"] = parsedKey[1]
+	m["hash_lock"] = doc.lock.HashLock()
 	m["height"] = doc.st.Height()
 
 	return bsonenc.Marshal(m)
 }
 
+type EventDoc struct {
+	contract string
+	address  string
+	event    types.Event
+}
+
+func NewEventDoc(contract, address string, event types.Event) EventDoc {
+	return EventDoc{
+		contract: contract,
+		address:  address,
+		event:    event,
+	}
+}
+
+func (doc EventDoc) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bson.M{
+		"contract":     doc.contract,
+		"address":      doc.address,
+		"sequence":     doc.event.Sequence,
+		"type":         doc.event.Type,
+		"currency":     doc.event.Currency,
+		"amount":       doc.event.Amount,
+		"counterparty": doc.event.Counterparty,
+		"fact_hash":    doc.event.FactHash,
+		"proposed_at":  doc.event.ProposedAt,
+		"height":       doc.event.Height,
+	})
+}
+
 var (
 	AccountInfoValueHint = hint.MustNewHint("mitum-payment-account-info-value-v0.0.1")
 )
 
 type AccountInfoValue struct {
 	hint.BaseHinter
-	setting types.Setting
-	record  types.DepositRecord
+	setting     types.Setting
+	record      types.DepositRecord
+	evaluatedAt uint64
+	allowances  map[string]types.TransferAllowance
 }
 
+// NewAccountInfoValue evaluates the transfer allowances of the currencies in
+// the setting at evaluatedAt, the time of the block in unix seconds; zero
+// evaluatedAt leaves the allowances empty.
 func NewAccountInfoValue(
 	setting types.Setting,
 	record types.DepositRecord,
+	evaluatedAt uint64,
 ) AccountInfoValue {
+	allowances := map[string]types.TransferAllowance{}
+	if evaluatedAt > 0 {
+		for cid := range setting.Items() {
+			if a, found := types.NewTransferAllowance(setting, record, cid, evaluatedAt); found {
+				allowances[cid] = a
+			}
+		}
+	}
+
 	return AccountInfoValue{
-		BaseHinter: hint.NewBaseHinter(AccountInfoValueHint),
-		setting:    setting,
-		record:     record,
+		BaseHinter:  hint.NewBaseHinter(AccountInfoValueHint),
+		setting:     setting,
+		record:      record,
+		evaluatedAt: evaluatedAt,
+		allowances:  allowances,
 	}
 }
 
@@ -127,16 +219,108 @@ func (ai AccountInfoValue) AccountRecord() types.DepositRecord {
 	return ai.record
 }
 
+func (ai AccountInfoValue) EvaluatedAt() uint64 {
+	return ai.evaluatedAt
+}
+
+func (ai AccountInfoValue) TransferAllowances() map[string]types.TransferAllowance {
+	return ai.allowances
+}
+
 type AccountInfoValueJSONMarshaler struct {
 	hint.BaseHinter
-	Setting types.Setting       `json:"transfer_setting"`
-	Record  types.DepositRecord `json:"deposit_record"`
+	Setting     types.Setting                      `json:"transfer_setting"`
+	Record      types.DepositRecord                `json:"deposit_record"`
+	EvaluatedAt uint64                             `json:"evaluated_at"`
+	Allowances  map[string]types.TransferAllowance `json:"transfer_allowance"`
 }
 
 func (ai AccountInfoValue) MarshalJSON() ([]byte, error) {
 	return util.MarshalJSON(AccountInfoValueJSONMarshaler{
-		BaseHinter: ai.BaseHinter,
-		Setting:    ai.setting,
-		Record:     ai.record,
+		BaseHinter:  ai.BaseHinter,
+		Setting:     ai.setting,
+		Record:      ai.record,
+		EvaluatedAt: ai.evaluatedAt,
+		Allowances:  ai.allowances,
+	})
+}
+
+var EventValueHint = hint.MustNewHint("mitum-payment-event-value-v0.0.1")
+
+// EventValue is the payment event of an account in a contract account.
+type EventValue struct {
+	hint.BaseHinter
+	contract string
+	address  string
+	event    types.Event
+}
+
+func NewEventValue(contract, address string, event types.Event) EventValue {
+	return EventValue{
+		BaseHinter: hint.NewBaseHinter(EventValueHint),
+		contract:   contract,
+		address:    address,
+		event:      event,
+	}
+}
+
+func (ev EventValue) Contract() string {
+	return ev.contract
+}
+
+func (ev EventValue) Address() string {
+	return ev.address
+}
+
+func (ev EventValue) Event() types.Event {
+	return ev.event
+}
+
+// Offset returns the offset of the next page which starts after the event.
+func (ev EventValue) Offset() string {
+	return BuildEventOffset(ev.event.Height, ev.address, ev.event.Sequence)
+}
+
+// Compare compares ev with the event of the offset by height, address and
+// sequence; -1 when ev is ordered before it.
+func (ev EventValue) Compare(height base.Height, address string, sequence uint64) int {
+	switch {
+	case ev.event.Height != height.Int64():
+		return cmp.Compare(ev.event.Height, height.Int64())
+	case ev.address != address:
+		return cmp.Compare(ev.address, address)
+	default:
+		return cmp.Compare(ev.event.Sequence, sequence)
+	}
+}
+
+type EventValueJSONMarshaler struct {
+	hint.BaseHinter
+	Contract string `json:"contract"`
+	Address  string `json:"address"`
+	types.Event
+}
+
+func (ev EventValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(EventValueJSONMarshaler{
+		BaseHinter: ev.BaseHinter,
+		Contract:   ev.contract,
+		Address:    ev.address,
+		Event:      ev.event,
 	})
 }
+
+type eventDocBSONUnmarshaler struct {
+	Contract    string `bson:"contract"`
+	Address     string `bson:"address"`
+	types.Event `bson:",inline"`
+}
+
+func loadEventValue(decoder func(interface{}) error) (EventValue, error) {
+	var u eventDocBSONUnmarshaler
+	if err := decoder(&u); err != nil {
+		return EventValue{}, err
+	}
+
+	return NewEventValue(u.Contract, u.Address, u.Event), nil
+}
@@ -1,10 +1,16 @@
 package digest
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/types"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func parseIdxFromPath(s string) (uint64, error) {
@@ -22,3 +28,200 @@ func parseIdxFromPath(s string) (uint64, error) {
 
 	return i, nil
 }
+
+// EventFilter selects the payment events of a contract account; the empty
+// fields are not filtered.
+type EventFilter struct {
+	Address    string
+	Types      []string
+	Currency   string
+	FromHeight base.Height
+	ToHeight   base.Height
+	FromTime   uint64
+	ToTime     uint64
+}
+
+func NewEventFilter() EventFilter {
+	return EventFilter{
+		FromHeight: base.NilHeight,
+		ToHeight:   base.NilHeight,
+	}
+}
+
+func (f EventFilter) bson(contract string) bson.M {
+	filter := bson.M{"contract": contract}
+
+	if len(f.Address) > 0 {
+		filter["address"] = f.Address
+	}
+
+	if len(f.Types) > 0 {
+		filter["type"] = bson.M{"$in": f.Types}
+	}
+
+	if len(f.Currency) > 0 {
+		filter["currency"] = f.Currency
+	}
+
+	height := bson.M{}
+	if f.FromHeight > base.NilHeight {
+		height["$gte"] = f.FromHeight.Int64()
+	}
+
+	if f.ToHeight > base.NilHeight {
+		height["$lte"] = f.ToHeight.Int64()
+	}
+
+	if len(height) > 0 {
+		filter["height"] = height
+	}
+
+	proposedAt := bson.M{}
+	if f.FromTime > 0 {
+		proposedAt["$gte"] = int64(f.FromTime)
+	}
+
+	if f.ToTime > 0 {
+		proposedAt["$lte"] = int64(f.ToTime)
+	}
+
+	if len(proposedAt) > 0 {
+		filter["proposed_at"] = proposedAt
+	}
+
+	return filter
+}
+
+// match checks the event as the filter of the database does.
+func (f EventFilter) match(address string, ev types.Event) bool {
+	switch {
+	case len(f.Address) > 0 && f.Address != address,
+		len(f.Currency) > 0 && f.Currency != ev.Currency,
+		f.FromHeight > base.NilHeight && ev.Height < f.FromHeight.Int64(),
+		f.ToHeight > base.NilHeight && ev.Height > f.ToHeight.Int64(),
+		f.FromTime > 0 && ev.ProposedAt < f.FromTime,
+		f.ToTime > 0 && ev.ProposedAt > f.ToTime:
+		return false
+	case len(f.Types) < 1:
+		return true
+	}
+
+	for i := range f.Types {
+		if f.Types[i] == ev.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BuildEventOffset returns the offset of the event, "<height>,<address>,<sequence>".
+func BuildEventOffset(height int64, address string, sequence uint64) string {
+	return fmt.Sprintf("%d,%s,%d", height, address, sequence)
+}
+
+// ParseEventOffset parses the offset built by BuildEventOffset.
+func ParseEventOffset(s string) (base.Height, string, uint64, error) {
+	n := strings.SplitN(s, ",", 3)
+	if len(n) < 3 {
+		return base.NilHeight, "", 0, errors.Errorf("invalid offset, %q", s)
+	}
+
+	h, err := base.ParseHeightString(n[0])
+	if err != nil {
+		return base.NilHeight, "", 0, errors.Wrap(err, "invalid height of offset")
+	}
+
+	if len(n[1]) < 1 {
+		return base.NilHeight, "", 0, errors.Errorf("empty address of offset, %q", s)
+	}
+
+	u, err := strconv.ParseUint(n[2], 10, 64)
+	if err != nil {
+		return base.NilHeight, "", 0, errors.Wrap(err, "invalid sequence of offset")
+	}
+
+	return h, n[1], u, nil
+}
+
+func buildEventsFilter(contract string, f EventFilter, offset string, reverse bool) (bson.M, error) {
+	filter := f.bson(contract)
+	if len(offset) < 1 {
+		return filter, nil
+	}
+
+	height, address, sequence, err := ParseEventOffset(offset)
+	if err != nil {
+		return nil, err
+	}
+
+	op := "$gt"
+	if reverse {
+		op = "$lt"
+	}
+
+	filter["$or"] = []bson.M{
+		{"height": bson.M{op: height.Int64()}},
+		{"height": height.Int64(), "address": bson.M{op: address}},
+		{"height": height.Int64(), "address": address, "sequence": bson.M{op: int64(sequence)}},
+	}
+
+	return filter, nil
+}
+
+// AccountFilter selects the accounts in the design of a contract account; the
+// empty fields are not filtered. The setting filters are satisfied when the
+// item of Currency, or any item without Currency, matches all of them.
+type AccountFilter struct {
+	Currency     string
+	Deposited    bool
+	ExpireBefore uint64
+	MinLimit     *common.Big
+	MaxLimit     *common.Big
+}
+
+func (f AccountFilter) matchSetting(s types.Setting) bool {
+	if len(f.Currency) > 0 {
+		item, found := s.Items()[f.Currency]
+
+		return found && f.matchSettingItem(item)
+	}
+
+	if f.ExpireBefore < 1 && f.MinLimit == nil && f.MaxLimit == nil {
+		return true
+	}
+
+	for _, item := range s.Items() {
+		if f.matchSettingItem(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f AccountFilter) matchSettingItem(item types.SettingItem) bool {
+	switch {
+	case f.ExpireBefore > 0 && item.EndTime >= f.ExpireBefore:
+		return false
+	case f.MinLimit != nil && item.TransferLimit.Compare(*f.MinLimit) < 0:
+		return false
+	case f.MaxLimit != nil && item.TransferLimit.Compare(*f.MaxLimit) > 0:
+		return false
+	default:
+		return true
+	}
+}
+
+// depositedCurrencies returns the currencies of the non-zero deposits.
+func depositedCurrencies(r types.DepositRecord) []string {
+	cids := []string{}
+	for k, v := range r.Items() {
+		if v.Amount.OverZero() {
+			cids = append(cids, k)
+		}
+	}
+	sort.Strings(cids)
+
+	return cids
+}
@@ -0,0 +1,148 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestEventOffset(t *testing.T) {
+	offset := BuildEventOffset(3, "accounta", 7)
+
+	height, address, sequence, err := ParseEventOffset(offset)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case height != 3, address != "accounta", sequence != 7:
+		t.Errorf("parsed %v, %q, %d", height, address, sequence)
+	}
+
+	for _, s := range []string{"", "3,accounta", "a,accounta,7", "3,,7", "3,accounta,-1"} {
+		if _, _, _, err := ParseEventOffset(s); err == nil {
+			t.Errorf("invalid offset, %q parsed", s)
+		}
+	}
+}
+
+func TestEventFilterMatch(t *testing.T) {
+	ev := types.NewEvent(types.EventTypeTransfer, "MCC", common.NewBig(1), "receiver", valuehash.RandomSHA256(), 100, 3)
+
+	newFilter := func(f func(*EventFilter)) EventFilter {
+		i := NewEventFilter()
+		f(&i)
+
+		return i
+	}
+
+	for name, c := range map[string]struct {
+		filter  EventFilter
+		matched bool
+	}{
+		"empty":          {NewEventFilter(), true},
+		"address":        {newFilter(func(f *EventFilter) { f.Address = "accounta" }), true},
+		"other address":  {newFilter(func(f *EventFilter) { f.Address = "accountb" }), false},
+		"types":          {newFilter(func(f *EventFilter) { f.Types = []string{types.EventTypeDeposit, ev.Type} }), true},
+		"other types":    {newFilter(func(f *EventFilter) { f.Types = []string{types.EventTypeDeposit} }), false},
+		"other currency": {newFilter(func(f *EventFilter) { f.Currency = "PEN" }), false},
+		"height range":   {newFilter(func(f *EventFilter) { f.FromHeight, f.ToHeight = 3, 3 }), true},
+		"after height":   {newFilter(func(f *EventFilter) { f.FromHeight = 4 }), false},
+		"before height":  {newFilter(func(f *EventFilter) { f.ToHeight = 2 }), false},
+		"time range":     {newFilter(func(f *EventFilter) { f.FromTime, f.ToTime = 100, 100 }), true},
+		"after time":     {newFilter(func(f *EventFilter) { f.FromTime = 101 }), false},
+		"before time":    {newFilter(func(f *EventFilter) { f.ToTime = 99 }), false},
+	} {
+		if c.filter.match("accounta", ev) != c.matched {
+			t.Errorf("%s: matched %v", name, !c.matched)
+		}
+	}
+}
+
+func TestBuildEventsFilter(t *testing.T) {
+	f := NewEventFilter()
+	f.Address = "accounta"
+	f.FromHeight = 2
+
+	filter, err := buildEventsFilter("contract", f, "", false)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case filter["contract"] != "contract", filter["address"] != "accounta":
+		t.Errorf("filter %v", filter)
+	case filter["$or"] != nil:
+		t.Error("offset filter without offset")
+	}
+
+	if h, ok := filter["height"].(bson.M); !ok || h["$gte"] != int64(2) || h["$lte"] != nil {
+		t.Errorf("height filter %v", filter["height"])
+	}
+
+	for reverse, op := range map[bool]string{false: "$gt", true: "$lt"} {
+		filter, err := buildEventsFilter("contract", f, BuildEventOffset(3, "accounta", 7), reverse)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		or, ok := filter["$or"].([]bson.M)
+		switch {
+		case !ok || len(or) != 3:
+			t.Fatalf("offset filter %v", filter["$or"])
+		case or[0]["height"].(bson.M)[op] != int64(3):
+			t.Errorf("reverse %v: height of offset %v", reverse, or[0])
+		case or[2]["sequence"].(bson.M)[op] != int64(7):
+			t.Errorf("reverse %v: sequence of offset %v", reverse, or[2])
+		}
+	}
+
+	if _, err := buildEventsFilter("contract", f, "3,accounta", false); err == nil {
+		t.Error("invalid offset built")
+	}
+}
+
+func TestAccountFilterMatchSetting(t *testing.T) {
+	setting := types.NewSettings(ctypes.NewStringAddress("account"))
+	setting.SetItem("MCC", common.NewBig(10), 1, 100, 1)
+	setting.SetItem("PEN", common.NewBig(50), 1, 300, 1)
+
+	big := func(i int64) *common.Big {
+		b := common.NewBig(i)
+
+		return &b
+	}
+
+	for name, c := range map[string]struct {
+		filter  AccountFilter
+		matched bool
+	}{
+		"empty":                   {AccountFilter{}, true},
+		"currency":                {AccountFilter{Currency: "MCC"}, true},
+		"other currency":          {AccountFilter{Currency: "ABC"}, false},
+		"expire before":           {AccountFilter{ExpireBefore: 200}, true},
+		"expire before of cid":    {AccountFilter{Currency: "PEN", ExpireBefore: 200}, false},
+		"expire at end time":      {AccountFilter{Currency: "MCC", ExpireBefore: 100}, false},
+		"limit range":             {AccountFilter{MinLimit: big(20), MaxLimit: big(50)}, true},
+		"limit range of cid":      {AccountFilter{Currency: "MCC", MinLimit: big(20)}, false},
+		"limit of other item":     {AccountFilter{MinLimit: big(20), ExpireBefore: 200}, false},
+		"limit over items":        {AccountFilter{MinLimit: big(51)}, false},
+		"limit under items":       {AccountFilter{MaxLimit: big(9)}, false},
+		"deposited not by filter": {AccountFilter{Deposited: true}, true},
+	} {
+		if c.filter.matchSetting(setting) != c.matched {
+			t.Errorf("%s: matched %v", name, !c.matched)
+		}
+	}
+}
+
+func TestDepositedCurrencies(t *testing.T) {
+	record := types.NewDepositRecord(ctypes.NewStringAddress("account"))
+	record.SetItem("PEN", common.NewBig(10), 1)
+	record.SetItem("ABC", common.ZeroBig, 1)
+	record.SetItem("MCC", common.NewBig(1), 1)
+
+	if cids := depositedCurrencies(record); len(cids) != 2 || cids[0] != "MCC" || cids[1] != "PEN" {
+		t.Errorf("deposited currencies %v", cids)
+	}
+}
@@ -17,6 +17,16 @@ var PaymentIndexModels = []mongo.IndexModel{
 	},
 }
 
+var PaymentCurrentIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_current_contract").
+			SetUnique(true),
+	},
+}
+
 var PaymentAccountRecordIndexModels = []mongo.IndexModel{
 	{
 		Keys: bson.D{
@@ -28,9 +38,124 @@ var PaymentAccountRecordIndexModels = []mongo.IndexModel{
 	},
 }
 
+var PaymentAccountCurrentIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "address", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_current_contract_address").
+			SetUnique(true),
+	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "deposited", Value: 1},
+			bson.E{Key: "address", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_current_contract_deposited_address"),
+	},
+}
+
+var PaymentEventIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "address", Value: 1},
+			bson.E{Key: "sequence", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_event_contract_address_sequence").
+			SetUnique(true),
+	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "height", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_event_height"),
+	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "height", Value: 1},
+			bson.E{Key: "address", Value: 1},
+			bson.E{Key: "sequence", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_event_contract_height_address_sequence"),
+	},
+}
+
+var PaymentLockIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "hash_lock", Value: 1},
+			bson.E{Key: "height", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_lock_contract_hash_lock_height"),
+	},
+}
+
+var PaymentStatsIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "height", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_stats_contract_height").
+			SetUnique(true),
+	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "proposed_at", Value: -1},
+			bson.E{Key: "height", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_stats_contract_proposed_at_height"),
+	},
+}
+
+var PaymentReceivedIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "sender", Value: 1},
+			bson.E{Key: "sequence", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_received_contract_sender_sequence").
+			SetUnique(true),
+	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "receiver", Value: 1},
+			bson.E{Key: "height", Value: 1},
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "sender", Value: 1},
+			bson.E{Key: "sequence", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_received_receiver_height_contract_sender_sequence"),
+	},
+}
+
+var PaymentWebhookDeadLetterIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "url", Value: 1},
+			bson.E{Key: "failed_at", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "payment_webhook_dead_url_failed_at"),
+	},
+}
+
 var DefaultIndexes = cdigest.DefaultIndexes
 
 func init() {
 	DefaultIndexes[DefaultColNamePayment] = PaymentIndexModels
+	DefaultIndexes[DefaultColNamePaymentCurrent] = PaymentCurrentIndexModels
 	DefaultIndexes[DefaultColNamePaymentAccount] = PaymentAccountRecordIndexModels
+	DefaultIndexes[DefaultColNamePaymentAccountCurrent] = PaymentAccountCurrentIndexModels
+	DefaultIndexes[DefaultColNamePaymentEvent] = PaymentEventIndexModels
+	DefaultIndexes[DefaultColNamePaymentLock] = PaymentLockIndexModels
+	DefaultIndexes[DefaultColNamePaymentStats] = PaymentStatsIndexModels
+	DefaultIndexes[DefaultColNamePaymentReceived] = PaymentReceivedIndexModels
+	DefaultIndexes[DefaultColNamePaymentWebhookDeadLetter] = PaymentWebhookDeadLetterIndexModels
 }
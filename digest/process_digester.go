@@ -63,12 +63,20 @@ func ProcessDigester(ctx context.Context) (context.Context, error) {
 		sourceReaders = i
 	}
 
-	di := cdigest.NewDigester(st, root, sourceReaders, fromRemotes, design.NetworkID, vs.String(), nil)
+	if err := BackfillPaymentCurrent(ctx, st); err != nil {
+		return ctx, err
+	}
+
+	digested := make(chan error, 100)
+
+	di := cdigest.NewDigester(st, root, sourceReaders, fromRemotes, design.NetworkID, vs.String(), digested)
 	_ = di.SetLogging(log)
 
+	go publishDigestedPaymentEvents(ctx, st, digested, log)
+
 	di.PrepareFunc = []cdigest.BlockSessionPrepareFunc{
 		cdigest.PrepareCurrencies, cdigest.PrepareAccounts, cdigest.PrepareDIDRegistry,
-		PreparePayment,
+		PreparePayment, PreparePaymentCurrent, PreparePaymentStats, PreparePaymentReceived,
 	}
 
 	return context.WithValue(ctx, cdigest.ContextValueDigester, di), nil
@@ -0,0 +1,402 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	DefaultColNamePaymentPrune = "digest_pmt_prune"
+	PNameStartPaymentPruner    = ps.Name("start-payment-pruner")
+
+	ContextValuePaymentRetention util.ContextKey = "payment_retention"
+	ContextValuePaymentPruner    util.ContextKey = "payment_pruner"
+)
+
+const (
+	pruneStateID     = "state"
+	pruneDeleteLimit = 500
+)
+
+// RetentionPolicy decides the payment history kept in the digest. The history
+// of the last Heights heights and the history after Since in unix seconds are
+// kept; with both, the longer one is kept. Before them, only the documents
+// effective at every SnapshotInterval heights are kept.
+type RetentionPolicy struct {
+	Heights          uint64
+	Since            uint64
+	SnapshotInterval uint64
+	// Interval is the interval of the pruning.
+	Interval time.Duration
+}
+
+func (p RetentionPolicy) Enabled() bool {
+	return p.Heights > 0 || p.Since > 0
+}
+
+// PruneState is the result of the last pruning; the history before Height is
+// kept only at the multiples of SnapshotInterval.
+type PruneState struct {
+	Height           base.Height `bson:"height"`
+	ProposedAt       uint64      `bson:"proposed_at"`
+	SnapshotInterval uint64      `bson:"snapshot_interval"`
+}
+
+func (s PruneState) isSnapshot(height base.Height) bool {
+	return s.SnapshotInterval > 0 && uint64(height.Int64())%s.SnapshotInterval == 0
+}
+
+func LoadPruneState(db *cdigest.Database) (PruneState, bool, error) {
+	var s PruneState
+
+	switch err := db.MongoClient().Collection(DefaultColNamePaymentPrune).FindOne(
+		context.Background(),
+		bson.M{"_id": pruneStateID},
+	).Decode(&s); {
+	case err == nil:
+		return s, true, nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return PruneState{Height: base.NilHeight}, false, nil
+	default:
+		return s, false, err
+	}
+}
+
+// checkPrunedHeight checks whether the history at height is kept.
+func checkPrunedHeight(db *cdigest.Database, height base.Height) error {
+	switch s, found, err := LoadPruneState(db); {
+	case err != nil:
+		return err
+	case !found, height >= s.Height, s.isSnapshot(height):
+		return nil
+	default:
+		return errPruned("height %d", height)
+	}
+}
+
+// checkPrunedTime checks whether the history at t in unix seconds is kept.
+func checkPrunedTime(db *cdigest.Database, t uint64) error {
+	switch s, found, err := LoadPruneState(db); {
+	case err != nil:
+		return err
+	case !found, t >= s.ProposedAt:
+		return nil
+	default:
+		return errPruned("time %d", t)
+	}
+}
+
+func errPruned(f string, a ...interface{}) error {
+	return util.ErrNotFound.Errorf("payment history pruned; %s", fmt.Sprintf(f, a...))
+}
+
+type PaymentPruner struct {
+	*logging.Logging
+	*util.ContextDaemon
+	db     *cdigest.Database
+	policy RetentionPolicy
+}
+
+func NewPaymentPruner(db *cdigest.Database, policy RetentionPolicy) *PaymentPruner {
+	p := &PaymentPruner{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "payment-pruner")
+		}),
+		db:     db,
+		policy: policy,
+	}
+
+	p.ContextDaemon = util.NewContextDaemon(p.start)
+
+	return p
+}
+
+func (p *PaymentPruner) start(ctx context.Context) error {
+	interval := p.policy.Interval
+	if interval <= 0 {
+		interval = time.Minute * 10
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Prune(ctx); err != nil {
+			p.Log().Error().Err(err).Msg("failed to prune")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune removes the payment history out of the retention policy.
+func (p *PaymentPruner) Prune(ctx context.Context) error {
+	prev, found, err := LoadPruneState(p.db)
+	if err != nil {
+		return err
+	}
+
+	if found && prev.SnapshotInterval != p.policy.SnapshotInterval {
+		return errors.Errorf(
+			"snapshot interval changed from %d to %d; rebuild payment digest",
+			prev.SnapshotInterval, p.policy.SnapshotInterval,
+		)
+	}
+
+	height, err := p.retainedHeight()
+	if err != nil {
+		return err
+	}
+
+	if height <= base.GenesisHeight || height <= prev.Height {
+		return nil
+	}
+
+	proposedAt, err := BlockTime(p.db, height)
+	if err != nil {
+		return err
+	}
+
+	state := PruneState{Height: height, ProposedAt: proposedAt, SnapshotInterval: p.policy.SnapshotInterval}
+
+	// NOTE the state is saved before pruning, so the history queries being
+	// pruned are rejected.
+	if _, err := p.db.MongoClient().Collection(DefaultColNamePaymentPrune).ReplaceOne(
+		ctx,
+		bson.M{"_id": pruneStateID},
+		state,
+		options.Replace().SetUpsert(true),
+	); err != nil {
+		return err
+	}
+
+	for col, keys := range map[string][]string{
+		DefaultColNamePayment:        {"contract"},
+		DefaultColNamePaymentAccount: {"contract", "address"},
+		DefaultColNamePaymentLock:    {"contract", "hash_lock"},
+		DefaultColNamePaymentStats:   {"contract"},
+	} {
+		if err := p.pruneStates(ctx, col, keys, state); err != nil {
+			return errors.WithMessagef(err, "prune %s", col)
+		}
+	}
+
+	for _, col := range []string{DefaultColNamePaymentEvent, DefaultColNamePaymentReceived} {
+		if _, err := p.db.MongoClient().Collection(col).DeleteMany(
+			ctx, bson.M{"height": bson.M{"$lt": height.Int64()}},
+		); err != nil {
+			return errors.WithMessagef(err, "prune %s", col)
+		}
+	}
+
+	p.Log().Debug().Interface("state", state).Msg("payment history pruned")
+
+	return nil
+}
+
+// retainedHeight returns the lowest height kept by the policy.
+func (p *PaymentPruner) retainedHeight() (base.Height, error) {
+	return p.policy.retainedHeight(p.db.LastBlock(), func(t time.Time) (base.Height, error) {
+		return HeightAt(p.db, t)
+	})
+}
+
+// retainedHeight returns the lowest height kept by the policy in the blocks up
+// to last; heightAt returns the last height proposed at or before the time.
+func (p RetentionPolicy) retainedHeight(
+	last base.Height, heightAt func(time.Time) (base.Height, error),
+) (base.Height, error) {
+	if last <= base.NilHeight {
+		return base.NilHeight, nil
+	}
+
+	height := base.NilHeight
+
+	if p.Heights > 0 {
+		height = last - base.Height(p.Heights) + 1
+	}
+
+	if p.Since > 0 {
+		var h base.Height
+
+		switch i, err := heightAt(time.Unix(int64(p.Since), 0)); {
+		case errors.Is(err, util.ErrNotFound):
+			h = base.GenesisHeight
+		case err != nil:
+			return base.NilHeight, err
+		default:
+			h = i + 1
+		}
+
+		if height <= base.NilHeight || h < height {
+			height = h
+		}
+	}
+
+	return height, nil
+}
+
+// pruneStates removes the documents before the height of state, except the
+// ones effective at the height and at the snapshots; the documents are
+// grouped by keys.
+func (p *PaymentPruner) pruneStates(ctx context.Context, col string, keys []string, state PruneState) error {
+	sort := bson.D{}
+	projection := bson.M{"_id": 1, "height": 1}
+	for _, k := range keys {
+		sort = append(sort, bson.E{Key: k, Value: 1})
+		projection[k] = 1
+	}
+	sort = append(sort, bson.E{Key: "height", Value: -1})
+
+	r := &snapshotRetainer{state: state}
+	var ids []interface{}
+
+	deleteIDs := func() error {
+		if len(ids) < 1 {
+			return nil
+		}
+
+		_, err := p.db.MongoClient().Collection(col).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		ids = nil
+
+		return err
+	}
+
+	if err := p.db.MongoClient().Find(
+		ctx,
+		col,
+		bson.M{"height": bson.M{"$lt": state.Height.Int64()}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var m bson.M
+			if err := cursor.Decode(&m); err != nil {
+				return false, err
+			}
+
+			height, ok := m["height"].(int64)
+			if !ok {
+				return false, errors.Errorf("invalid height, %v", m["height"])
+			}
+
+			vs := make([]string, len(keys))
+			for i, k := range keys {
+				vs[i] = fmt.Sprint(m[k])
+			}
+
+			if !r.keep(strings.Join(vs, ","), height) {
+				ids = append(ids, m["_id"])
+			}
+
+			if len(ids) >= pruneDeleteLimit {
+				if err := deleteIDs(); err != nil {
+					return false, err
+				}
+			}
+
+			return true, nil
+		},
+		options.Find().SetSort(sort).SetProjection(projection),
+	); err != nil {
+		return err
+	}
+
+	return deleteIDs()
+}
+
+// snapshotRetainer decides the documents kept by pruneStates; the documents
+// of a group are given in the descending order of height. The document
+// effective at the height before the pruned height and the ones effective at
+// the snapshots are kept.
+type snapshotRetainer struct {
+	state PruneState
+	group string
+	need  int64
+}
+
+func (r *snapshotRetainer) keep(group string, height int64) bool {
+	if group != r.group {
+		r.group, r.need = group, r.state.Height.Int64()-1
+	}
+
+	if height > r.need {
+		return false
+	}
+
+	// NOTE the document is effective from the height to need; the next needed
+	// one is effective at the last snapshot before it.
+	r.need = base.NilHeight.Int64()
+	if i := int64(r.state.SnapshotInterval); i > 0 && height > 0 {
+		r.need = ((height - 1) / i) * i
+	}
+
+	return true
+}
+
+func ProcessStartPaymentPruner(ctx context.Context) (context.Context, error) {
+	var policy RetentionPolicy
+	if err := util.LoadFromContext(ctx, ContextValuePaymentRetention, &policy); err != nil {
+		return ctx, err
+	}
+
+	if !policy.Enabled() {
+		return ctx, nil
+	}
+
+	var log *logging.Logging
+	var st *cdigest.Database
+
+	if err := util.LoadFromContextOK(ctx, launch.LoggingContextKey, &log); err != nil {
+		return ctx, err
+	}
+
+	if err := util.LoadFromContext(ctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return ctx, err
+	}
+
+	if st == nil {
+		return ctx, nil
+	}
+
+	pruner := NewPaymentPruner(st, policy)
+	_ = pruner.SetLogging(log)
+
+	if err := pruner.Start(context.Background()); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValuePaymentPruner, pruner), nil
+}
+
+func ProcessClosePaymentPruner(ctx context.Context) (context.Context, error) {
+	var pruner *PaymentPruner
+	if err := util.LoadFromContext(ctx, ContextValuePaymentPruner, &pruner); err != nil {
+		return ctx, err
+	}
+
+	if pruner == nil {
+		return ctx, nil
+	}
+
+	if err := pruner.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
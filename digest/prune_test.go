@@ -0,0 +1,85 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+)
+
+func TestSnapshotRetainer(t *testing.T) {
+	r := &snapshotRetainer{state: PruneState{Height: 100, SnapshotInterval: 10}}
+
+	// NOTE 95 is effective at 99, 90 at the snapshot 90, 72 at 80 and 70, 60
+	// at 60, 41 at 50, 5 at 40 to 10 and 0 at 0.
+	for i, c := range []struct {
+		group  string
+		height int64
+		kept   bool
+	}{
+		{"a", 95, true},
+		{"a", 90, true},
+		{"a", 85, false},
+		{"a", 72, true},
+		{"a", 71, false},
+		{"a", 60, true},
+		{"a", 41, true},
+		{"a", 5, true},
+		{"a", 0, true},
+		{"b", 50, true},
+		{"b", 49, false},
+		{"b", 40, true},
+		{"b", 30, true},
+	} {
+		if r.keep(c.group, c.height) != c.kept {
+			t.Errorf("%d: %s at %d, kept %v", i, c.group, c.height, !c.kept)
+		}
+	}
+
+	// NOTE without the snapshots, only the document effective at the pruned
+	// height is kept.
+	r = &snapshotRetainer{state: PruneState{Height: 100}}
+
+	for i, height := range []int64{95, 90, 0} {
+		if r.keep("a", height) != (i == 0) {
+			t.Errorf("%d: height %d kept %v", i, height, i != 0)
+		}
+	}
+
+	if s := (PruneState{Height: 100, SnapshotInterval: 10}); !s.isSnapshot(90) || s.isSnapshot(95) {
+		t.Error("wrong snapshot")
+	}
+}
+
+func TestRetainedHeight(t *testing.T) {
+	heightAt := func(t time.Time) (base.Height, error) {
+		if t.Unix() < 100 {
+			return base.NilHeight, util.ErrNotFound.Errorf("no block")
+		}
+
+		return base.Height(t.Unix() / 10), nil
+	}
+
+	for name, c := range map[string]struct {
+		policy RetentionPolicy
+		last   base.Height
+		height base.Height
+	}{
+		"no block":             {RetentionPolicy{Heights: 10}, base.NilHeight, base.NilHeight},
+		"heights":              {RetentionPolicy{Heights: 10}, 100, 91},
+		"heights over last":    {RetentionPolicy{Heights: 200}, 100, -99},
+		"since":                {RetentionPolicy{Since: 500}, 100, 51},
+		"since before genesis": {RetentionPolicy{Since: 50}, 100, base.GenesisHeight},
+		"longer since":         {RetentionPolicy{Heights: 10, Since: 500}, 100, 51},
+		"longer heights":       {RetentionPolicy{Heights: 60, Since: 500}, 100, 41},
+	} {
+		height, err := c.policy.retainedHeight(c.last, heightAt)
+		switch {
+		case err != nil:
+			t.Errorf("%s: %v", name, err)
+		case height != c.height:
+			t.Errorf("%s: height %v, not %v", name, height, c.height)
+		}
+	}
+}
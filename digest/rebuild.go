@@ -0,0 +1,239 @@
+package digest
+
+import (
+	"context"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/fixedtree"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var DefaultColNamePaymentRebuild = "digest_pmt_rebuild"
+
+const rebuildProgressID = "progress"
+
+// paymentHeightCollections is the collections which keep the documents by
+// height; the documents of a height are replaced when the height is rebuilt.
+func paymentHeightCollections() []string {
+	return []string{
+		DefaultColNamePayment,
+		DefaultColNamePaymentAccount,
+		DefaultColNamePaymentEvent,
+		DefaultColNamePaymentLock,
+		DefaultColNamePaymentStats,
+		DefaultColNamePaymentReceived,
+	}
+}
+
+// RebuildProgress is the height range of the rebuild and the last rebuilt
+// height in it.
+type RebuildProgress struct {
+	From   base.Height `bson:"from"`
+	To     base.Height `bson:"to"`
+	Height base.Height `bson:"height"`
+}
+
+func (p RebuildProgress) Done() bool {
+	return p.Height >= p.To
+}
+
+// ResumeRebuildProgress returns the progress of the rebuild from from to to;
+// it continues after the last rebuilt height of prev when prev is the
+// unfinished rebuild from the same height.
+func ResumeRebuildProgress(from, to base.Height, prev *RebuildProgress) RebuildProgress {
+	p := RebuildProgress{From: from, To: to, Height: from - 1}
+
+	if prev != nil && prev.From == from && !prev.Done() {
+		p.Height = prev.Height
+	}
+
+	return p
+}
+
+func LoadRebuildProgress(db *cdigest.Database) (RebuildProgress, bool, error) {
+	var p RebuildProgress
+
+	switch err := db.MongoClient().Collection(DefaultColNamePaymentRebuild).FindOne(
+		context.Background(),
+		bson.M{"_id": rebuildProgressID},
+	).Decode(&p); {
+	case err == nil:
+		return p, true, nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return p, false, nil
+	default:
+		return p, false, err
+	}
+}
+
+func SaveRebuildProgress(db *cdigest.Database, p RebuildProgress) error {
+	_, err := db.MongoClient().Collection(DefaultColNamePaymentRebuild).ReplaceOne(
+		context.Background(),
+		bson.M{"_id": rebuildProgressID},
+		p,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+// RebuildPaymentBlock replaces the payment documents of the block with the
+// ones prepared again from the states of the block. The current collections
+// are updated only with current; they should not go back to the older
+// blocks than the last digested one. The documents of the block are written
+// in a transaction, so the block can be rebuilt again after failure.
+func RebuildPaymentBlock(
+	ctx context.Context,
+	db *cdigest.Database,
+	bm base.BlockMap,
+	pr base.ProposalSignFact,
+	ops []base.Operation,
+	opsTree fixedtree.Tree,
+	sts []base.State,
+	current bool,
+) error {
+	bs, err := cdigest.NewBlockSession(db, bm, ops, opsTree, sts, nil, pr, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = bs.Close()
+	}()
+
+	prepares := []cdigest.BlockSessionPrepareFunc{PreparePayment, PreparePaymentStats, PreparePaymentReceived}
+	if current {
+		prepares = append(prepares, PreparePaymentCurrent)
+	}
+
+	for i := range sts {
+		for _, prepare := range prepares {
+			col, models, err := prepare(bs, sts[i])
+			if err != nil {
+				return err
+			}
+
+			if len(models) > 0 {
+				bs.WriteModels[col] = append(bs.WriteModels[col], models...)
+			}
+		}
+	}
+
+	height := bm.Manifest().Height()
+
+	_, err = db.MongoClient().WithSession(
+		ctx,
+		func(txnCtx context.Context, collection func(string) *mongo.Collection) (interface{}, error) {
+			for _, col := range paymentHeightCollections() {
+				if _, err := collection(col).DeleteMany(txnCtx, bson.M{"height": height}); err != nil {
+					return nil, err
+				}
+			}
+
+			for col, models := range bs.WriteModels {
+				if _, err := collection(col).BulkWrite(txnCtx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+					return nil, err
+				}
+			}
+
+			return nil, nil
+		},
+	)
+
+	return err
+}
+
+const backfillCurrentID = "backfill-current"
+
+// BackfillPaymentCurrent fills the current collections with the last
+// documents of the history collections, which were digested before the current
+// collections were kept. It runs once; the documents already updated by the
+// newer blocks are kept.
+func BackfillPaymentCurrent(ctx context.Context, db *cdigest.Database) error {
+	rebuild := db.MongoClient().Collection(DefaultColNamePaymentRebuild)
+
+	switch err := rebuild.FindOne(ctx, bson.M{"_id": backfillCurrentID}).Err(); {
+	case err == nil:
+		return nil
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return err
+	}
+
+	for _, i := range []struct {
+		current string
+		history string
+		keys    []string
+	}{
+		{current: DefaultColNamePaymentCurrent, history: DefaultColNamePayment, keys: []string{"contract"}},
+		{
+			current: DefaultColNamePaymentAccountCurrent, history: DefaultColNamePaymentAccount,
+			keys: []string{"contract", "address"},
+		},
+	} {
+		if err := backfillCurrent(ctx, db, i.current, i.history, i.keys); err != nil {
+			return errors.WithMessagef(err, "backfill %s", i.current)
+		}
+	}
+
+	_, err := rebuild.InsertOne(ctx, bson.M{"_id": backfillCurrentID})
+
+	return err
+}
+
+func backfillCurrent(ctx context.Context, db *cdigest.Database, current, history string, keys []string) error {
+	group := bson.D{}
+	for _, k := range keys {
+		group = append(group, bson.E{Key: k, Value: "$" + k})
+	}
+
+	cursor, err := db.MongoClient().Collection(history).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "height", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: group},
+			{Key: "doc", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$replaceWith", Value: "$doc"}},
+		{{Key: "$unset", Value: "_id"}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var models []mongo.WriteModel
+
+	for cursor.Next(ctx) {
+		doc := make(bson.Raw, len(cursor.Current))
+		copy(doc, cursor.Current)
+
+		filter := bson.D{}
+		for _, k := range keys {
+			filter = append(filter, bson.E{Key: k, Value: doc.Lookup(k)})
+		}
+
+		height, ok := doc.Lookup("height").AsInt64OK()
+		if !ok {
+			return errors.Errorf("invalid height of %v", filter)
+		}
+
+		models = append(models, currentDocUpdate(filter, doc, base.Height(height)))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	if len(models) < 1 {
+		return nil
+	}
+
+	_, err = db.MongoClient().Collection(current).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+	return err
+}
@@ -0,0 +1,35 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/mitum2/base"
+)
+
+func TestResumeRebuildProgress(t *testing.T) {
+	for name, c := range map[string]struct {
+		prev   *RebuildProgress
+		height base.Height
+	}{
+		"no progress":  {nil, 1},
+		"unfinished":   {&RebuildProgress{From: 2, To: 10, Height: 5}, 5},
+		"other to":     {&RebuildProgress{From: 2, To: 8, Height: 5}, 5},
+		"other from":   {&RebuildProgress{From: 0, To: 10, Height: 5}, 1},
+		"done":         {&RebuildProgress{From: 2, To: 10, Height: 10}, 1},
+		"nothing done": {&RebuildProgress{From: 2, To: 10, Height: 1}, 1},
+	} {
+		p := ResumeRebuildProgress(2, 10, c.prev)
+
+		switch {
+		case p.From != 2, p.To != 10:
+			t.Errorf("%s: range %v-%v", name, p.From, p.To)
+		case p.Height != c.height:
+			t.Errorf("%s: resumed after %v, not %v", name, p.Height, c.height)
+		}
+	}
+
+	// NOTE the rebuild from genesis starts before it.
+	if p := ResumeRebuildProgress(base.GenesisHeight, 10, nil); p.Height != base.NilHeight || p.Done() {
+		t.Errorf("rebuild from genesis, %v", p.Height)
+	}
+}
@@ -0,0 +1,213 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var DefaultColNamePaymentReceived = "digest_pmt_rcv"
+
+// isReceivedEvent checks whether the counterparty of the event is paid by it.
+func isReceivedEvent(ev types.Event) bool {
+	switch ev.Type {
+	case types.EventTypeTransfer, types.EventTypeClaimVoucher, types.EventTypeClaimTransfer:
+		return len(ev.Counterparty) > 0
+	default:
+		return false
+	}
+}
+
+// PreparePaymentReceived indexes the payments appended in the height of the
+// event log state by the receivers.
+func PreparePaymentReceived(_ *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
+	if !state.IsEventLogStateKey(st.Key()) {
+		return "", nil, nil
+	}
+
+	log, err := state.GetEventLogFromState(st)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parsedKey, err := cstate.ParseStateKey(st.Key(), state.PaymentStateKeyPrefix, 4)
+	if err != nil {
+		return "", nil, err
+	}
+
+	contract, sender := parsedKey[1], log.Address().String()
+
+	var models []mongo.WriteModel
+	for _, ev := range log.Events() {
+		if ev.Height != st.Height().Int64() || !isReceivedEvent(ev) {
+			continue
+		}
+
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{
+				{Key: "contract", Value: contract},
+				{Key: "sender", Value: sender},
+				{Key: "sequence", Value: ev.Sequence},
+			}).
+			SetReplacement(NewReceivedDoc(contract, sender, ev)).
+			SetUpsert(true),
+		)
+	}
+
+	return DefaultColNamePaymentReceived, models, nil
+}
+
+// ReceivedDoc is the payment to the counterparty of the event.
+type ReceivedDoc struct {
+	contract string
+	sender   string
+	event    types.Event
+}
+
+func NewReceivedDoc(contract, sender string, event types.Event) ReceivedDoc {
+	return ReceivedDoc{
+		contract: contract,
+		sender:   sender,
+		event:    event,
+	}
+}
+
+func (doc ReceivedDoc) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bson.M{
+		"receiver":    doc.event.Counterparty,
+		"contract":    doc.contract,
+		"sender":      doc.sender,
+		"sequence":    doc.event.Sequence,
+		"type":        doc.event.Type,
+		"currency":    doc.event.Currency,
+		"amount":      doc.event.Amount,
+		"fact_hash":   doc.event.FactHash,
+		"proposed_at": doc.event.ProposedAt,
+		"height":      doc.event.Height,
+	})
+}
+
+type receivedDocBSONUnmarshaler struct {
+	Receiver    string `bson:"receiver"`
+	Contract    string `bson:"contract"`
+	Sender      string `bson:"sender"`
+	types.Event `bson:",inline"`
+}
+
+func loadReceivedValue(decoder func(interface{}) error) (EventValue, error) {
+	var u receivedDocBSONUnmarshaler
+	if err := decoder(&u); err != nil {
+		return EventValue{}, err
+	}
+
+	u.Event.Counterparty = u.Receiver
+
+	return NewEventValue(u.Contract, u.Sender, u.Event), nil
+}
+
+// BuildReceivedOffset returns the offset of the payment,
+// "<height>,<contract>,<sender>,<sequence>".
+func BuildReceivedOffset(va EventValue) string {
+	return fmt.Sprintf("%d,%s,%s,%d", va.Event().Height, va.Contract(), va.Address(), va.Event().Sequence)
+}
+
+func buildReceivedFilter(receiver, offset string, reverse bool) (bson.M, error) {
+	filter := bson.M{"receiver": receiver}
+	if len(offset) < 1 {
+		return filter, nil
+	}
+
+	n := strings.SplitN(offset, ",", 4)
+	if len(n) < 4 || len(n[1]) < 1 || len(n[2]) < 1 {
+		return nil, errors.Errorf("invalid offset, %q", offset)
+	}
+
+	height, err := base.ParseHeightString(n[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid height of offset")
+	}
+
+	sequence, err := strconv.ParseUint(n[3], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid sequence of offset")
+	}
+
+	op := "$gt"
+	if reverse {
+		op = "$lt"
+	}
+
+	h := height.Int64()
+	filter["$or"] = []bson.M{
+		{"height": bson.M{op: h}},
+		{"height": h, "contract": bson.M{op: n[1]}},
+		{"height": h, "contract": n[1], "sender": bson.M{op: n[2]}},
+		{"height": h, "contract": n[1], "sender": n[2], "sequence": bson.M{op: int64(sequence)}},
+	}
+
+	return filter, nil
+}
+
+// PaymentsReceived calls callback with the payments to the receiver from all
+// the contract accounts, ordered by height, contract, sender and sequence.
+// The counterparty of the event is the receiver.
+func PaymentsReceived(
+	db *cdigest.Database,
+	receiver string,
+	reverse bool,
+	offset string,
+	limit int64,
+	callback func(EventValue) (bool, error),
+) error {
+	filter, err := buildReceivedFilter(receiver, offset, reverse)
+	if err != nil {
+		return err
+	}
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(bson.D{
+		{Key: "height", Value: sr},
+		{Key: "contract", Value: sr},
+		{Key: "sender", Value: sr},
+		{Key: "sequence", Value: sr},
+	})
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	return db.MongoClient().Find(
+		context.Background(),
+		DefaultColNamePaymentReceived,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			va, err := loadReceivedValue(cursor.Decode)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(va)
+		},
+		opt,
+	)
+}
@@ -0,0 +1,96 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func newTestReceivedEvent(t, counterparty string, height base.Height) types.Event {
+	return types.NewEvent(t, "MCC", common.NewBig(10), counterparty, valuehash.RandomSHA256(), 100, height)
+}
+
+func TestPreparePaymentReceived(t *testing.T) {
+	sender := ctypes.NewStringAddress("sender")
+
+	st := eventLogState(sender, 3,
+		newTestReceivedEvent(types.EventTypeTransfer, "receivera", 2),
+		newTestReceivedEvent(types.EventTypeTransfer, "receivera", 3),
+		newTestReceivedEvent(types.EventTypeClaimTransfer, "receiverb", 3),
+		newTestReceivedEvent(types.EventTypeClaimVoucher, "receiverc", 3),
+		newTestReceivedEvent(types.EventTypeLockTransfer, "receiverd", 3),
+		newTestReceivedEvent(types.EventTypeWithdrawPenalty, "receivere", 3),
+		newTestReceivedEvent(types.EventTypeDeposit, "", 3),
+	)
+
+	col, models, err := PreparePaymentReceived(nil, st)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case col != DefaultColNamePaymentReceived:
+		t.Errorf("collection %q", col)
+	case len(models) != 3:
+		t.Fatalf("%d models", len(models))
+	}
+
+	// NOTE only the payments of the height are indexed; the lock is paid at
+	// the claim.
+	for i, receiver := range []string{"receivera", "receiverb", "receiverc"} {
+		m, ok := models[i].(*mongo.ReplaceOneModel)
+		if !ok {
+			t.Fatalf("expected ReplaceOneModel, not %T", models[i])
+		}
+
+		b, err := bson.Marshal(m.Replacement)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		va, err := loadReceivedValue(func(i interface{}) error { return bson.Unmarshal(b, i) })
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case va.Contract() != testStatsContract, va.Address() != sender.String():
+			t.Errorf("%d: contract %q, sender %q", i, va.Contract(), va.Address())
+		case va.Event().Counterparty != receiver, va.Event().Height != 3:
+			t.Errorf("%d: receiver %q, height %d", i, va.Event().Counterparty, va.Event().Height)
+		}
+	}
+
+	if _, models, err := PreparePaymentReceived(nil, depositRecordState(sender, 3, 10)); err != nil || len(models) > 0 {
+		t.Errorf("deposit record indexed, %v", err)
+	}
+}
+
+func TestBuildReceivedFilter(t *testing.T) {
+	va := NewEventValue("contract", "sender", newTestReceivedEvent(types.EventTypeTransfer, "receiver", 3))
+
+	filter, err := buildReceivedFilter("receiver", BuildReceivedOffset(va), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	or, ok := filter["$or"].([]bson.M)
+	switch {
+	case filter["receiver"] != "receiver":
+		t.Errorf("receiver %v", filter["receiver"])
+	case !ok || len(or) != 4:
+		t.Fatalf("offset filter %v", filter["$or"])
+	case or[0]["height"].(bson.M)["$lt"] != int64(3):
+		t.Errorf("height of offset %v", or[0])
+	case or[3]["contract"] != "contract", or[3]["sender"] != "sender":
+		t.Errorf("sequence of offset %v", or[3])
+	}
+
+	for _, offset := range []string{"3,contract,sender", "3,,sender,0", "a,contract,sender,0", "3,contract,sender,a"} {
+		if _, err := buildReceivedFilter("receiver", offset, false); err == nil {
+			t.Errorf("invalid offset, %q built", offset)
+		}
+	}
+}
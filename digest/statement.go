@@ -0,0 +1,309 @@
+package digest
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var StatementValueHint = hint.MustNewHint("mitum-payment-statement-value-v0.0.1")
+
+// statementEventTypes is the event types which change the deposit of the
+// account.
+var statementEventTypes = []string{
+	types.EventTypeDeposit,
+	types.EventTypeTransfer,
+	types.EventTypeLockTransfer,
+	types.EventTypeClaimVoucher,
+	types.EventTypeWithdraw,
+	types.EventTypeWithdrawPenalty,
+}
+
+// statementChange returns the change of the deposit by the event.
+func statementChange(ev types.Event) common.Big {
+	if ev.Type == types.EventTypeDeposit {
+		return ev.Amount
+	}
+
+	return ev.Amount.Neg()
+}
+
+// StatementEntry is an event which changes the deposit; Balance is the
+// deposit after it.
+type StatementEntry struct {
+	Height        base.Height `json:"height"`
+	ProposedAt    uint64      `json:"proposed_at"`
+	Sequence      uint64      `json:"sequence"`
+	Type          string      `json:"type"`
+	Counterparty  string      `json:"counterparty,omitempty"`
+	FactHash      string      `json:"fact_hash"`
+	OperationHash string      `json:"operation_hash,omitempty"`
+	Amount        common.Big  `json:"amount"`
+	Change        common.Big  `json:"change"`
+	Balance       common.Big  `json:"balance"`
+}
+
+// StatementValue is the statement of the deposit of an account in a currency
+// from FromHeight to ToHeight. OpeningBalance is the deposit before FromHeight
+// and ClosingBalance is the deposit at ToHeight; Reconciled is whether the
+// entries lead from the opening to the closing.
+type StatementValue struct {
+	hint.BaseHinter
+	Contract       string           `json:"contract"`
+	Account        string           `json:"account"`
+	Currency       string           `json:"currency"`
+	From           uint64           `json:"from,omitempty"`
+	To             uint64           `json:"to"`
+	FromHeight     base.Height      `json:"from_height"`
+	ToHeight       base.Height      `json:"to_height"`
+	OpeningBalance common.Big       `json:"opening_balance"`
+	TotalCredit    common.Big       `json:"total_credit"`
+	TotalDebit     common.Big       `json:"total_debit"`
+	ClosingBalance common.Big       `json:"closing_balance"`
+	Reconciled     bool             `json:"reconciled"`
+	Entries        []StatementEntry `json:"entries"`
+}
+
+// PaymentStatement returns the statement of the deposit of the account in the
+// time range from, to in unix seconds; from 0 is the first block and to 0 is
+// the last block. The statement is built from the payment history.
+func PaymentStatement(
+	db *cdigest.Database, contract, account, cid string, from, to uint64,
+) (StatementValue, error) {
+	if !PaymentHistory() {
+		return StatementValue{}, errHistoryNotKept
+	}
+
+	fromHeight, toHeight, err := statementHeights(db, from, to)
+	if err != nil {
+		return StatementValue{}, err
+	}
+
+	if to < 1 {
+		if to, err = BlockTime(db, toHeight); err != nil {
+			return StatementValue{}, err
+		}
+	}
+
+	// NOTE the events before the pruned height are removed without the
+	// snapshots; the deposit records effective at the height before it are
+	// kept.
+	switch s, found, err := LoadPruneState(db); {
+	case err != nil:
+		return StatementValue{}, err
+	case found && fromHeight < s.Height:
+		return StatementValue{}, errPruned("height %d", fromHeight)
+	}
+
+	opening := common.ZeroBig
+	if fromHeight > base.GenesisHeight {
+		if opening, err = depositAmountAt(db, contract, account, cid, fromHeight-1); err != nil {
+			return StatementValue{}, err
+		}
+	}
+
+	closing, err := depositAmountAt(db, contract, account, cid, toHeight)
+	if err != nil {
+		return StatementValue{}, err
+	}
+
+	va := StatementValue{
+		BaseHinter:     hint.NewBaseHinter(StatementValueHint),
+		Contract:       contract,
+		Account:        account,
+		Currency:       cid,
+		From:           from,
+		To:             to,
+		FromHeight:     fromHeight,
+		ToHeight:       toHeight,
+		OpeningBalance: opening,
+		TotalCredit:    common.ZeroBig,
+		TotalDebit:     common.ZeroBig,
+		ClosingBalance: closing,
+		Entries:        []StatementEntry{},
+	}
+
+	f := NewEventFilter()
+	f.Address = account
+	f.Currency = cid
+	f.Types = statementEventTypes
+	f.FromHeight = fromHeight
+	f.ToHeight = toHeight
+
+	opHashes := map[string]string{}
+
+	if err := PaymentEvents(db, contract, f, false, "", 0, func(ev EventValue) (bool, error) {
+		e := ev.Event()
+
+		opHash, found := opHashes[e.FactHash]
+		if !found {
+			i, err := operationHashByFact(db, e.FactHash)
+			if err != nil {
+				return false, err
+			}
+
+			opHash, opHashes[e.FactHash] = i, i
+		}
+
+		va.addEntry(e, opHash)
+
+		return true, nil
+	}); err != nil {
+		return StatementValue{}, err
+	}
+
+	va.Reconciled = va.balance().Equal(closing)
+
+	return va, nil
+}
+
+// balance returns the deposit after the last entry.
+func (va StatementValue) balance() common.Big {
+	if n := len(va.Entries); n > 0 {
+		return va.Entries[n-1].Balance
+	}
+
+	return va.OpeningBalance
+}
+
+// addEntry appends the entry of the event to the statement and adds the change
+// by it to the totals.
+func (va *StatementValue) addEntry(e types.Event, opHash string) {
+	change := statementChange(e)
+
+	if change.OverZero() {
+		va.TotalCredit = va.TotalCredit.Add(change)
+	} else {
+		va.TotalDebit = va.TotalDebit.Add(e.Amount)
+	}
+
+	va.Entries = append(va.Entries, StatementEntry{
+		Height:        base.Height(e.Height),
+		ProposedAt:    e.ProposedAt,
+		Sequence:      e.Sequence,
+		Type:          e.Type,
+		Counterparty:  e.Counterparty,
+		FactHash:      e.FactHash,
+		OperationHash: opHash,
+		Amount:        e.Amount,
+		Change:        change,
+		Balance:       va.balance().Add(change),
+	})
+}
+
+// statementHeights returns the heights of the blocks in the time range.
+func statementHeights(db *cdigest.Database, from, to uint64) (base.Height, base.Height, error) {
+	fromHeight, toHeight := base.GenesisHeight, db.LastBlock()
+	if toHeight < fromHeight {
+		return base.NilHeight, base.NilHeight, util.ErrNotFound.Errorf("no block")
+	}
+
+	if to > 0 {
+		i, err := HeightAt(db, time.Unix(int64(to), 0))
+		if err != nil {
+			return base.NilHeight, base.NilHeight, err
+		}
+
+		toHeight = i
+	}
+
+	if from > 0 {
+		switch i, err := HeightAt(db, time.Unix(int64(from), 0).Add(-time.Nanosecond)); {
+		case errors.Is(err, util.ErrNotFound):
+		case err != nil:
+			return base.NilHeight, base.NilHeight, err
+		default:
+			fromHeight = i + 1
+		}
+	}
+
+	if fromHeight > toHeight {
+		return base.NilHeight, base.NilHeight, util.ErrNotFound.Errorf("no block from %d to %d", from, to)
+	}
+
+	return fromHeight, toHeight, nil
+}
+
+// depositAmountAt returns the deposit of the account in the currency as of
+// the block of height.
+func depositAmountAt(db *cdigest.Database, contract, account, cid string, height base.Height) (common.Big, error) {
+	record, err := lastDepositRecord(db, contract, account, bson.M{"$lte": height})
+	if err != nil {
+		return common.ZeroBig, err
+	}
+
+	if am := record.Amount(cid); am != nil {
+		return *am, nil
+	}
+
+	return common.ZeroBig, nil
+}
+
+// operationHashByFact returns the hash of the operation of the fact hash; the
+// empty string when the operation is not digested.
+func operationHashByFact(db *cdigest.Database, factHash string) (string, error) {
+	switch va, found, err := db.Operation(valuehash.NewBytesFromString(factHash), true); {
+	case err != nil:
+		return "", err
+	case !found:
+		return "", nil
+	default:
+		return va.Operation().Hash().String(), nil
+	}
+}
+
+var statementCSVHeader = []string{
+	"height", "proposed_at", "sequence", "type", "counterparty",
+	"fact_hash", "operation_hash", "amount", "change", "balance",
+}
+
+// WriteCSV writes the statement in CSV; the opening and the closing balances
+// are the first and the last rows.
+func (va StatementValue) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		statementCSVHeader,
+		{va.FromHeight.String(), strconv.FormatUint(va.From, 10), "", "opening", "", "", "", "", "",
+			va.OpeningBalance.String()},
+	}
+
+	for i := range va.Entries {
+		e := va.Entries[i]
+
+		rows = append(rows, []string{
+			e.Height.String(),
+			strconv.FormatUint(e.ProposedAt, 10),
+			strconv.FormatUint(e.Sequence, 10),
+			e.Type,
+			e.Counterparty,
+			e.FactHash,
+			e.OperationHash,
+			e.Amount.String(),
+			e.Change.String(),
+			e.Balance.String(),
+		})
+	}
+
+	rows = append(rows, []string{
+		va.ToHeight.String(), strconv.FormatUint(va.To, 10), "", "closing", "", "", "", "",
+		va.TotalCredit.Sub(va.TotalDebit).String(), va.ClosingBalance.String(),
+	})
+
+	if err := cw.WriteAll(rows); err != nil {
+		return errors.WithMessage(err, "write statement csv")
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func newTestStatement(opening, closing int64, evs ...types.Event) StatementValue {
+	va := StatementValue{
+		FromHeight:     2,
+		ToHeight:       4,
+		OpeningBalance: common.NewBig(opening),
+		TotalCredit:    common.ZeroBig,
+		TotalDebit:     common.ZeroBig,
+		ClosingBalance: common.NewBig(closing),
+		Entries:        []StatementEntry{},
+	}
+
+	for i := range evs {
+		va.addEntry(evs[i], "")
+	}
+
+	va.Reconciled = va.balance().Equal(va.ClosingBalance)
+
+	return va
+}
+
+func TestStatementReconcile(t *testing.T) {
+	evs := []types.Event{
+		newTestStatsEvent(types.EventTypeDeposit, 50, 2),
+		newTestStatsEvent(types.EventTypeTransfer, 30, 3),
+		newTestStatsEvent(types.EventTypeLockTransfer, 20, 3),
+		newTestStatsEvent(types.EventTypeWithdraw, 60, 4),
+		newTestStatsEvent(types.EventTypeWithdrawPenalty, 10, 4),
+	}
+
+	va := newTestStatement(100, 30, evs...)
+
+	switch {
+	case !va.Reconciled:
+		t.Errorf("not reconciled, %v", va.balance())
+	case !va.TotalCredit.Equal(common.NewBig(50)), !va.TotalDebit.Equal(common.NewBig(120)):
+		t.Errorf("credit %v, debit %v", va.TotalCredit, va.TotalDebit)
+	case len(va.Entries) != len(evs):
+		t.Fatalf("%d entries", len(va.Entries))
+	}
+
+	for i, balance := range []int64{150, 120, 100, 40, 30} {
+		if e := va.Entries[i]; !e.Balance.Equal(common.NewBig(balance)) {
+			t.Errorf("%d: balance %v, not %d", i, e.Balance, balance)
+		}
+	}
+
+	// NOTE the statement of the missing entries is not reconciled.
+	if va := newTestStatement(100, 30, evs[:4]...); va.Reconciled {
+		t.Error("statement without penalty reconciled")
+	}
+
+	if va := newTestStatement(100, 100); !va.Reconciled || !va.balance().Equal(common.NewBig(100)) {
+		t.Error("statement without entries not reconciled")
+	}
+}
+
+func TestStatementWriteCSV(t *testing.T) {
+	va := newTestStatement(100, 120,
+		newTestStatsEvent(types.EventTypeDeposit, 50, 2),
+		newTestStatsEvent(types.EventTypeTransfer, 30, 3),
+	)
+
+	var buf bytes.Buffer
+	if err := va.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := len(statementCSVHeader) - 1
+
+	switch {
+	case len(rows) != 5:
+		t.Fatalf("%d rows", len(rows))
+	case rows[1][3] != "opening", rows[1][last] != "100":
+		t.Errorf("opening row %v", rows[1])
+	case rows[3][3] != types.EventTypeTransfer, rows[3][last-1] != "-30", rows[3][last] != "120":
+		t.Errorf("entry row %v", rows[3])
+	case rows[4][3] != "closing", rows[4][last-1] != "20", rows[4][last] != "120":
+		t.Errorf("closing row %v", rows[4])
+	}
+}
@@ -0,0 +1,538 @@
+package digest
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var DefaultColNamePaymentStats = "digest_pmt_stats"
+
+const statsWriteBatch = 1000
+
+// CurrencyStats is the aggregates of a currency in a contract account at a
+// height. Transferred and Withdrawn are accumulated from the first block.
+type CurrencyStats struct {
+	Deposited   common.Big `bson:"deposited"`
+	Depositors  int64      `bson:"depositors"`
+	Transferred common.Big `bson:"transferred"`
+	Withdrawn   common.Big `bson:"withdrawn"`
+	// EndTimes is the sorted end times of the settings.
+	EndTimes []uint64 `bson:"end_times"`
+}
+
+func newCurrencyStats() *CurrencyStats {
+	return &CurrencyStats{
+		Deposited:   common.ZeroBig,
+		Transferred: common.ZeroBig,
+		Withdrawn:   common.ZeroBig,
+	}
+}
+
+// StatsDoc keeps the aggregates of a contract account at a height. One
+// document is written for each height where the payment states of the
+// contract account are changed. Changes is the changes of the aggregates by
+// the block, so the aggregates of the later heights can be recomputed after
+// the block is rebuilt.
+type StatsDoc struct {
+	Contract   string                    `bson:"contract"`
+	Height     int64                     `bson:"height"`
+	ProposedAt int64                     `bson:"proposed_at"`
+	Currencies map[string]*CurrencyStats `bson:"currencies"`
+	Changes    map[string]*CurrencyStats `bson:"changes"`
+	deposits   map[string]*blockDeposit
+}
+
+// blockDeposit is the deposit record of an account after the block and the
+// changes of the deposits by the events of the block.
+type blockDeposit struct {
+	record  *types.DepositRecord
+	deltas  map[string]common.Big
+	counted map[string]int64
+}
+
+func (doc *StatsDoc) currency(cid string) *CurrencyStats {
+	if doc.Currencies == nil {
+		doc.Currencies = map[string]*CurrencyStats{}
+	}
+
+	c, found := doc.Currencies[cid]
+	if !found {
+		c = newCurrencyStats()
+		doc.Currencies[cid] = c
+	}
+
+	return c
+}
+
+// change applies f to the aggregates of the currency and the changes of the
+// block.
+func (doc *StatsDoc) change(cid string, f func(*CurrencyStats)) {
+	f(doc.currency(cid))
+
+	if doc.Changes == nil {
+		doc.Changes = map[string]*CurrencyStats{}
+	}
+
+	c, found := doc.Changes[cid]
+	if !found {
+		c = newCurrencyStats()
+		doc.Changes[cid] = c
+	}
+
+	f(c)
+}
+
+func (doc *StatsDoc) deposit(account string) *blockDeposit {
+	if doc.deposits == nil {
+		doc.deposits = map[string]*blockDeposit{}
+	}
+
+	d, found := doc.deposits[account]
+	if !found {
+		d = &blockDeposit{deltas: map[string]common.Big{}, counted: map[string]int64{}}
+		doc.deposits[account] = d
+	}
+
+	return d
+}
+
+// countDepositors counts the depositor by the deposits before and after the
+// block; the deposit before the block is the one after the block without the
+// changes by the events of the block. The deposit record and the event log of
+// the account can be given in any order.
+func (doc *StatsDoc) countDepositors(account string) {
+	d := doc.deposit(account)
+	if d.record == nil {
+		return
+	}
+
+	for cid, delta := range d.deltas {
+		after := common.ZeroBig
+		if am := d.record.Amount(cid); am != nil {
+			after = *am
+		}
+
+		before := after.Sub(delta)
+
+		var n int64
+
+		switch {
+		case !before.OverZero() && after.OverZero():
+			n = 1
+		case before.OverZero() && !after.OverZero():
+			n = -1
+		}
+
+		if diff := n - d.counted[cid]; diff != 0 {
+			doc.change(cid, func(c *CurrencyStats) {
+				c.Depositors += diff
+			})
+		}
+
+		d.counted[cid] = n
+	}
+}
+
+// accumulate sets the aggregates to the ones of prev with the changes of the
+// block; the end times are kept.
+func (doc *StatsDoc) accumulate(prev *StatsDoc) {
+	currencies := map[string]*CurrencyStats{}
+
+	for cid, p := range prev.Currencies {
+		c := *p
+		c.EndTimes = nil
+		currencies[cid] = &c
+	}
+
+	for cid, ch := range doc.Changes {
+		c, found := currencies[cid]
+		if !found {
+			c = newCurrencyStats()
+			currencies[cid] = c
+		}
+
+		c.Deposited = c.Deposited.Add(ch.Deposited)
+		c.Depositors += ch.Depositors
+		c.Transferred = c.Transferred.Add(ch.Transferred)
+		c.Withdrawn = c.Withdrawn.Add(ch.Withdrawn)
+	}
+
+	for cid, c := range doc.Currencies {
+		n, found := currencies[cid]
+		if !found {
+			n = newCurrencyStats()
+			currencies[cid] = n
+		}
+
+		n.EndTimes = c.EndTimes
+	}
+
+	doc.Currencies = currencies
+}
+
+// PreparePaymentStats updates the stats of the contract account of the
+// payment state. The stats document of a height is shared by the states of
+// the same block session.
+func PreparePaymentStats(bs *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
+	var update func(*StatsDoc) error
+
+	switch {
+	case state.IsDesignStateKey(st.Key()):
+		update = func(doc *StatsDoc) error {
+			return updateStatsByDesign(doc, st)
+		}
+	case state.IsDepositRecordStateKey(st.Key()):
+		update = func(doc *StatsDoc) error {
+			return updateStatsByDepositRecord(doc, st)
+		}
+	case state.IsEventLogStateKey(st.Key()):
+		update = func(doc *StatsDoc) error {
+			return updateStatsByEventLog(doc, st)
+		}
+	default:
+		return "", nil, nil
+	}
+
+	parsedKey, err := cstate.ParseStateKey(st.Key(), state.PaymentStateKeyPrefix, 3)
+	if err != nil {
+		return "", nil, err
+	}
+
+	contract := parsedKey[1]
+
+	for _, m := range bs.WriteModels[DefaultColNamePaymentStats] {
+		if r, ok := m.(*mongo.ReplaceOneModel); ok {
+			if doc, ok := r.Replacement.(*StatsDoc); ok && doc.Contract == contract {
+				return "", nil, update(doc)
+			}
+		}
+	}
+
+	doc, err := lastStatsDoc(bs.Database(), contract, bson.M{"$lt": st.Height().Int64()}, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	doc.Contract = contract
+	doc.Height = st.Height().Int64()
+	doc.ProposedAt = bs.BlockMap().Manifest().ProposedAt().Unix()
+	doc.Changes = map[string]*CurrencyStats{}
+
+	if err := update(doc); err != nil {
+		return "", nil, err
+	}
+
+	return DefaultColNamePaymentStats, []mongo.WriteModel{
+		mongo.NewReplaceOneModel().
+			SetFilter(bson.D{
+				{Key: "contract", Value: contract},
+				{Key: "height", Value: doc.Height},
+			}).
+			SetReplacement(doc).
+			SetUpsert(true),
+	}, nil
+}
+
+func updateStatsByDesign(doc *StatsDoc, st base.State) error {
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range doc.Currencies {
+		c.EndTimes = nil
+	}
+
+	for _, setting := range design.AccountSettings() {
+		for cid, item := range setting.Items() {
+			c := doc.currency(cid)
+			c.EndTimes = append(c.EndTimes, item.EndTime)
+		}
+	}
+
+	for _, c := range doc.Currencies {
+		sort.Slice(c.EndTimes, func(i, j int) bool { return c.EndTimes[i] < c.EndTimes[j] })
+	}
+
+	return nil
+}
+
+// updateStatsByDepositRecord counts the depositors by the deposit record
+// after the block. The deposits before the block are not read from the
+// digested records, which can be newer than the block in the rebuild.
+func updateStatsByDepositRecord(doc *StatsDoc, st base.State) error {
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		return err
+	}
+
+	account := record.Address().String()
+	doc.deposit(account).record = record
+	doc.countDepositors(account)
+
+	return nil
+}
+
+// updateStatsByEventLog applies the events of the block; the deposits are
+// changed by the events in the event log of the depositor.
+func updateStatsByEventLog(doc *StatsDoc, st base.State) error {
+	log, err := state.GetEventLogFromState(st)
+	if err != nil {
+		return err
+	}
+
+	account := log.Address().String()
+	d := doc.deposit(account)
+
+	for _, ev := range log.Events() {
+		if ev.Height != st.Height().Int64() {
+			continue
+		}
+
+		var delta common.Big
+
+		switch ev.Type {
+		case types.EventTypeDeposit:
+			delta = ev.Amount
+		case types.EventTypeTransfer, types.EventTypeClaimVoucher:
+			delta = common.ZeroBig.Sub(ev.Amount)
+
+			doc.change(ev.Currency, func(c *CurrencyStats) {
+				c.Transferred = c.Transferred.Add(ev.Amount)
+			})
+		case types.EventTypeLockTransfer:
+			delta = common.ZeroBig.Sub(ev.Amount)
+		case types.EventTypeClaimTransfer:
+			doc.change(ev.Currency, func(c *CurrencyStats) {
+				c.Transferred = c.Transferred.Add(ev.Amount)
+			})
+
+			continue
+		case types.EventTypeWithdraw, types.EventTypeWithdrawPenalty:
+			delta = common.ZeroBig.Sub(ev.Amount)
+
+			doc.change(ev.Currency, func(c *CurrencyStats) {
+				c.Withdrawn = c.Withdrawn.Add(ev.Amount)
+			})
+		default:
+			continue
+		}
+
+		doc.change(ev.Currency, func(c *CurrencyStats) {
+			c.Deposited = c.Deposited.Add(delta)
+		})
+
+		if prev, found := d.deltas[ev.Currency]; found {
+			delta = prev.Add(delta)
+		}
+
+		d.deltas[ev.Currency] = delta
+	}
+
+	doc.countDepositors(account)
+
+	return nil
+}
+
+// RecomputePaymentStats recomputes the aggregates of the stats documents
+// after height with the changes of their blocks, so the documents after the
+// rebuilt blocks follow the rebuilt ones.
+func RecomputePaymentStats(ctx context.Context, db *cdigest.Database, height base.Height) error {
+	col := db.MongoClient().Collection(DefaultColNamePaymentStats)
+
+	cursor, err := col.Find(ctx,
+		bson.M{"height": bson.M{"$gt": height.Int64()}},
+		options.Find().SetSort(bson.D{{Key: "contract", Value: -1}, {Key: "height", Value: 1}}),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	write := func(models []mongo.WriteModel) error {
+		if len(models) < 1 {
+			return nil
+		}
+
+		_, err := col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+		return err
+	}
+
+	var prev *StatsDoc
+	var models []mongo.WriteModel
+
+	for cursor.Next(ctx) {
+		doc := &StatsDoc{}
+		if err := cursor.Decode(doc); err != nil {
+			return err
+		}
+
+		if prev == nil || prev.Contract != doc.Contract {
+			if prev, err = lastStatsDoc(db, doc.Contract, bson.M{"$lte": height.Int64()}, nil); err != nil {
+				return err
+			}
+		}
+
+		doc.accumulate(prev)
+
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{
+				{Key: "contract", Value: doc.Contract},
+				{Key: "height", Value: doc.Height},
+			}).
+			SetReplacement(doc))
+
+		if len(models) >= statsWriteBatch {
+			if err := write(models); err != nil {
+				return err
+			}
+
+			models = nil
+		}
+
+		prev = doc
+	}
+
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	return write(models)
+}
+
+// lastStatsDoc returns the last stats document which matches height and
+// proposedAt filters; the empty document when nothing matched.
+func lastStatsDoc(db *cdigest.Database, contract string, height, proposedAt bson.M) (*StatsDoc, error) {
+	filter := bson.M{"contract": contract}
+	if height != nil {
+		filter["height"] = height
+	}
+
+	if proposedAt != nil {
+		filter["proposed_at"] = proposedAt
+	}
+
+	var doc StatsDoc
+
+	switch err := db.MongoClient().Collection(DefaultColNamePaymentStats).FindOne(
+		context.Background(),
+		filter,
+		options.FindOne().SetSort(bson.D{{Key: "height", Value: -1}}),
+	).Decode(&doc); {
+	case err == nil:
+	case errors.Is(err, mongo.ErrNoDocuments):
+		doc = StatsDoc{Height: base.NilHeight.Int64()}
+	default:
+		return nil, err
+	}
+
+	if doc.Currencies == nil {
+		doc.Currencies = map[string]*CurrencyStats{}
+	}
+
+	return &doc, nil
+}
+
+var StatsValueHint = hint.MustNewHint("mitum-payment-stats-value-v0.0.1")
+
+// CurrencyStatsValue is the aggregates of a currency; Transferred and
+// Withdrawn are in the time range and the others are at the end of it.
+type CurrencyStatsValue struct {
+	Deposited       common.Big `json:"deposited"`
+	Depositors      int64      `json:"depositors"`
+	Transferred     common.Big `json:"transferred"`
+	Withdrawn       common.Big `json:"withdrawn"`
+	ActiveSettings  int        `json:"active_settings"`
+	ExpiredSettings int        `json:"expired_settings"`
+}
+
+type StatsValue struct {
+	hint.BaseHinter
+	Contract   string                        `json:"contract"`
+	Height     base.Height                   `json:"height"`
+	From       uint64                        `json:"from,omitempty"`
+	To         uint64                        `json:"to"`
+	Currencies map[string]CurrencyStatsValue `json:"currencies"`
+}
+
+// PaymentStats returns the stats of the contract account in the time range
+// from, to in unix seconds; from 0 is the first block and to 0 is now.
+func PaymentStats(db *cdigest.Database, contract string, from, to uint64) (StatsValue, error) {
+	if to < 1 {
+		to = uint64(time.Now().Unix())
+	}
+
+	for _, t := range []uint64{from, to} {
+		if t < 1 {
+			continue
+		}
+
+		if err := checkPrunedTime(db, t); err != nil {
+			return StatsValue{}, err
+		}
+	}
+
+	end, err := lastStatsDoc(db, contract, nil, bson.M{"$lte": int64(to)})
+	if err != nil {
+		return StatsValue{}, err
+	}
+
+	if end.Height == base.NilHeight.Int64() {
+		return StatsValue{}, util.ErrNotFound.Errorf("payment stats by contract account %v", contract)
+	}
+
+	start := &StatsDoc{Currencies: map[string]*CurrencyStats{}}
+	if from > 0 {
+		if start, err = lastStatsDoc(db, contract, nil, bson.M{"$lt": int64(from)}); err != nil {
+			return StatsValue{}, err
+		}
+	}
+
+	va := StatsValue{
+		BaseHinter: hint.NewBaseHinter(StatsValueHint),
+		Contract:   contract,
+		Height:     base.Height(end.Height),
+		From:       from,
+		To:         to,
+		Currencies: map[string]CurrencyStatsValue{},
+	}
+
+	for cid, c := range end.Currencies {
+		transferred, withdrawn := c.Transferred, c.Withdrawn
+		if s, found := start.Currencies[cid]; found {
+			transferred = transferred.Sub(s.Transferred)
+			withdrawn = withdrawn.Sub(s.Withdrawn)
+		}
+
+		expired := sort.Search(len(c.EndTimes), func(i int) bool { return c.EndTimes[i] >= to })
+
+		va.Currencies[cid] = CurrencyStatsValue{
+			Deposited:       c.Deposited,
+			Depositors:      c.Depositors,
+			Transferred:     transferred,
+			Withdrawn:       withdrawn,
+			ActiveSettings:  len(c.EndTimes) - expired,
+			ExpiredSettings: expired,
+		}
+	}
+
+	return va, nil
+}
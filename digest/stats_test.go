@@ -0,0 +1,148 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+const testStatsContract = "contract"
+
+func depositRecordState(account base.Address, height base.Height, amount int64) base.State {
+	record := types.NewDepositRecord(account)
+	record.SetItem("MCC", common.NewBig(amount), 1)
+
+	return common.NewBaseState(height, state.DepositRecordStateKey(testStatsContract, account.String()),
+		state.NewDepositRecordStateValue(record), nil, []util.Hash{})
+}
+
+func eventLogState(account base.Address, height base.Height, evs ...types.Event) base.State {
+	log := types.NewEventLog(account)
+	log.Append(evs...)
+
+	return common.NewBaseState(height, state.EventLogStateKey(testStatsContract, account.String()),
+		state.NewEventLogStateValue(log), nil, []util.Hash{})
+}
+
+func eventLogStateAt(account base.Address, evs ...types.Event) base.State {
+	return eventLogState(account, base.Height(evs[len(evs)-1].Height), evs...)
+}
+
+func newTestStatsEvent(t string, amount int64, height base.Height) types.Event {
+	return types.NewEvent(t, "MCC", common.NewBig(amount), "", valuehash.RandomSHA256(), 1, height)
+}
+
+func TestStatsByBlockStates(t *testing.T) {
+	a, b := ctypes.NewStringAddress("accounta"), ctypes.NewStringAddress("accountb")
+
+	// NOTE the current records are newer than the block; the stats do not
+	// depend on them.
+	prev := &StatsDoc{Currencies: map[string]*CurrencyStats{"MCC": {
+		Deposited: common.NewBig(100), Depositors: 1,
+		Transferred: common.ZeroBig, Withdrawn: common.ZeroBig,
+	}}}
+
+	for name, recordFirst := range map[string]bool{"record first": true, "event log first": false} {
+		doc := &StatsDoc{Changes: map[string]*CurrencyStats{}}
+		doc.accumulate(prev)
+
+		sts := []base.State{
+			depositRecordState(a, 3, 0),
+			eventLogStateAt(a,
+				newTestStatsEvent(types.EventTypeDeposit, 100, 2),
+				newTestStatsEvent(types.EventTypeTransfer, 30, 3),
+				newTestStatsEvent(types.EventTypeWithdraw, 60, 3),
+				newTestStatsEvent(types.EventTypeWithdrawPenalty, 10, 3),
+			),
+			depositRecordState(b, 3, 50),
+			eventLogStateAt(b, newTestStatsEvent(types.EventTypeDeposit, 50, 3)),
+		}
+
+		if !recordFirst {
+			sts[0], sts[1], sts[2], sts[3] = sts[1], sts[0], sts[3], sts[2]
+		}
+
+		for _, st := range sts {
+			var err error
+
+			if state.IsDepositRecordStateKey(st.Key()) {
+				err = updateStatsByDepositRecord(doc, st)
+			} else {
+				err = updateStatsByEventLog(doc, st)
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		c := doc.Currencies["MCC"]
+
+		switch {
+		case !c.Deposited.Equal(common.NewBig(50)):
+			t.Errorf("%s: deposited %v", name, c.Deposited)
+		case c.Depositors != 1:
+			t.Errorf("%s: depositors %d", name, c.Depositors)
+		case !c.Transferred.Equal(common.NewBig(30)):
+			t.Errorf("%s: transferred %v", name, c.Transferred)
+		case !c.Withdrawn.Equal(common.NewBig(70)):
+			t.Errorf("%s: withdrawn %v", name, c.Withdrawn)
+		}
+
+		if ch := doc.Changes["MCC"]; !ch.Deposited.Equal(common.NewBig(-50)) || ch.Depositors != 0 {
+			t.Errorf("%s: changes %v, %d", name, ch.Deposited, ch.Depositors)
+		}
+	}
+}
+
+func TestStatsAccumulate(t *testing.T) {
+	prev := &StatsDoc{Currencies: map[string]*CurrencyStats{"MCC": {
+		Deposited: common.NewBig(100), Depositors: 2,
+		Transferred: common.NewBig(10), Withdrawn: common.NewBig(20),
+		EndTimes: []uint64{1, 2},
+	}}}
+
+	doc := &StatsDoc{
+		Currencies: map[string]*CurrencyStats{"MCC": {
+			Deposited: common.NewBig(1), Transferred: common.ZeroBig, Withdrawn: common.ZeroBig,
+			EndTimes: []uint64{3},
+		}},
+		Changes: map[string]*CurrencyStats{
+			"MCC": {
+				Deposited: common.NewBig(-30), Depositors: -1,
+				Transferred: common.NewBig(5), Withdrawn: common.NewBig(25),
+			},
+			"PEN": {
+				Deposited: common.NewBig(7), Depositors: 1,
+				Transferred: common.ZeroBig, Withdrawn: common.ZeroBig,
+			},
+		},
+	}
+
+	doc.accumulate(prev)
+
+	c := doc.Currencies["MCC"]
+
+	switch {
+	case !c.Deposited.Equal(common.NewBig(70)), c.Depositors != 1:
+		t.Errorf("deposited %v, depositors %d", c.Deposited, c.Depositors)
+	case !c.Transferred.Equal(common.NewBig(15)), !c.Withdrawn.Equal(common.NewBig(45)):
+		t.Errorf("transferred %v, withdrawn %v", c.Transferred, c.Withdrawn)
+	case len(c.EndTimes) != 1 || c.EndTimes[0] != 3:
+		t.Errorf("end times %v", c.EndTimes)
+	}
+
+	if c := doc.Currencies["PEN"]; c == nil || !c.Deposited.Equal(common.NewBig(7)) || c.Depositors != 1 {
+		t.Errorf("new currency %v", c)
+	}
+
+	if p := prev.Currencies["MCC"]; !p.Deposited.Equal(common.NewBig(100)) {
+		t.Errorf("previous changed, %v", p.Deposited)
+	}
+}
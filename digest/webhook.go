@@ -0,0 +1,602 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	DefaultColNamePaymentWebhookDeadLetter = "digest_pmt_webhook_dead"
+	PNameStartWebhookDispatcher            = ps.Name("start-payment-webhook-dispatcher")
+
+	ContextValueWebhookDesign     util.ContextKey = "payment_webhook_design"
+	ContextValueWebhookDispatcher util.ContextKey = "payment_webhook_dispatcher"
+)
+
+const (
+	WebhookHeaderEventID   = "X-Payment-Event-Id"
+	WebhookHeaderTimestamp = "X-Payment-Timestamp"
+	WebhookHeaderSignature = "X-Payment-Signature"
+)
+
+// WebhookDesign is the "payment_webhook" section of the node design.
+type WebhookDesign struct {
+	Hooks []Webhook `yaml:"hooks"`
+	// Retries is the number of the retries after the first delivery failed.
+	Retries    uint64        `yaml:"retries"`
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	Timeout    time.Duration `yaml:"timeout"`
+	QueueSize  int           `yaml:"queue_size"`
+	Workers    int           `yaml:"workers"`
+}
+
+func (d *WebhookDesign) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid webhook design")
+
+	for i := range d.Hooks {
+		if err := d.Hooks[i].IsValid(nil); err != nil {
+			return e.Wrap(err)
+		}
+	}
+
+	switch {
+	case d.Backoff < 0, d.MaxBackoff < 0, d.Timeout < 0:
+		return e.Errorf("negative duration")
+	case d.QueueSize < 0, d.Workers < 0:
+		return e.Errorf("negative queue size or workers")
+	}
+
+	if d.Backoff == 0 {
+		d.Backoff = time.Second
+	}
+
+	if d.MaxBackoff == 0 {
+		d.MaxBackoff = time.Minute
+	}
+
+	if d.Timeout == 0 {
+		d.Timeout = time.Second * 10
+	}
+
+	if d.QueueSize == 0 {
+		d.QueueSize = 1024
+	}
+
+	if d.Workers == 0 {
+		d.Workers = 4
+	}
+
+	return nil
+}
+
+func (d WebhookDesign) Enabled() bool {
+	return len(d.Hooks) > 0
+}
+
+// Webhook receives the payment events of the types and the contract accounts;
+// the empty ones are not filtered. The payloads are signed with Secret.
+type Webhook struct {
+	URL       string   `yaml:"url"`
+	Secret    string   `yaml:"secret"`
+	Types     []string `yaml:"types"`
+	Contracts []string `yaml:"contracts"`
+}
+
+func (h Webhook) IsValid([]byte) error {
+	switch u, err := url.Parse(h.URL); {
+	case err != nil:
+		return errors.WithMessagef(err, "invalid webhook url, %q", h.URL)
+	case u.Scheme != "http" && u.Scheme != "https", len(u.Host) < 1:
+		return errors.Errorf("invalid webhook url, %q", h.URL)
+	}
+
+	if len(h.Secret) < 1 {
+		return errors.Errorf("empty secret of webhook, %q", h.URL)
+	}
+
+	for i := range h.Types {
+		if !types.IsEventType(h.Types[i]) {
+			return errors.Errorf("unknown event type, %q of webhook, %q", h.Types[i], h.URL)
+		}
+	}
+
+	return nil
+}
+
+func (h Webhook) match(va EventValue) bool {
+	return (len(h.Types) < 1 || slices.Contains(h.Types, va.Event().Type)) &&
+		(len(h.Contracts) < 1 || slices.Contains(h.Contracts, va.Contract()))
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of
+// "<timestamp>.<body>" with the secret of the webhook.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(m, "%d.", timestamp)
+	_, _ = m.Write(body)
+
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// WebhookClient sends the webhook requests; *http.Client by default.
+type WebhookClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// WebhookDeadLetter is the delivery which was not accepted by the webhook
+// after the retries. The gap is the events lost after EventID, the last event
+// received, when the subscription of the events overflowed; it has no payload
+// and the lost events should be read from the digest.
+type WebhookDeadLetter struct {
+	ID       bson.ObjectID `bson:"_id,omitempty"`
+	URL      string        `bson:"url"`
+	EventID  string        `bson:"event_id"`
+	Contract string        `bson:"contract"`
+	Payload  string        `bson:"payload"`
+	Error    string        `bson:"error"`
+	Attempts uint64        `bson:"attempts"`
+	FailedAt time.Time     `bson:"failed_at"`
+	Gap      bool          `bson:"gap,omitempty"`
+}
+
+// WebhookDeadLetters keeps the dead letters.
+type WebhookDeadLetters interface {
+	Add(context.Context, WebhookDeadLetter) error
+}
+
+// WebhookDeadLetterStore keeps the dead letters to be replayed.
+type WebhookDeadLetterStore interface {
+	WebhookDeadLetters
+	Each(ctx context.Context, u string, limit int64, callback func(WebhookDeadLetter) (bool, error)) error
+	Remove(ctx context.Context, id bson.ObjectID) error
+	Failed(ctx context.Context, id bson.ObjectID, attempts uint64, err error) error
+}
+
+type webhookDelivery struct {
+	hook    Webhook
+	eventID string
+	va      EventValue
+}
+
+// WebhookDispatcher delivers the payment events of PaymentEventBroker to the
+// webhooks. The events which are not delivered after the retries, or which
+// overflow the queue, are kept in the dead letters to be replayed; the events
+// which overflow the subscription are kept as the gaps.
+type WebhookDispatcher struct {
+	*logging.Logging
+	*util.ContextDaemon
+	design      WebhookDesign
+	client      WebhookClient
+	deadLetters WebhookDeadLetters
+	enc         func(interface{}) ([]byte, error)
+	queue       chan webhookDelivery
+	sub         <-chan EventValue
+	cancel      func()
+}
+
+func NewWebhookDispatcher(
+	design WebhookDesign,
+	client WebhookClient,
+	deadLetters WebhookDeadLetters,
+	enc func(interface{}) ([]byte, error),
+) *WebhookDispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: design.Timeout}
+	}
+
+	d := &WebhookDispatcher{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "payment-webhook-dispatcher")
+		}),
+		design:      design,
+		client:      client,
+		deadLetters: deadLetters,
+		enc:         enc,
+		queue:       make(chan webhookDelivery, design.QueueSize),
+	}
+
+	d.ContextDaemon = util.NewContextDaemon(d.start)
+
+	return d
+}
+
+// Start subscribes PaymentEventBroker before the daemon starts, so the events
+// published right after Start are not missed.
+func (d *WebhookDispatcher) Start(ctx context.Context) error {
+	d.sub, d.cancel = PaymentEventBroker().Subscribe("", NewEventFilter(), d.design.QueueSize)
+
+	if err := d.ContextDaemon.Start(ctx); err != nil {
+		d.cancel()
+
+		return err
+	}
+
+	return nil
+}
+
+func (d *WebhookDispatcher) start(ctx context.Context) error {
+	for i := 0; i < d.design.Workers; i++ {
+		go d.work(ctx)
+	}
+
+	ch, cancel := d.sub, d.cancel
+
+	var last string
+
+	for {
+		if done := d.receive(ctx, ch, &last); done {
+			cancel()
+
+			return nil
+		}
+
+		cancel()
+
+		d.Log().Error().Str("after", last).Msg("payment events overflowed; subscribe again")
+
+		d.deadLetterGap(last)
+
+		ch, cancel = PaymentEventBroker().Subscribe("", NewEventFilter(), d.design.QueueSize)
+	}
+}
+
+// receive queues the events until ctx is done or the subscription is closed;
+// last is the offset of the last event received.
+func (d *WebhookDispatcher) receive(ctx context.Context, ch <-chan EventValue, last *string) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case va, ok := <-ch:
+			if !ok {
+				return false
+			}
+
+			*last = va.Offset()
+
+			d.Dispatch(va)
+		}
+	}
+}
+
+// Dispatch queues the event to the matched webhooks; the event is kept in the
+// dead letters when the queue is full.
+func (d *WebhookDispatcher) Dispatch(va EventValue) {
+	for i := range d.design.Hooks {
+		hook := d.design.Hooks[i]
+		if !hook.match(va) {
+			continue
+		}
+
+		dv := webhookDelivery{hook: hook, eventID: va.Offset(), va: va}
+
+		select {
+		case d.queue <- dv:
+		default:
+			d.deadLetter(dv, 0, errors.Errorf("queue full"))
+		}
+	}
+}
+
+func (d *WebhookDispatcher) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case dv := <-d.queue:
+			body, err := d.enc(dv.va)
+			if err != nil {
+				d.deadLetter(dv, 0, err)
+
+				continue
+			}
+
+			if attempts, err := d.Deliver(ctx, dv.hook, dv.eventID, body); err != nil {
+				d.deadLetterBody(dv.hook.URL, dv.eventID, dv.va.Contract(), body, attempts, err)
+			}
+		}
+	}
+}
+
+// Deliver sends the payload to the webhook with the retries and returns the
+// number of the attempts.
+func (d *WebhookDispatcher) Deliver(ctx context.Context, hook Webhook, eventID string, body []byte) (uint64, error) {
+	backoff := d.design.Backoff
+
+	var attempts uint64
+	var err error
+
+	for {
+		attempts++
+
+		if err = d.send(ctx, hook, eventID, body); err == nil || attempts > d.design.Retries {
+			return attempts, err
+		}
+
+		d.Log().Debug().Err(err).Str("url", hook.URL).Str("event", eventID).Uint64("attempts", attempts).
+			Msg("failed to deliver webhook; retry")
+
+		select {
+		case <-ctx.Done():
+			return attempts, err
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > d.design.MaxBackoff {
+			backoff = d.design.MaxBackoff
+		}
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, hook Webhook, eventID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderEventID, eventID)
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, "sha256="+SignWebhookPayload(hook.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("unexpected status, %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func (d *WebhookDispatcher) deadLetter(dv webhookDelivery, attempts uint64, err error) {
+	body, _ := d.enc(dv.va)
+
+	d.deadLetterBody(dv.hook.URL, dv.eventID, dv.va.Contract(), body, attempts, err)
+}
+
+func (d *WebhookDispatcher) deadLetterBody(
+	u, eventID, contract string, body []byte, attempts uint64, err error,
+) {
+	d.addDeadLetter(WebhookDeadLetter{
+		URL:      u,
+		EventID:  eventID,
+		Contract: contract,
+		Payload:  string(body),
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	})
+}
+
+// deadLetterGap keeps the gap after the event of eventID for every webhook;
+// the lost events are not known, so they can be matched to any webhook.
+func (d *WebhookDispatcher) deadLetterGap(eventID string) {
+	for i := range d.design.Hooks {
+		d.addDeadLetter(WebhookDeadLetter{
+			URL:      d.design.Hooks[i].URL,
+			EventID:  eventID,
+			Error:    "payment events overflowed",
+			FailedAt: time.Now().UTC(),
+			Gap:      true,
+		})
+	}
+}
+
+// addDeadLetter keeps the dead letter; it is kept even after the dispatcher
+// stopped in the middle of the delivery.
+func (d *WebhookDispatcher) addDeadLetter(dl WebhookDeadLetter) {
+	l := d.Log().With().Str("url", dl.URL).Str("event", dl.EventID).Bool("gap", dl.Gap).Logger()
+
+	l.Error().Str("error", dl.Error).Uint64("attempts", dl.Attempts).Msg("webhook not delivered")
+
+	if d.deadLetters == nil {
+		return
+	}
+
+	if err := d.deadLetters.Add(context.Background(), dl); err != nil {
+		l.Error().Err(err).Msg("failed to keep dead letter")
+	}
+}
+
+// MongoWebhookDeadLetters keeps the dead letters in the digest database.
+type MongoWebhookDeadLetters struct {
+	db *cdigest.Database
+}
+
+func NewMongoWebhookDeadLetters(db *cdigest.Database) MongoWebhookDeadLetters {
+	return MongoWebhookDeadLetters{db: db}
+}
+
+func (m MongoWebhookDeadLetters) Add(ctx context.Context, dl WebhookDeadLetter) error {
+	_, err := m.db.MongoClient().Collection(DefaultColNamePaymentWebhookDeadLetter).InsertOne(ctx, dl)
+
+	return err
+}
+
+// Each calls callback with the dead letters of the webhook url, ordered by the
+// failed time; of all the webhooks with the empty url.
+func (m MongoWebhookDeadLetters) Each(
+	ctx context.Context, u string, limit int64, callback func(WebhookDeadLetter) (bool, error),
+) error {
+	filter := bson.M{}
+	if len(u) > 0 {
+		filter["url"] = u
+	}
+
+	opt := options.Find().SetSort(bson.D{{Key: "failed_at", Value: 1}})
+	if limit > 0 {
+		opt = opt.SetLimit(limit)
+	}
+
+	return m.db.MongoClient().Find(
+		ctx,
+		DefaultColNamePaymentWebhookDeadLetter,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			var dl WebhookDeadLetter
+			if err := cursor.Decode(&dl); err != nil {
+				return false, err
+			}
+
+			return callback(dl)
+		},
+		opt,
+	)
+}
+
+func (m MongoWebhookDeadLetters) Remove(ctx context.Context, id bson.ObjectID) error {
+	_, err := m.db.MongoClient().Collection(DefaultColNamePaymentWebhookDeadLetter).
+		DeleteOne(ctx, bson.M{"_id": id})
+
+	return err
+}
+
+func (m MongoWebhookDeadLetters) Failed(ctx context.Context, id bson.ObjectID, attempts uint64, err error) error {
+	_, uerr := m.db.MongoClient().Collection(DefaultColNamePaymentWebhookDeadLetter).UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"attempts": attempts},
+			"$set": bson.M{"error": err.Error(), "failed_at": time.Now().UTC()},
+		},
+	)
+
+	return uerr
+}
+
+// ReplayWebhookDeadLetters delivers the dead letters to the webhooks of the
+// design again; the delivered ones are removed. The dead letters of the
+// webhooks not in the design and the gaps are skipped.
+func ReplayWebhookDeadLetters(
+	ctx context.Context,
+	d *WebhookDispatcher,
+	m WebhookDeadLetterStore,
+	u string,
+	limit int64,
+) (delivered, failed uint64, _ error) {
+	hooks := map[string]Webhook{}
+	for i := range d.design.Hooks {
+		hooks[d.design.Hooks[i].URL] = d.design.Hooks[i]
+	}
+
+	var dls []WebhookDeadLetter
+
+	if err := m.Each(ctx, u, limit, func(dl WebhookDeadLetter) (bool, error) {
+		dls = append(dls, dl)
+
+		return true, nil
+	}); err != nil {
+		return 0, 0, err
+	}
+
+	for i := range dls {
+		dl := dls[i]
+
+		hook, found := hooks[dl.URL]
+		if !found || dl.Gap {
+			continue
+		}
+
+		switch attempts, err := d.Deliver(ctx, hook, dl.EventID, []byte(dl.Payload)); {
+		case err == nil:
+			if err := m.Remove(ctx, dl.ID); err != nil {
+				return delivered, failed, err
+			}
+
+			delivered++
+		default:
+			if err := m.Failed(ctx, dl.ID, attempts, err); err != nil {
+				return delivered, failed, err
+			}
+
+			failed++
+		}
+	}
+
+	return delivered, failed, nil
+}
+
+func ProcessStartWebhookDispatcher(ctx context.Context) (context.Context, error) {
+	var design WebhookDesign
+	if err := util.LoadFromContext(ctx, ContextValueWebhookDesign, &design); err != nil {
+		return ctx, err
+	}
+
+	if !design.Enabled() {
+		return ctx, nil
+	}
+
+	var log *logging.Logging
+	var st *cdigest.Database
+
+	if err := util.LoadFromContextOK(ctx, launch.LoggingContextKey, &log); err != nil {
+		return ctx, err
+	}
+
+	if err := util.LoadFromContext(ctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return ctx, err
+	}
+
+	if st == nil {
+		return ctx, nil
+	}
+
+	d := NewWebhookDispatcher(design, nil, NewMongoWebhookDeadLetters(st), util.MarshalJSON)
+	_ = d.SetLogging(log)
+
+	if err := d.Start(context.Background()); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueWebhookDispatcher, d), nil
+}
+
+func ProcessCloseWebhookDispatcher(ctx context.Context) (context.Context, error) {
+	var d *WebhookDispatcher
+	if err := util.LoadFromContext(ctx, ContextValueWebhookDispatcher, &d); err != nil {
+		return ctx, err
+	}
+
+	if d == nil {
+		return ctx, nil
+	}
+
+	if err := d.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
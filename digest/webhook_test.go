@@ -0,0 +1,356 @@
+package digest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const testWebhookSecret = "secret"
+
+// testWebhookServer fails the first fails requests and accepts the others;
+// the requests with the invalid signature are rejected.
+type testWebhookServer struct {
+	*httptest.Server
+	fails    int64
+	requests atomic.Int64
+	bodies   chan []byte
+	times    chan time.Time
+}
+
+func newTestWebhookServer(t *testing.T, fails int64) *testWebhookServer {
+	s := &testWebhookServer{
+		fails:  fails,
+		bodies: make(chan []byte, 100),
+		times:  make(chan time.Time, 100),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.times <- time.Now()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(WebhookHeaderTimestamp), 10, 64)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if i := r.Header.Get(WebhookHeaderSignature); i != "sha256="+SignWebhookPayload(testWebhookSecret, timestamp, body) {
+			t.Errorf("invalid signature, %q", i)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if len(r.Header.Get(WebhookHeaderEventID)) < 1 {
+			t.Error("empty event id")
+		}
+
+		if s.requests.Add(1) <= s.fails {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		s.bodies <- body
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+type testDeadLetters struct {
+	sync.Mutex
+	dls   []WebhookDeadLetter
+	added chan WebhookDeadLetter
+}
+
+func newTestDeadLetters() *testDeadLetters {
+	return &testDeadLetters{added: make(chan WebhookDeadLetter, 100)}
+}
+
+func (m *testDeadLetters) Add(_ context.Context, dl WebhookDeadLetter) error {
+	m.Lock()
+	defer m.Unlock()
+
+	dl.ID = bson.NewObjectID()
+	m.dls = append(m.dls, dl)
+	m.added <- dl
+
+	return nil
+}
+
+func (m *testDeadLetters) Each(
+	_ context.Context, u string, _ int64, callback func(WebhookDeadLetter) (bool, error),
+) error {
+	m.Lock()
+	dls := append([]WebhookDeadLetter(nil), m.dls...)
+	m.Unlock()
+
+	for i := range dls {
+		if len(u) > 0 && dls[i].URL != u {
+			continue
+		}
+
+		if keep, err := callback(dls[i]); err != nil || !keep {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *testDeadLetters) Remove(_ context.Context, id bson.ObjectID) error {
+	m.Lock()
+	defer m.Unlock()
+
+	for i := range m.dls {
+		if m.dls[i].ID == id {
+			m.dls = append(m.dls[:i], m.dls[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (m *testDeadLetters) Failed(_ context.Context, id bson.ObjectID, attempts uint64, err error) error {
+	m.Lock()
+	defer m.Unlock()
+
+	for i := range m.dls {
+		if m.dls[i].ID == id {
+			m.dls[i].Attempts += attempts
+			m.dls[i].Error = err.Error()
+		}
+	}
+
+	return nil
+}
+
+func (m *testDeadLetters) len() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return len(m.dls)
+}
+
+func newTestWebhookDispatcher(
+	t *testing.T, u string, retries uint64, deadLetters WebhookDeadLetters,
+) *WebhookDispatcher {
+	design := WebhookDesign{
+		Hooks:      []Webhook{{URL: u, Secret: testWebhookSecret}},
+		Retries:    retries,
+		Backoff:    time.Millisecond * 30,
+		MaxBackoff: time.Millisecond * 50,
+	}
+
+	if err := design.IsValid(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewWebhookDispatcher(design, nil, deadLetters, util.MarshalJSON)
+}
+
+func newTestEventValue() EventValue {
+	return NewEventValue("contract", "account", types.NewEvent(
+		types.EventTypeDeposit, "MCC", common.NewBig(100), "", valuehash.RandomSHA256(), 1, base.Height(3)))
+}
+
+func waitDeadLetter(t *testing.T, m *testDeadLetters) WebhookDeadLetter {
+	t.Helper()
+
+	select {
+	case dl := <-m.added:
+		return dl
+	case <-time.After(time.Second * 5):
+		t.Fatal("no dead letter")
+
+		return WebhookDeadLetter{}
+	}
+}
+
+func TestWebhookDeliverRetry(t *testing.T) {
+	server := newTestWebhookServer(t, 2)
+	d := newTestWebhookDispatcher(t, server.URL, 2, nil)
+
+	attempts, err := d.Deliver(context.Background(), d.design.Hooks[0], "event", []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("attempts %d", attempts)
+	}
+
+	if body := <-server.bodies; string(body) != `{"a":1}` {
+		t.Errorf("body %q", body)
+	}
+
+	var times []time.Time
+	for range 3 {
+		times = append(times, <-server.times)
+	}
+
+	// NOTE the backoff is doubled up to the max backoff.
+	for i, backoff := range []time.Duration{d.design.Backoff, d.design.MaxBackoff} {
+		if elapsed := times[i+1].Sub(times[i]); elapsed < backoff {
+			t.Errorf("retried after %v, before backoff %v", elapsed, backoff)
+		}
+	}
+}
+
+func TestWebhookDispatchDeadLetter(t *testing.T) {
+	server := newTestWebhookServer(t, 2)
+	deadLetters := newTestDeadLetters()
+	d := newTestWebhookDispatcher(t, server.URL, 1, deadLetters)
+
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = d.Stop()
+	}()
+
+	va := newTestEventValue()
+	PaymentEventBroker().Publish(va)
+
+	dl := waitDeadLetter(t, deadLetters)
+
+	switch {
+	case dl.URL != server.URL:
+		t.Errorf("url %q", dl.URL)
+	case dl.EventID != va.Offset():
+		t.Errorf("event id %q", dl.EventID)
+	case dl.Attempts != 2:
+		t.Errorf("attempts %d", dl.Attempts)
+	case dl.Gap:
+		t.Error("gap")
+	}
+
+	// NOTE the server accepts from the third request.
+	delivered, failed, err := ReplayWebhookDeadLetters(context.Background(), d, deadLetters, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if delivered != 1 || failed != 0 {
+		t.Errorf("delivered %d, failed %d", delivered, failed)
+	}
+
+	if body := <-server.bodies; string(body) != dl.Payload {
+		t.Errorf("body %q", body)
+	}
+
+	if i := deadLetters.len(); i != 0 {
+		t.Errorf("%d dead letters left", i)
+	}
+}
+
+func TestWebhookReplayFailed(t *testing.T) {
+	server := newTestWebhookServer(t, 100)
+	deadLetters := newTestDeadLetters()
+	d := newTestWebhookDispatcher(t, server.URL, 0, deadLetters)
+
+	for _, dl := range []WebhookDeadLetter{
+		{URL: server.URL, EventID: "event", Payload: "{}", Attempts: 1},
+		{URL: server.URL, EventID: "gap", Gap: true},
+		{URL: "http://unknown", EventID: "unknown", Payload: "{}"},
+	} {
+		_ = deadLetters.Add(context.Background(), dl)
+	}
+
+	delivered, failed, err := ReplayWebhookDeadLetters(context.Background(), d, deadLetters, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if delivered != 0 || failed != 1 {
+		t.Errorf("delivered %d, failed %d", delivered, failed)
+	}
+
+	if i := server.requests.Load(); i != 1 {
+		t.Errorf("%d requests", i)
+	}
+
+	if dl := deadLetters.dls[0]; dl.Attempts != 2 || len(dl.Error) < 1 {
+		t.Errorf("attempts %d, error %q", dl.Attempts, dl.Error)
+	}
+
+	if i := deadLetters.len(); i != 3 {
+		t.Errorf("%d dead letters left", i)
+	}
+}
+
+func TestWebhookSubscriptionOverflow(t *testing.T) {
+	server := newTestWebhookServer(t, 0)
+	deadLetters := newTestDeadLetters()
+	d := newTestWebhookDispatcher(t, server.URL, 0, deadLetters)
+
+	va := newTestEventValue()
+
+	// NOTE the subscription is closed after one event as the broker does on
+	// overflow.
+	sub := make(chan EventValue, 1)
+	sub <- va
+	close(sub)
+
+	d.sub, d.cancel = sub, func() {}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- d.start(ctx)
+	}()
+
+	if body := <-server.bodies; len(body) < 1 {
+		t.Error("event not delivered")
+	}
+
+	dl := waitDeadLetter(t, deadLetters)
+
+	switch {
+	case !dl.Gap:
+		t.Error("not gap")
+	case dl.URL != server.URL:
+		t.Errorf("url %q", dl.URL)
+	case dl.EventID != va.Offset():
+		t.Errorf("gap after %q, not %q", dl.EventID, va.Offset())
+	}
+
+	// NOTE subscribed again.
+	next := newTestEventValue()
+	PaymentEventBroker().Publish(next)
+
+	if body := <-server.bodies; len(body) < 1 {
+		t.Error("event not delivered after overflow")
+	}
+
+	cancel()
+
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
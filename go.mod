@@ -6,6 +6,7 @@ toolchain go1.24.9
 
 require (
 	github.com/alecthomas/kong v1.12.1
+	github.com/gorilla/mux v1.8.1
 	github.com/imfact-labs/currency-model v0.0.0-20260428032920-7ae7cefc4ff6
 	github.com/imfact-labs/mitum2 v0.0.0-20260410075537-0fc3877ecf42
 	github.com/pkg/errors v0.9.1
@@ -16,7 +17,6 @@ require (
 
 require (
 	github.com/arl/statsviz v0.7.1 // indirect
-	github.com/gorilla/mux v1.8.1 // indirect
 	golang.org/x/sync v0.18.0 // indirect
 )
 
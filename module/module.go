@@ -38,8 +38,20 @@ func (Module) Register(reg *modulekit.Registry) error {
 
 	if err := reg.AddAPIRoutes(
 		ID,
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentOpenAPI, Methods: []string{"GET"}},
 		modulekit.APIRoute{Path: modapi.HandlerPathPaymentDesign, Methods: []string{"GET"}},
 		modulekit.APIRoute{Path: modapi.HandlerPathPaymentAccountInfo, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentLock, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentReceived, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentStats, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentAccounts, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentOperations, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentAccountOperations, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentEvents, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentAccountStatement, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentSimulate, Methods: []string{"POST"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentBuildFact, Methods: []string{"POST"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathPaymentBuildOperation, Methods: []string{"POST"}},
 	); err != nil {
 		return err
 	}
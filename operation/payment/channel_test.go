@@ -0,0 +1,208 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func (pt *paymentTest) setChannel(depositor, receiver base.Address, capacity, claimed int64, nonce, closingAt uint64) {
+	pt.setState(
+		state.ChannelStateKey(pt.contract.String(), depositor.String(), pt.GenesisCurrency.String()),
+		state.NewChannelStateValue(types.NewChannel(
+			depositor, receiver, pt.GenesisCurrency, common.NewBig(capacity), common.NewBig(claimed),
+			nonce, 10, closingAt)),
+	)
+}
+
+func (pt *paymentTest) channel(depositor base.Address) *types.Channel {
+	st, found, _ := pt.GetStateFunc(
+		state.ChannelStateKey(pt.contract.String(), depositor.String(), pt.GenesisCurrency.String()))
+	if !found {
+		return nil
+	}
+
+	channel, err := state.GetChannelFromState(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	return channel
+}
+
+func TestOpenChannelCapacity(t *testing.T) {
+	for name, c := range map[string]struct {
+		capacity int64
+		opened   bool
+	}{
+		"over deposit": {capacity: 1001},
+		"deposit":      {capacity: 1000, opened: true},
+	} {
+		pt := newPaymentTest(t)
+		depositor, receiver := pt.account("depositor"), pt.account("receiver")
+
+		pt.setDesign(pt.setting(depositor, 1000))
+		pt.setDeposit(depositor, 1000, 1)
+
+		op, err := payment.NewOpenChannel(payment.NewOpenChannelFact(
+			[]byte("token"), depositor, pt.contract, receiver, common.NewBig(c.capacity), 10, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewOpenChannelProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if opened := reasons[0] == nil; opened != c.opened {
+			t.Errorf("%s: opened %v, not %v: %v", name, opened, c.opened, reasons[0])
+		}
+
+		if channel := pt.channel(depositor); c.opened && (channel == nil || channel.Nonce() != 1) {
+			t.Errorf("%s: channel %v", name, channel)
+		}
+	}
+}
+
+func TestChannelReservedDeposit(t *testing.T) {
+	for name, c := range map[string]struct {
+		amount      int64
+		closingAt   func(now uint64) uint64
+		transferred bool
+	}{
+		"reserved":        {amount: 501},
+		"not reserved":    {amount: 500, transferred: true},
+		"closing":         {amount: 501, closingAt: func(now uint64) uint64 { return now + 1 }},
+		"closed":          {amount: 501, closingAt: func(now uint64) uint64 { return now }, transferred: true},
+		"closed all":      {amount: 1000, closingAt: func(now uint64) uint64 { return now - 1 }, transferred: true},
+		"deposit at most": {amount: 1001, closingAt: func(now uint64) uint64 { return now }},
+	} {
+		pt := newPaymentTest(t)
+		depositor, receiver := pt.account("depositor"), pt.account("receiver")
+
+		pt.setDesign(pt.setting(depositor, 10000))
+		pt.setDeposit(depositor, 1000, 1)
+
+		var closingAt uint64
+		if c.closingAt != nil {
+			closingAt = c.closingAt(pt.now)
+		}
+
+		// NOTE 500 of the capacity is not claimed yet.
+		pt.setChannel(depositor, receiver, 600, 100, 1, closingAt)
+
+		op, err := payment.NewTransfer(payment.NewTransferFact(
+			[]byte("token"), depositor, pt.contract, receiver, common.NewBig(c.amount), pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewTransferProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if transferred := reasons[0] == nil; transferred != c.transferred {
+			t.Errorf("%s: transferred %v, not %v: %v", name, transferred, c.transferred, reasons[0])
+		}
+	}
+}
+
+func TestChannelWithdraw(t *testing.T) {
+	pt := newPaymentTest(t)
+	depositor, receiver := pt.account("depositor"), pt.account("receiver")
+
+	pt.setDesign(pt.setting(depositor, 1000))
+	pt.setDeposit(depositor, 1000, 1)
+	pt.setChannel(depositor, receiver, 100, 0, 1, 0)
+
+	op, err := payment.NewWithdraw(payment.NewWithdrawFact([]byte("token"), depositor, pt.contract, pt.GenesisCurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err := pt.process(payment.NewWithdrawProcessor(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] == nil {
+		t.Error("deposit of open channel withdrawn")
+	}
+}
+
+func TestClaimVoucher(t *testing.T) {
+	pt := newPaymentTest(t)
+	depositor, _, priv := pt.NewTestAccountState(pt.NewPrivateKey("depositor"), true)
+	receiver := pt.account("receiver")
+	other := base.NewMPrivatekey()
+
+	pt.setDesign(pt.setting(depositor, 1000))
+	pt.setDeposit(depositor, 1000, 1)
+	pt.setChannel(depositor, receiver, 500, 100, 2, 0)
+
+	for name, c := range map[string]struct {
+		nonce   uint64
+		amount  int64
+		signer  base.Privatekey
+		network base.NetworkID
+		claimed bool
+	}{
+		"other signer":    {nonce: 2, amount: 200, signer: other},
+		"other network":   {nonce: 2, amount: 200, network: base.NetworkID("other")},
+		"previous nonce":  {nonce: 1, amount: 200},
+		"next nonce":      {nonce: 3, amount: 200},
+		"claimed amount":  {nonce: 2, amount: 100},
+		"over capacity":   {nonce: 2, amount: 501},
+		"voucher claimed": {nonce: 2, amount: 300, claimed: true},
+	} {
+		signer, network := priv, pt.NetworkID
+		if c.signer != nil {
+			signer = c.signer
+		}
+
+		if c.network != nil {
+			network = c.network
+		}
+
+		voucher := types.NewVoucher(pt.contract, depositor, receiver, pt.GenesisCurrency, c.nonce, common.NewBig(c.amount))
+		if err := voucher.Sign(signer, network); err != nil {
+			t.Fatal(err)
+		}
+
+		op, err := payment.NewClaimVoucher(payment.NewClaimVoucherFact(
+			[]byte(name), receiver, pt.contract, voucher, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewClaimVoucherProcessor(pt.NetworkID), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if claimed := reasons[0] == nil; claimed != c.claimed {
+			t.Errorf("%s: claimed %v, not %v: %v", name, claimed, c.claimed, reasons[0])
+		}
+
+		if c.claimed {
+			// NOTE the difference from the claimed of channel is paid.
+			switch {
+			case !pt.channel(depositor).Claimed().Equal(common.NewBig(c.amount)):
+				t.Errorf("%s: channel claimed %v", name, pt.channel(depositor).Claimed())
+			case !pt.deposit(depositor).Equal(common.NewBig(1000 - 200)):
+				t.Errorf("%s: deposit %v", name, pt.deposit(depositor))
+			case !pt.balance(receiver).Equal(common.NewBig(200)):
+				t.Errorf("%s: receiver balance %v", name, pt.balance(receiver))
+			}
+
+			pt.setChannel(depositor, receiver, 500, 100, 2, 0)
+			pt.setDeposit(depositor, 1000, 1)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	ClaimTransferFactHint = hint.MustNewHint("mitum-payment-claim-transfer-operation-fact-v0.0.1")
+	ClaimTransferHint     = hint.MustNewHint("mitum-payment-claim-transfer-operation-v0.0.1")
+)
+
+type ClaimTransferFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	hashLock string
+	preimage string
+	currency ctypes.CurrencyID
+}
+
+func NewClaimTransferFact(
+	token []byte,
+	sender, contract base.Address,
+	hashLock, preimage string,
+	currency ctypes.CurrencyID,
+) ClaimTransferFact {
+	bf := base.NewBaseFact(ClaimTransferFactHint, token)
+	fact := ClaimTransferFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		hashLock: hashLock,
+		preimage: preimage,
+		currency: currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact ClaimTransferFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact ClaimTransferFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact ClaimTransferFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		[]byte(fact.hashLock),
+		[]byte(fact.preimage),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact ClaimTransferFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := types.IsValidHashLock(fact.hashLock); err != nil {
+		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Wrap(err))
+	} else if err := types.VerifyPreimage(fact.hashLock, fact.preimage); err != nil {
+		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Wrap(err))
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact ClaimTransferFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact ClaimTransferFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimTransferFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact ClaimTransferFact) HashLock() string {
+	return fact.hashLock
+}
+
+func (fact ClaimTransferFact) Preimage() string {
+	return fact.preimage
+}
+
+func (fact ClaimTransferFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact ClaimTransferFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimTransferFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender}, nil
+}
+
+func (fact ClaimTransferFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact ClaimTransferFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimTransferFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimTransferFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact ClaimTransferFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	r[extras.DuplicationKeyTypeContractWithdraw] = []string{
+		fmt.Sprintf("%s:%s", fact.contract.String(), fact.hashLock)}
+
+	return r, nil
+}
+
+type ClaimTransfer struct {
+	extras.ExtendedOperation
+}
+
+func (op ClaimTransfer) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	return contractWithdrawOperationDupKey(op)
+}
+
+func NewClaimTransfer(fact ClaimTransferFact) (ClaimTransfer, error) {
+	return ClaimTransfer{
+		ExtendedOperation: extras.NewExtendedOperation(ClaimTransferHint, fact),
+	}, nil
+}
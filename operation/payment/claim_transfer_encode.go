@@ -0,0 +1,32 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+func (fact *ClaimTransferFact) unpack(
+	enc encoder.Encoder,
+	sa, ca, hashLock, preimage, cid string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	fact.hashLock = hashLock
+	fact.preimage = preimage
+	fact.currency = ctypes.CurrencyID(cid)
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var claimTransferProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(ClaimTransferProcessor)
+	},
+}
+
+func (ClaimTransfer) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type ClaimTransferProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewClaimTransferProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new ClaimTransferProcessor")
+
+		nopp := claimTransferProcessorPool.Get()
+		opp, ok := nopp.(*ClaimTransferProcessor)
+		if !ok {
+			return nil, e.Errorf("expected ClaimTransferProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *ClaimTransferProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(ClaimTransferFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", ClaimTransferFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	lock, reason := existsLockedLock(fact.Contract(), fact.HashLock(), getStateFunc)
+	if reason != nil {
+		return ctx, reason, nil
+	}
+
+	if !lock.Receiver().Equal(fact.Sender()) {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMAccountNAth).Errorf("sender %v is not receiver of lock, %v",
+				fact.Sender(), lock.Receiver(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *ClaimTransferProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(ClaimTransferFact)
+
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	k := state.LockStateKey(fact.Contract().String(), fact.HashLock())
+	st, _ := cstate.ExistsState(k, "lock", getStateFunc)
+	lock, _ := state.GetLockFromState(st)
+	if lock.IsExpired(nowTime) {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"lock for hash lock, %v in contract account %v expired at %v",
+			fact.HashLock(), fact.Contract(), lock.Timeout(),
+		), nil
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+	smv, err := cstate.CreateNotExistAccount(fact.Sender(), getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError("%w", err), nil
+	} else if smv != nil {
+		sts = append(sts, smv)
+	}
+
+	nLock := types.NewLock(
+		lock.Sender(), lock.Receiver(), lock.Currency(), lock.Amount(),
+		lock.HashLock(), lock.Timeout(), fact.Preimage(), types.LockStatusClaimed,
+	)
+	if err := nLock.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid lock for hash lock, %v in contract account %v: %w", fact.HashLock(), fact.Contract(), err), nil
+	}
+
+	sts = append(sts, cstate.NewStateMergeValue(k, state.NewLockStateValue(nLock)))
+	sts = append(sts, releaseLockStateMergeValues(
+		fact.Contract(), *lock, lock.Receiver(), types.EventTypeClaimTransfer, fact.Hash(), nowTime, opp.Height(),
+	)...)
+
+	return sts, nil, nil
+}
+
+func (opp *ClaimTransferProcessor) Close() error {
+	opp.proposal = nil
+	claimTransferProcessorPool.Put(opp)
+
+	return nil
+}
+
+// existsLockedLock returns the lock which is neither claimed nor refunded yet.
+func existsLockedLock(
+	contract base.Address, hashLock string, getStateFunc base.GetStateFunc,
+) (*types.Lock, base.OperationProcessReasonError) {
+	if _, err := cstate.ExistsState(
+		state.DesignStateKey(contract.String()), "service design", getStateFunc); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				contract,
+			))
+	}
+
+	st, err := cstate.ExistsState(state.LockStateKey(contract.String(), hashLock), "lock", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("lock for hash lock, %v in contract account %v",
+				hashLock, contract,
+			))
+	}
+
+	lock, err := state.GetLockFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("lock for hash lock, %v in contract account %v",
+				hashLock, contract,
+			))
+	} else if lock.Status() != types.LockStatusLocked {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("lock for hash lock, %v in contract account %v is already %v",
+				hashLock, contract, lock.Status(),
+			))
+	}
+
+	return lock, nil
+}
+
+// releaseLockStateMergeValues pays the locked amount from the contract account
+// to the given account and records the event in the log of lock sender.
+func releaseLockStateMergeValues(
+	contract base.Address,
+	lock types.Lock,
+	to base.Address,
+	eventType string,
+	factHash util.Hash,
+	nowTime uint64,
+	height base.Height,
+) []base.StateMergeValue {
+	cid := lock.Currency()
+	am := ctypes.NewAmount(lock.Amount(), cid)
+
+	return []base.StateMergeValue{
+		common.NewBaseStateMergeValue(
+			currency.BalanceStateKey(contract, cid),
+			currency.NewDeductBalanceStateValue(am),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return currency.NewBalanceStateValueMerger(
+					height, currency.BalanceStateKey(contract, cid),
+					cid, st,
+				)
+			}),
+		common.NewBaseStateMergeValue(
+			currency.BalanceStateKey(to, cid),
+			currency.NewAddBalanceStateValue(am),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return currency.NewBalanceStateValueMerger(height,
+					currency.BalanceStateKey(to, cid),
+					cid, st,
+				)
+			},
+		),
+		common.NewBaseStateMergeValue(
+			state.EventLogStateKey(contract.String(), lock.Sender().String()),
+			state.NewAppendEventLogStateValue(lock.Sender(), types.NewEvent(
+				eventType, cid.String(), lock.Amount(), to.String(), factHash, nowTime, height,
+			)),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return state.NewEventLogStateValueMerger(height,
+					state.EventLogStateKey(contract.String(), lock.Sender().String()), st,
+				)
+			},
+		),
+	}
+}
@@ -0,0 +1,163 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	ClaimVoucherFactHint = hint.MustNewHint("mitum-payment-claim-voucher-operation-fact-v0.0.1")
+	ClaimVoucherHint     = hint.MustNewHint("mitum-payment-claim-voucher-operation-v0.0.1")
+)
+
+type ClaimVoucherFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	voucher  types.Voucher
+	currency ctypes.CurrencyID
+}
+
+func NewClaimVoucherFact(
+	token []byte, sender, contract base.Address, voucher types.Voucher, currency ctypes.CurrencyID,
+) ClaimVoucherFact {
+	bf := base.NewBaseFact(ClaimVoucherFactHint, token)
+	fact := ClaimVoucherFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		voucher:  voucher,
+		currency: currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact ClaimVoucherFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact ClaimVoucherFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact ClaimVoucherFact) Bytes() []byte {
+	bs := make([][]byte, len(fact.voucher.Signs()))
+	for i, sign := range fact.voucher.Signs() {
+		bs[i] = sign.Bytes()
+	}
+
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.voucher.Bytes(),
+		util.ConcatBytesSlice(bs...),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact ClaimVoucherFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.voucher,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if !fact.voucher.Receiver().Equal(fact.sender) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("sender %v is not receiver of voucher, %v", fact.sender, fact.voucher.Receiver()))
+	} else if !fact.voucher.Contract().Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("contract account of voucher, %v is not %v", fact.voucher.Contract(), fact.contract))
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact ClaimVoucherFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact ClaimVoucherFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimVoucherFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact ClaimVoucherFact) Voucher() types.Voucher {
+	return fact.voucher
+}
+
+func (fact ClaimVoucherFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact ClaimVoucherFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimVoucherFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender, fact.voucher.Depositor()}, nil
+}
+
+func (fact ClaimVoucherFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact ClaimVoucherFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimVoucherFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact ClaimVoucherFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact ClaimVoucherFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.voucher.Depositor())}
+
+	return r, nil
+}
+
+type ClaimVoucher struct {
+	extras.ExtendedOperation
+}
+
+func (op ClaimVoucher) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	return contractWithdrawOperationDupKey(op)
+}
+
+func NewClaimVoucher(fact ClaimVoucherFact) (ClaimVoucher, error) {
+	return ClaimVoucher{
+		ExtendedOperation: extras.NewExtendedOperation(ClaimVoucherHint, fact),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func (fact *ClaimVoucherFact) unpack(
+	enc encoder.Encoder,
+	sa, ca string,
+	voucher types.Voucher,
+	cid string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	fact.voucher = voucher
+	fact.currency = ctypes.CurrencyID(cid)
+
+	return nil
+}
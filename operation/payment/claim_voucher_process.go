@@ -0,0 +1,316 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var claimVoucherProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(ClaimVoucherProcessor)
+	},
+}
+
+func (ClaimVoucher) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type ClaimVoucherProcessor struct {
+	*base.BaseOperationProcessor
+	proposal  *base.ProposalSignFact
+	networkID base.NetworkID
+}
+
+func NewClaimVoucherProcessor(networkID base.NetworkID) ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new ClaimVoucherProcessor")
+
+		nopp := claimVoucherProcessorPool.Get()
+		opp, ok := nopp.(*ClaimVoucherProcessor)
+		if !ok {
+			return nil, e.Errorf("expected ClaimVoucherProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+		opp.networkID = networkID
+
+		return opp, nil
+	}
+}
+
+func (opp *ClaimVoucherProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(ClaimVoucherFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", ClaimVoucherFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	voucher := fact.Voucher()
+	depositor := voucher.Depositor()
+	cid := voucher.Currency()
+
+	if err := voucher.VerifySigns(opp.networkID); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMSignInvalid).Errorf("voucher of account, %v: %v", depositor, err)), nil
+	}
+
+	if err := cstate.CheckFactSignsByState(depositor, voucher.Signs(), getStateFunc); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMSignInvalid).Errorf("voucher of account, %v: %v", depositor, err)), nil
+	}
+
+	st, err := cstate.ExistsState(
+		state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("service design value for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	if setting := design.AccountSetting(depositor.String()); setting != nil && setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				depositor, fact.Contract(),
+			)), nil
+	}
+
+	st, err = cstate.ExistsState(
+		state.ChannelStateKey(fact.Contract().String(), depositor.String(), cid.String()),
+		"channel", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("channel of account, %v for currency, %v in contract account %v",
+				depositor, cid, fact.Contract(),
+			)), nil
+	}
+
+	channel, err := state.GetChannelFromState(st)
+	switch {
+	case err != nil:
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("channel of account, %v for currency, %v in contract account %v",
+				depositor, cid, fact.Contract(),
+			)), nil
+	case !channel.Receiver().Equal(fact.Sender()):
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMAccountNAth).Errorf("sender %v is not receiver of channel, %v",
+				fact.Sender(), channel.Receiver(),
+			)), nil
+	case channel.Nonce() != voucher.Nonce():
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("nonce of voucher, %v does not match with channel, %v",
+				voucher.Nonce(), channel.Nonce(),
+			)), nil
+	case voucher.Amount().Compare(channel.Capacity()) > 0:
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValOOR).Errorf("voucher amount(%v) exceeds the capacity(%v) of channel",
+				voucher.Amount(), channel.Capacity(),
+			)), nil
+	case voucher.Amount().Compare(channel.Claimed()) <= 0:
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("voucher amount(%v) is not over the claimed(%v) of channel",
+				voucher.Amount(), channel.Claimed(),
+			)), nil
+	}
+
+	_, err = cstate.ExistsState(currency.BalanceStateKey(fact.Contract(), cid),
+		fmt.Sprintf("balance of account, %v", fact.Contract()), getStateFunc,
+	)
+	if err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.Wrap(common.ErrMStateNF).
+				Errorf("%v", err)), nil
+	}
+
+	st, err = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), depositor.String()),
+		"account record", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("record of account, %v in contract account %v",
+				depositor, fact.Contract(),
+			)), nil
+	}
+
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("record of account, %v not found in contract account %v",
+				depositor, fact.Contract(),
+			)), nil
+	}
+
+	payout := voucher.Amount().Sub(channel.Claimed())
+	if amount := record.Amount(cid.String()); amount == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit for currency, %v of account, %v not found in contract account %v",
+				cid, depositor, fact.Contract(),
+			)), nil
+	} else if amount.Compare(payout) < 0 {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("claim amount(%v) exceeds the deposit(%v) of account %v in contract account %v",
+				payout, amount, depositor, fact.Contract(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *ClaimVoucherProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(ClaimVoucherFact)
+
+	voucher := fact.Voucher()
+	depositor := voucher.Depositor()
+	cid := voucher.Currency()
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	k := state.ChannelStateKey(fact.Contract().String(), depositor.String(), cid.String())
+	st, _ := cstate.ExistsState(k, "channel", getStateFunc)
+	channel, _ := state.GetChannelFromState(st)
+	if channel.IsClosed(nowTime) {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"channel of account, %v for currency, %v in contract account %v was closed at %v",
+			depositor, cid, fact.Contract(), channel.ClosingAt(),
+		), nil
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+
+	payout := voucher.Amount().Sub(channel.Claimed())
+	nChannel := types.NewChannel(
+		channel.Depositor(), channel.Receiver(), channel.Currency(),
+		channel.Capacity(), voucher.Amount(), channel.Nonce(),
+		channel.DisputeWindow(), channel.ClosingAt(),
+	)
+	if err := nChannel.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid channel of account, %v in contract account %v: %w", depositor, fact.Contract(), err), nil
+	}
+
+	sts = append(sts, cstate.NewStateMergeValue(k, state.NewChannelStateValue(nChannel)))
+
+	st, _ = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), depositor.String()),
+		"account record", getStateFunc)
+	record, _ := state.GetDepositRecordFromState(st)
+
+	if amount := record.Amount(cid.String()); amount == nil || amount.Compare(payout) < 0 {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"payout, %v exceeds the deposit of account, %v in contract account %v", payout, depositor, fact.Contract()), nil
+	}
+
+	sts = append(sts, state.NewDepositRecordStateMergeValue(
+		state.DepositRecordStateKey(fact.Contract().String(), depositor.String()),
+		state.NewDeductDepositRecordStateValue(depositor, cid.String(), payout, nil),
+	))
+
+	am := ctypes.NewAmount(payout, cid)
+	sts = append(
+		sts,
+		common.NewBaseStateMergeValue(
+			currency.BalanceStateKey(fact.Contract(), cid),
+			currency.NewDeductBalanceStateValue(am),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return currency.NewBalanceStateValueMerger(
+					height, currency.BalanceStateKey(fact.Contract(), cid),
+					cid, st,
+				)
+			}),
+	)
+
+	sts = append(sts, common.NewBaseStateMergeValue(
+		currency.BalanceStateKey(fact.Sender(), cid),
+		currency.NewAddBalanceStateValue(am),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return currency.NewBalanceStateValueMerger(height,
+				currency.BalanceStateKey(fact.Sender(), cid),
+				cid, st,
+			)
+		},
+	))
+
+	sts = append(sts, common.NewBaseStateMergeValue(
+		state.EventLogStateKey(fact.Contract().String(), depositor.String()),
+		state.NewAppendEventLogStateValue(depositor, types.NewEvent(
+			types.EventTypeClaimVoucher, cid.String(), payout, fact.Sender().String(), fact.Hash(), nowTime, opp.Height(),
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(fact.Contract().String(), depositor.String()), st,
+			)
+		},
+	))
+
+	return sts, nil, nil
+}
+
+func (opp *ClaimVoucherProcessor) Close() error {
+	opp.proposal = nil
+	opp.networkID = nil
+	claimVoucherProcessorPool.Put(opp)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestClaimVoucherLockedDeposit(t *testing.T) {
+	for _, locked := range []bool{false, true} {
+		pt := newPaymentTest(t)
+		depositor, _, priv := pt.NewTestAccountState(pt.NewPrivateKey("depositor"), true)
+		receiver := pt.account("receiver")
+
+		setting := pt.setting(depositor, 1000)
+		setting.SetKeys(priv.Publickey(), priv.Publickey())
+		setting.SetLocked(locked)
+
+		pt.setDesign(setting)
+		pt.setDeposit(depositor, 1000, 1)
+		pt.setState(
+			state.ChannelStateKey(pt.contract.String(), depositor.String(), pt.GenesisCurrency.String()),
+			state.NewChannelStateValue(types.NewChannel(
+				depositor, receiver, pt.GenesisCurrency, common.NewBig(500), common.ZeroBig, 0, 10, 0)),
+		)
+
+		voucher := types.NewVoucher(pt.contract, depositor, receiver, pt.GenesisCurrency, 0, common.NewBig(100))
+		if err := voucher.Sign(priv, pt.NetworkID); err != nil {
+			t.Fatal(err)
+		}
+
+		op, err := payment.NewClaimVoucher(payment.NewClaimVoucherFact(
+			[]byte("token"), receiver, pt.contract, voucher, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewClaimVoucherProcessor(pt.NetworkID), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case locked && reasons[0] == nil:
+			t.Error("voucher claimed from locked deposit")
+		case !locked && reasons[0] != nil:
+			t.Errorf("voucher not claimed: %v", reasons[0])
+		case !locked && !pt.deposit(depositor).Equal(common.NewBig(900)):
+			t.Errorf("deposit %v after claim", pt.deposit(depositor))
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	CloseChannelFactHint = hint.MustNewHint("mitum-payment-close-channel-operation-fact-v0.0.1")
+	CloseChannelHint     = hint.MustNewHint("mitum-payment-close-channel-operation-v0.0.1")
+)
+
+type CloseChannelFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	currency ctypes.CurrencyID
+}
+
+func NewCloseChannelFact(
+	token []byte, sender, contract base.Address, currency ctypes.CurrencyID) CloseChannelFact {
+	bf := base.NewBaseFact(CloseChannelFactHint, token)
+	fact := CloseChannelFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		currency: currency,
+	}
+
+	fact.SetHash(fact.GenerateHash())
+	return fact
+}
+
+func (fact CloseChannelFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact CloseChannelFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact CloseChannelFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact CloseChannelFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact CloseChannelFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact CloseChannelFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact CloseChannelFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact CloseChannelFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact CloseChannelFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender}, nil
+}
+
+func (fact CloseChannelFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact CloseChannelFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact CloseChannelFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact CloseChannelFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact CloseChannelFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact CloseChannelFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[extras.DuplicationKeyTypeSender] = []string{fmt.Sprintf("%s:%s", fact.sender.String(), fact.currency.String())}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type CloseChannel struct {
+	extras.ExtendedOperation
+}
+
+func (op CloseChannel) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewCloseChannel(fact CloseChannelFact) (CloseChannel, error) {
+	return CloseChannel{
+		ExtendedOperation: extras.NewExtendedOperation(CloseChannelHint, fact),
+	}, nil
+}
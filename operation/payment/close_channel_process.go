@@ -0,0 +1,208 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var closeChannelProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(CloseChannelProcessor)
+	},
+}
+
+func (CloseChannel) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type CloseChannelProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewCloseChannelProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new CloseChannelProcessor")
+
+		nopp := closeChannelProcessorPool.Get()
+		opp, ok := nopp.(*CloseChannelProcessor)
+		if !ok {
+			return nil, e.Errorf("expected CloseChannelProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *CloseChannelProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(CloseChannelFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", CloseChannelFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	if _, err := cstate.ExistsState(
+		state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	st, err := cstate.ExistsState(
+		state.ChannelStateKey(fact.Contract().String(), fact.Sender().String(), fact.Currency().String()),
+		"channel", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("channel of account, %v for currency, %v in contract account %v",
+				fact.Sender(), fact.Currency(), fact.Contract(),
+			)), nil
+	}
+
+	channel, err := state.GetChannelFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("channel of account, %v for currency, %v in contract account %v",
+				fact.Sender(), fact.Currency(), fact.Contract(),
+			)), nil
+	} else if channel.ClosingAt() > 0 {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf(
+				"channel of account, %v for currency, %v in contract account %v is already closing at %v",
+				fact.Sender(), fact.Currency(), fact.Contract(), channel.ClosingAt(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *CloseChannelProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(CloseChannelFact)
+
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	k := state.ChannelStateKey(fact.Contract().String(), fact.Sender().String(), fact.Currency().String())
+	st, _ := cstate.ExistsState(k, "channel", getStateFunc)
+	channel, _ := state.GetChannelFromState(st)
+
+	nChannel := types.NewChannel(
+		channel.Depositor(), channel.Receiver(), channel.Currency(),
+		channel.Capacity(), channel.Claimed(), channel.Nonce(),
+		channel.DisputeWindow(), nowTime+channel.DisputeWindow(),
+	)
+	if err := nChannel.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid channel of account, %v in contract account %v: %w", fact.Sender(), fact.Contract(), err), nil
+	}
+
+	return []base.StateMergeValue{cstate.NewStateMergeValue(k, state.NewChannelStateValue(nChannel))}, nil, nil
+}
+
+func (opp *CloseChannelProcessor) Close() error {
+	opp.proposal = nil
+	closeChannelProcessorPool.Put(opp)
+
+	return nil
+}
+
+// checkChannelClosed prevents the deposit backing an open channel from being
+// withdrawn before the receiver redeems the vouchers of the channel.
+func checkChannelClosed(
+	contract, depositor base.Address, cid string, nowTime uint64, getStateFunc base.GetStateFunc,
+) base.OperationProcessReasonError {
+	switch st, found, err := getStateFunc(state.ChannelStateKey(contract.String(), depositor.String(), cid)); {
+	case err != nil:
+		return base.NewBaseOperationProcessReasonError(
+			"failed to get channel of account, %v for currency, %v in contract account %v: %w",
+			depositor, cid, contract, err)
+	case found:
+		channel, err := state.GetChannelFromState(st)
+		if err != nil {
+			return base.NewBaseOperationProcessReasonError(
+				"invalid channel of account, %v for currency, %v in contract account %v: %w",
+				depositor, cid, contract, err)
+		} else if !channel.IsClosed(nowTime) {
+			return base.NewBaseOperationProcessReasonError(
+				"channel of account, %v for currency, %v is not closed in contract account %v",
+				depositor, cid, contract)
+		}
+	}
+
+	return nil
+}
+
+// checkChannelReserved keeps the capacity of an open channel, which is not
+// claimed yet, in the deposit of depositor until the channel is closed, so
+// the receiver can redeem the vouchers of the channel.
+func checkChannelReserved(
+	contract, depositor base.Address, cid string, deposit, amount common.Big, nowTime uint64,
+	getStateFunc base.GetStateFunc,
+) base.OperationProcessReasonError {
+	switch st, found, err := getStateFunc(state.ChannelStateKey(contract.String(), depositor.String(), cid)); {
+	case err != nil:
+		return base.NewBaseOperationProcessReasonError(
+			"failed to get channel of account, %v for currency, %v in contract account %v: %w",
+			depositor, cid, contract, err)
+	case found:
+		channel, err := state.GetChannelFromState(st)
+		if err != nil {
+			return base.NewBaseOperationProcessReasonError(
+				"invalid channel of account, %v for currency, %v in contract account %v: %w",
+				depositor, cid, contract, err)
+		} else if channel.IsClosed(nowTime) {
+			return nil
+		}
+
+		reserved := channel.Capacity().Sub(channel.Claimed())
+		if deposit.Sub(reserved).Compare(amount) < 0 {
+			return base.NewBaseOperationProcessReasonError(
+				"amount(%v) exceeds the deposit(%v) of account, %v except the capacity(%v) reserved for open channel in contract account %v",
+				amount, deposit, depositor, reserved, contract)
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/hint"
 	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
 	"github.com/pkg/errors"
 )
 
@@ -27,6 +28,7 @@ type DepositFact struct {
 	startTime     uint64
 	endTime       uint64
 	duration      uint64
+	lockUp        *types.LockUp
 	currency      ctypes.CurrencyID
 }
 
@@ -34,7 +36,7 @@ func NewDepositFact(
 	token []byte,
 	sender, contract base.Address,
 	amount, transferLimit common.Big,
-	startTime, endTime, duration uint64, currency ctypes.CurrencyID,
+	startTime, endTime, duration uint64, lockUp *types.LockUp, currency ctypes.CurrencyID,
 
 ) DepositFact {
 	bf := base.NewBaseFact(DepositFactHint, token)
@@ -47,6 +49,7 @@ func NewDepositFact(
 		startTime:     startTime,
 		endTime:       endTime,
 		duration:      duration,
+		lockUp:        lockUp,
 		currency:      currency,
 	}
 	fact.SetHash(fact.GenerateHash())
@@ -63,6 +66,15 @@ func (fact DepositFact) GenerateHash() util.Hash {
 }
 
 func (fact DepositFact) Bytes() []byte {
+	var lockUp []byte
+	if fact.lockUp != nil {
+		lockUp = util.ConcatBytesSlice(
+			util.Uint64ToBytes(fact.lockUp.MaturityTime),
+			util.Uint64ToBytes(fact.lockUp.PenaltyRate),
+			[]byte(fact.lockUp.PenaltyReceiver),
+		)
+	}
+
 	return util.ConcatBytesSlice(
 		fact.Token(),
 		fact.sender.Bytes(),
@@ -72,6 +84,7 @@ func (fact DepositFact) Bytes() []byte {
 		util.Uint64ToBytes(fact.startTime),
 		util.Uint64ToBytes(fact.endTime),
 		util.Uint64ToBytes(fact.duration),
+		lockUp,
 		fact.currency.Bytes(),
 	)
 }
@@ -107,6 +120,17 @@ func (fact DepositFact) IsValid(b []byte) error {
 		return common.ErrFactInvalid.Wrap(err)
 	}
 
+	if fact.lockUp != nil {
+		if err := fact.lockUp.IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+
+		if fact.lockUp.PenaltyReceiver == fact.contract.String() {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrSelfTarget.Wrap(errors.Errorf("penalty receiver %v is same with contract account", fact.contract)))
+		}
+	}
+
 	if err := common.IsValidOperationFact(fact, b); err != nil {
 		return common.ErrFactInvalid.Wrap(err)
 	}
@@ -150,6 +174,12 @@ func (fact DepositFact) Duration() uint64 {
 	return fact.duration
 }
 
+// LockUp returns the lock-up of the deposited currency; nil when the deposit
+// is not locked up.
+func (fact DepositFact) LockUp() *types.LockUp {
+	return fact.lockUp
+}
+
 func (fact DepositFact) Signer() base.Address {
 	return fact.sender
 }
@@ -177,6 +207,7 @@ func (fact DepositFact) ActiveContract() []base.Address {
 func (fact DepositFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
 	r := make(map[ctypes.DuplicationKeyType][]string)
 	r[extras.DuplicationKeyTypeSender] = []string{fmt.Sprintf("%s:%s", fact.sender.String(), fact.currency.String())}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
 
 	return r, nil
 }
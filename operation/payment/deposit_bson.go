@@ -6,37 +6,43 @@ import (
 	"github.com/imfact-labs/currency-model/utils/bsonenc"
 	"github.com/imfact-labs/mitum2/util/hint"
 	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func (fact DepositFact) MarshalBSON() ([]byte, error) {
-	return bsonenc.Marshal(
-		bson.M{
-			"_hint":          fact.Hint().String(),
-			"hash":           fact.BaseFact.Hash().String(),
-			"token":          fact.BaseFact.Token(),
-			"sender":         fact.sender,
-			"contract":       fact.contract,
-			"amount":         fact.amount,
-			"transfer_limit": fact.transferLimit,
-			"start_time":     fact.startTime,
-			"end_time":       fact.endTime,
-			"duration":       fact.duration,
-			"currency":       fact.currency,
-		},
-	)
+	m := bson.M{
+		"_hint":          fact.Hint().String(),
+		"hash":           fact.BaseFact.Hash().String(),
+		"token":          fact.BaseFact.Token(),
+		"sender":         fact.sender,
+		"contract":       fact.contract,
+		"amount":         fact.amount,
+		"transfer_limit": fact.transferLimit,
+		"start_time":     fact.startTime,
+		"end_time":       fact.endTime,
+		"duration":       fact.duration,
+		"currency":       fact.currency,
+	}
+
+	if fact.lockUp != nil {
+		m["lock_up"] = fact.lockUp
+	}
+
+	return bsonenc.Marshal(m)
 }
 
 type DepositFactBSONUnmarshaler struct {
-	Hint          string     `bson:"_hint"`
-	Sender        string     `bson:"sender"`
-	Contract      string     `bson:"contract"`
-	Amount        common.Big `bson:"amount"`
-	TransferLimit common.Big `bson:"transfer_limit"`
-	StartTime     uint64     `bson:"start_time"`
-	EndTime       uint64     `bson:"end_time"`
-	Duration      uint64     `bson:"duration"`
-	Currency      string     `bson:"currency"`
+	Hint          string        `bson:"_hint"`
+	Sender        string        `bson:"sender"`
+	Contract      string        `bson:"contract"`
+	Amount        common.Big    `bson:"amount"`
+	TransferLimit common.Big    `bson:"transfer_limit"`
+	StartTime     uint64        `bson:"start_time"`
+	EndTime       uint64        `bson:"end_time"`
+	Duration      uint64        `bson:"duration"`
+	LockUp        *types.LockUp `bson:"lock_up,omitempty"`
+	Currency      string        `bson:"currency"`
 }
 
 func (fact *DepositFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
@@ -62,6 +68,7 @@ func (fact *DepositFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 	fact.BaseHinter = hint.NewBaseHinter(ht)
 	fact.amount = uf.Amount
 	fact.transferLimit = uf.TransferLimit
+	fact.lockUp = uf.LockUp
 
 	if err := fact.unpack(
 		enc, uf.Sender, uf.Contract, uf.StartTime, uf.EndTime, uf.Duration, uf.Currency,
@@ -0,0 +1,234 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	DepositItemsFactHint = hint.MustNewHint("mitum-payment-deposit-items-operation-fact-v0.0.1")
+	DepositItemsHint     = hint.MustNewHint("mitum-payment-deposit-items-operation-v0.0.1")
+)
+
+var MaxDepositItems = 10
+
+type DepositItem struct {
+	Currency ctypes.CurrencyID `bson:"currency" json:"currency"`
+	Amount   common.Big        `bson:"amount" json:"amount"`
+	Setting  types.SettingItem `bson:"setting" json:"setting"`
+}
+
+func NewDepositItem(
+	currency ctypes.CurrencyID, amount, transferLimit common.Big, startTime, endTime, duration uint64,
+) DepositItem {
+	return DepositItem{
+		Currency: currency,
+		Amount:   amount,
+		Setting:  types.NewSettingItem(transferLimit, startTime, endTime, duration),
+	}
+}
+
+func (it DepositItem) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		it.Currency.Bytes(),
+		it.Amount.Bytes(),
+		it.Setting.TransferLimit.Bytes(),
+		util.Uint64ToBytes(it.Setting.StartTime),
+		util.Uint64ToBytes(it.Setting.EndTime),
+		util.Uint64ToBytes(it.Setting.Duration),
+	)
+}
+
+func (it DepositItem) IsValid([]byte) error {
+	if it.Amount.IsZero() {
+		return common.ErrValueInvalid.Errorf("amount cannot be zero")
+	} else if it.Setting.EndTime == 0 {
+		return common.ErrValueInvalid.Errorf("end time cannot be zero")
+	} else if it.Setting.StartTime >= it.Setting.EndTime {
+		return common.ErrValueInvalid.Errorf("start time cannot be greater than end time or equal with end time")
+	} else if it.Setting.Duration > (it.Setting.EndTime - it.Setting.StartTime) {
+		return common.ErrValueInvalid.Errorf("duration cannot be greater than the difference between start and end time")
+	}
+
+	return util.CheckIsValiders(nil, false,
+		it.Currency,
+		it.Amount,
+		it.Setting.TransferLimit,
+	)
+}
+
+type DepositItemsFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	items    []DepositItem
+	currency ctypes.CurrencyID
+}
+
+func NewDepositItemsFact(
+	token []byte, sender, contract base.Address, items []DepositItem, currency ctypes.CurrencyID,
+) DepositItemsFact {
+	bf := base.NewBaseFact(DepositItemsFactHint, token)
+	fact := DepositItemsFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		items:    items,
+		currency: currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact DepositItemsFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact DepositItemsFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact DepositItemsFact) Bytes() []byte {
+	bs := make([][]byte, len(fact.items))
+	for i := range fact.items {
+		bs[i] = fact.items[i].Bytes()
+	}
+
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		util.ConcatBytesSlice(bs...),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact DepositItemsFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if n := len(fact.items); n < 1 {
+		return common.ErrFactInvalid.Wrap(common.ErrArrayLen.Wrap(errors.Errorf("empty items")))
+	} else if n > MaxDepositItems {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrArrayLen.Wrap(errors.Errorf("items, %d over max, %d", n, MaxDepositItems)))
+	}
+
+	founds := map[ctypes.CurrencyID]struct{}{}
+	for i := range fact.items {
+		it := fact.items[i]
+		if err := it.IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+
+		if _, found := founds[it.Currency]; found {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrDupVal.Wrap(errors.Errorf("currency %v in items", it.Currency)))
+		}
+		founds[it.Currency] = struct{}{}
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact DepositItemsFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact DepositItemsFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact DepositItemsFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact DepositItemsFact) Items() []DepositItem {
+	return fact.items
+}
+
+func (fact DepositItemsFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact DepositItemsFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact DepositItemsFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.Sender()}, nil
+}
+
+func (fact DepositItemsFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), len(fact.items), len(fact.Bytes()), extras.HasItem
+}
+
+func (fact DepositItemsFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact DepositItemsFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact DepositItemsFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact DepositItemsFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	keys := make([]string, len(fact.items))
+	for i := range fact.items {
+		keys[i] = fmt.Sprintf("%s:%s", fact.sender.String(), fact.items[i].Currency.String())
+	}
+	r[extras.DuplicationKeyTypeSender] = keys
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type DepositItems struct {
+	extras.ExtendedOperation
+}
+
+func (op DepositItems) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewDepositItems(fact base.Fact) (DepositItems, error) {
+	return DepositItems{
+		ExtendedOperation: extras.NewExtendedOperation(DepositItemsHint, fact),
+	}, nil
+}
@@ -0,0 +1,224 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var depositItemsProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(DepositItemsProcessor)
+	},
+}
+
+func (DepositItems) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type DepositItemsProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewDepositItemsProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new DepositItemsProcessor")
+
+		nopp := depositItemsProcessorPool.Get()
+		opp, ok := nopp.(*DepositItemsProcessor)
+		if !ok {
+			return nil, e.Errorf("expected DepositItemsProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *DepositItemsProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(DepositItemsFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", DepositItemsFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	for _, it := range fact.Items() {
+		_, err := cstate.ExistsState(currency.BalanceStateKey(fact.Sender(), it.Currency),
+			fmt.Sprintf("balance of currency, %v of account, %v", it.Currency, fact.Sender()), getStateFunc,
+		)
+		if err != nil {
+			return ctx, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.Wrap(common.ErrMStateNF).
+					Errorf("%v", err)), nil
+		}
+	}
+
+	st, err := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf(
+				"service design value not found, %v: %v", fact.Contract(), err)), nil
+	}
+
+	if setting := design.AccountSetting(fact.Sender().String()); setting != nil {
+		if setting.IsLocked() {
+			return nil, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.
+					Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+					fact.Sender(), fact.Contract(),
+				)), nil
+		}
+
+		if _, err := cstate.ExistsState(state.DepositRecordStateKey(
+			fact.Contract().String(), fact.Sender().String()), "account record", getStateFunc); err != nil {
+			return nil, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.
+					Wrap(common.ErrMStateNF).Errorf(
+					"record of account, %v nof found in contract account, %v: %v", fact.Sender(), fact.Contract(), err)), nil
+		}
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *DepositItemsProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(DepositItemsFact)
+
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	design, _ := state.GetDesignFromState(st)
+	setting := design.AccountSetting(fact.Sender().String())
+
+	var nSetting types.Setting
+	if setting != nil {
+		nSetting = copyAccountSetting(*setting)
+	} else {
+		if err := design.CheckAccountsLimit(); err != nil {
+			return nil, base.NewBaseOperationProcessReasonError(
+				"failed to add setting of account, %v in contract account %v: %w", fact.Sender(), fact.Contract(), err,
+			), nil
+		}
+
+		nSetting = types.NewSettings(fact.Sender())
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+	for _, it := range fact.Items() {
+		cid := it.Currency
+
+		sts = append(sts, state.NewDepositRecordStateMergeValue(
+			state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+			state.NewAddDepositRecordStateValue(fact.Sender(), cid.String(), it.Amount),
+		))
+
+		nSetting.SetItem(
+			cid.String(), it.Setting.TransferLimit, it.Setting.StartTime, it.Setting.EndTime, it.Setting.Duration)
+
+		am := ctypes.NewAmount(it.Amount, cid)
+		sts = append(
+			sts,
+			common.NewBaseStateMergeValue(
+				currency.BalanceStateKey(fact.Sender(), cid),
+				currency.NewDeductBalanceStateValue(am),
+				func(height base.Height, st base.State) base.StateValueMerger {
+					return currency.NewBalanceStateValueMerger(
+						height, currency.BalanceStateKey(fact.Sender(), cid),
+						cid, st,
+					)
+				}),
+		)
+
+		sts = append(sts, common.NewBaseStateMergeValue(
+			currency.BalanceStateKey(fact.Contract(), cid),
+			currency.NewAddBalanceStateValue(am),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return currency.NewBalanceStateValueMerger(height,
+					currency.BalanceStateKey(fact.Contract(), cid),
+					cid, st,
+				)
+			},
+		))
+
+		sts = append(sts, common.NewBaseStateMergeValue(
+			state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()),
+			state.NewAppendEventLogStateValue(fact.Sender(), types.NewEvent(
+				types.EventTypeDeposit, cid.String(), it.Amount, "", fact.Hash(), nowTime, opp.Height(),
+			)),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return state.NewEventLogStateValueMerger(height,
+					state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()), st,
+				)
+			},
+		))
+	}
+
+	if err := nSetting.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid setting of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
+	}
+
+	sts = append(sts, state.NewDesignStateMergeValue(
+		state.DesignStateKey(fact.Contract().String()),
+		state.NewSetDesignAccountStateValue(setting, nSetting),
+	))
+
+	return sts, nil, nil
+}
+
+func (opp *DepositItemsProcessor) Close() error {
+	opp.proposal = nil
+	depositItemsProcessorPool.Put(opp)
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+)
+
+func (pt *paymentTest) currencyBalance(addr base.Address, cid ctypes.CurrencyID) common.Big {
+	st, found, _ := pt.GetStateFunc(currency.BalanceStateKey(addr, cid))
+	if !found {
+		return common.ZeroBig
+	}
+
+	am, err := currency.StateBalanceValue(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	return am.Big()
+}
+
+func TestDepositItemsFact(t *testing.T) {
+	sender, contract := ctypes.NewStringAddress("sender"), ctypes.NewStringAddress("contract")
+
+	newFact := func(cids ...ctypes.CurrencyID) payment.DepositItemsFact {
+		items := make([]payment.DepositItem, len(cids))
+		for i := range cids {
+			items[i] = payment.NewDepositItem(cids[i], common.NewBig(100), common.NewBig(10), 1, 100, 1)
+		}
+
+		return payment.NewDepositItemsFact([]byte("token"), sender, contract, items, "MCC")
+	}
+
+	over := make([]ctypes.CurrencyID, payment.MaxDepositItems+1)
+	for i := range over {
+		over[i] = ctypes.CurrencyID(string(rune('A'+i)) + "CC")
+	}
+
+	for name, c := range map[string]struct {
+		fact  payment.DepositItemsFact
+		valid bool
+	}{
+		"items":          {newFact("MCC", "PEN"), true},
+		"empty":          {newFact(), false},
+		"same currency":  {newFact("MCC", "PEN", "MCC"), false},
+		"over max items": {newFact(over...), false},
+		"max items":      {newFact(over[:payment.MaxDepositItems]...), true},
+	} {
+		if err := c.fact.IsValid(nil); (err == nil) != c.valid {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestDepositItemsAndWithdrawAll(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender := pt.account("sender")
+	cids := []ctypes.CurrencyID{pt.GenesisCurrency, "PEN"}
+
+	for _, cid := range cids {
+		pt.NewTestBalanceState(sender, cid, 1000, true)
+	}
+
+	op, err := payment.NewDepositItems(payment.NewDepositItemsFact([]byte("token"), sender, pt.contract, []payment.DepositItem{
+		payment.NewDepositItem(cids[0], common.NewBig(100), common.NewBig(10), 1, pt.now+3600, 1),
+		payment.NewDepositItem(cids[1], common.NewBig(200), common.NewBig(20), 1, pt.now+3600, 1),
+	}, pt.GenesisCurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err := pt.process(payment.NewDepositItemsProcessor(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	setting := pt.design().AccountSetting(sender.String())
+	if setting == nil {
+		t.Fatal("setting not added")
+	}
+
+	st, _, _ := pt.GetStateFunc(state.DepositRecordStateKey(pt.contract.String(), sender.String()))
+
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, cid := range cids {
+		amount := common.NewBig(int64(100 * (i + 1)))
+
+		switch {
+		case record.Amount(cid.String()) == nil || !record.Amount(cid.String()).Equal(amount):
+			t.Errorf("deposit of %v, %v", cid, record.Amount(cid.String()))
+		case setting.TransferLimit(cid.String()) == nil ||
+			!setting.TransferLimit(cid.String()).Equal(common.NewBig(int64(10*(i+1)))):
+			t.Errorf("transfer limit of %v, %v", cid, setting.TransferLimit(cid.String()))
+		case !pt.currencyBalance(sender, cid).Equal(common.NewBig(1000).Sub(amount)):
+			t.Errorf("balance of %v, %v", cid, pt.currencyBalance(sender, cid))
+		case !pt.currencyBalance(pt.contract, cid).Equal(amount):
+			t.Errorf("contract balance of %v, %v", cid, pt.currencyBalance(pt.contract, cid))
+		}
+	}
+
+	if evs := pt.events(sender); len(evs) != len(cids) {
+		t.Errorf("%d events", len(evs))
+	}
+
+	wop, err := payment.NewWithdrawAll(payment.NewWithdrawAllFact([]byte("token"), sender, pt.contract, pt.GenesisCurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err = pt.process(payment.NewWithdrawAllProcessor(), wop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	if pt.design().AccountSetting(sender.String()) != nil {
+		t.Error("setting not removed")
+	}
+
+	for _, cid := range cids {
+		if i := pt.currencyBalance(sender, cid); !i.Equal(common.NewBig(1000)) {
+			t.Errorf("balance of %v, %v", cid, i)
+		}
+
+		if i := pt.currencyBalance(pt.contract, cid); !i.Equal(common.ZeroBig) {
+			t.Errorf("contract balance of %v, %v", cid, i)
+		}
+	}
+}
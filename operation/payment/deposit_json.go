@@ -7,6 +7,7 @@ import (
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/payment-model/types"
 )
 
 type DepositFactJSONMarshaler struct {
@@ -18,6 +19,7 @@ type DepositFactJSONMarshaler struct {
 	StartTime     uint64            `json:"start_time"`
 	EndTime       uint64            `json:"end_time"`
 	Duration      uint64            `json:"duration"`
+	LockUp        *types.LockUp     `json:"lock_up,omitempty"`
 	Currency      ctypes.CurrencyID `json:"currency"`
 }
 
@@ -31,22 +33,24 @@ func (fact DepositFact) MarshalJSON() ([]byte, error) {
 		StartTime:             fact.startTime,
 		EndTime:               fact.endTime,
 		Duration:              fact.duration,
+		LockUp:                fact.lockUp,
 		Currency:              fact.currency,
 	})
 }
 
 type DepositFactJSONUnmarshaler struct {
 	base.BaseFactJSONUnmarshaler
-	Sender        string     `json:"sender"`
-	Contract      string     `json:"contract"`
-	Account       string     `json:"account"`
-	Amount        common.Big `json:"amount"`
-	TransferLimit common.Big `json:"transfer_limit"`
-	StartTime     uint64     `json:"start_time"`
-	EndTime       uint64     `json:"end_time"`
-	Duration      uint64     `json:"duration"`
-	Editable      bool       `json:"editable"`
-	Currency      string     `json:"currency"`
+	Sender        string        `json:"sender"`
+	Contract      string        `json:"contract"`
+	Account       string        `json:"account"`
+	Amount        common.Big    `json:"amount"`
+	TransferLimit common.Big    `json:"transfer_limit"`
+	StartTime     uint64        `json:"start_time"`
+	EndTime       uint64        `json:"end_time"`
+	Duration      uint64        `json:"duration"`
+	LockUp        *types.LockUp `json:"lock_up"`
+	Editable      bool          `json:"editable"`
+	Currency      string        `json:"currency"`
 }
 
 func (fact *DepositFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
@@ -58,6 +62,7 @@ func (fact *DepositFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
 	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
 	fact.amount = u.Amount
 	fact.transferLimit = u.TransferLimit
+	fact.lockUp = u.LockUp
 
 	if err := fact.unpack(
 		enc, u.Sender, u.Contract, u.StartTime, u.EndTime, u.Duration, u.Currency,
@@ -29,11 +29,13 @@ func (Deposit) Process(
 
 type DepositProcessor struct {
 	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
 }
 
-func NewDepositProcessor() ctypes.GetNewProcessor {
+func NewDepositProcessor() ctypes.GetNewProcessorWithProposal {
 	return func(
 		height base.Height,
+		proposal *base.ProposalSignFact,
 		getStateFunc base.GetStateFunc,
 		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
 		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
@@ -53,6 +55,7 @@ func NewDepositProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
 
 		return opp, nil
 	}
@@ -101,6 +104,14 @@ func (opp *DepositProcessor) PreProcess(
 	}
 	setting := design.AccountSetting(fact.Sender().String())
 	if setting != nil {
+		if setting.IsLocked() {
+			return nil, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.
+					Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+					fact.Sender(), fact.Contract(),
+				)), nil
+		}
+
 		st, err = cstate.ExistsState(state.DepositRecordStateKey(
 			fact.Contract().String(), fact.Sender().String()), "account record", getStateFunc)
 		if err != nil {
@@ -109,6 +120,17 @@ func (opp *DepositProcessor) PreProcess(
 					Wrap(common.ErrMStateNF).Errorf(
 					"record of account, %v nof found in contract account, %v: %v", fact.Sender(), fact.Contract(), err)), nil
 		}
+
+		nowTime := uint64((*opp.proposal).ProposalFact().ProposedAt().Unix())
+
+		if prev := setting.LockUp(fact.Currency().String()); prev != nil && fact.LockUp() != nil &&
+			fact.LockUp().Weakens(*prev, nowTime) {
+			return nil, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.
+					Wrap(common.ErrMValueInvalid).Errorf(
+					"lock-up of account, %v for currency id, %v cannot be weakened in contract account, %v",
+					fact.Sender(), fact.Currency(), fact.Contract())), nil
+		}
 	}
 
 	return ctx, nil, nil
@@ -121,100 +143,46 @@ func (opp *DepositProcessor) Process( // nolint:dupl
 	fact, _ := op.Fact().(DepositFact)
 
 	cid := fact.Currency()
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
 	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
 	design, _ := state.GetDesignFromState(st)
 
 	var sts []base.StateMergeValue // nolint:prealloc
 	setting := design.AccountSetting(fact.Sender().String())
 
+	var nSetting types.Setting
 	if setting != nil {
 		// additional deposit
-		st, _ := cstate.ExistsState(state.DepositRecordStateKey(
-			fact.Contract().String(), fact.Sender().String()), "account record", getStateFunc)
-		record, _ := state.GetDepositRecordFromState(st)
-		amount := record.Amount(cid.String())
-
-		var nAmount common.Big
-		var nTransfferdAt uint64
-		if amount == nil {
-			nAmount = fact.Amount()
-			nTransfferdAt = 0
-		} else {
-			nAmount = amount.Add(fact.Amount())
-			nTransfferdAt = *record.TransferredAt(cid.String())
-		}
-
-		nRecord := types.NewDepositRecord(fact.Sender())
-		for k, v := range record.Items() {
-			nRecord.SetItem(k, v.Amount, v.TransferredAt)
-		}
-		nRecord.SetItem(cid.String(), nAmount, nTransfferdAt)
-
-		if err := nRecord.IsValid(nil); err != nil {
-			return nil, base.NewBaseOperationProcessReasonError(
-				"invalid record of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
-		}
-		// update Record
-		sts = append(sts, cstate.NewStateMergeValue(
-			state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
-			state.NewDepositRecordStateValue(nRecord),
-		))
-
-		// update AccountSetting
-		nSetting := types.NewSettings(fact.Sender())
-		for k, v := range setting.Items() {
-			nSetting.SetItem(k, v.TransferLimit, v.StartTime, v.EndTime, v.Duration)
-		}
-		nSetting.SetItem(cid.String(), fact.TransferLimit(), fact.StartTime(), fact.EndTime(), fact.Duration())
-		nDesign := types.NewDesign()
-		for _, v := range design.AccountSettings() {
-			nDesign.AddAccountSetting(v)
-		}
-		if err := nDesign.UpdateAccountSetting(nSetting); err != nil {
-			return nil, base.NewBaseOperationProcessReasonError(
-				"failed to update setting of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
-		}
-
-		sts = append(sts, cstate.NewStateMergeValue(
-			state.DesignStateKey(fact.Contract().String()),
-			state.NewDesignStateValue(nDesign),
-		))
+		nSetting = copyAccountSetting(*setting)
 	} else {
-		nSetting := types.NewSettings(fact.Sender())
-		nSetting.SetItem(cid.String(), fact.TransferLimit(), fact.StartTime(), fact.EndTime(), fact.Duration())
-		nDesign := types.NewDesign()
-		for _, v := range design.AccountSettings() {
-			nDesign.AddAccountSetting(v)
-		}
-		err := nDesign.AddAccountSetting(nSetting)
-		if err != nil {
+		if err := design.CheckAccountsLimit(); err != nil {
 			return nil, base.NewBaseOperationProcessReasonError(
 				"failed to add setting of account, %v in contract account %v: %w", fact.Sender(), fact.Contract(), err,
 			), nil
 		}
-		if err := nDesign.IsValid(nil); err != nil {
-			return nil, base.NewBaseOperationProcessReasonError("invalid service design, %q; %w", fact.Contract(), err), nil
-		}
 
-		sts = append(sts, cstate.NewStateMergeValue(
-			state.DesignStateKey(fact.Contract().String()),
-			state.NewDesignStateValue(nDesign),
-		))
+		nSetting = types.NewSettings(fact.Sender())
+	}
+	nSetting.SetItem(cid.String(), fact.TransferLimit(), fact.StartTime(), fact.EndTime(), fact.Duration())
+	if fact.LockUp() != nil {
+		nSetting.SetLockUp(cid.String(), fact.LockUp())
+	}
 
-		// new AccountRecord
-		nRecord := types.NewDepositRecord(fact.Sender())
-		nRecord.SetItem(cid.String(), fact.Amount(), 0)
+	if err := nSetting.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid setting of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
+	}
 
-		if err := nRecord.IsValid(nil); err != nil {
-			return nil, base.NewBaseOperationProcessReasonError(
-				"invalid record of account, %v in contract account, %v; %w", fact.Sender(), fact.Contract(), err), nil
-		}
+	sts = append(sts, state.NewDesignStateMergeValue(
+		state.DesignStateKey(fact.Contract().String()),
+		state.NewSetDesignAccountStateValue(setting, nSetting),
+	))
 
-		sts = append(sts, cstate.NewStateMergeValue(
-			state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
-			state.NewDepositRecordStateValue(nRecord),
-		))
-	}
+	sts = append(sts, state.NewDepositRecordStateMergeValue(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		state.NewAddDepositRecordStateValue(fact.Sender(), cid.String(), fact.Amount()),
+	))
 
 	am := ctypes.NewAmount(fact.Amount(), cid)
 	sts = append(
@@ -241,10 +209,23 @@ func (opp *DepositProcessor) Process( // nolint:dupl
 		},
 	))
 
+	sts = append(sts, common.NewBaseStateMergeValue(
+		state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()),
+		state.NewAppendEventLogStateValue(fact.Sender(), types.NewEvent(
+			types.EventTypeDeposit, cid.String(), fact.Amount(), "", fact.Hash(), nowTime, opp.Height(),
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()), st,
+			)
+		},
+	))
+
 	return sts, nil, nil
 }
 
 func (opp *DepositProcessor) Close() error {
+	opp.proposal = nil
 	depositProcessorPool.Put(opp)
 
 	return nil
@@ -0,0 +1,92 @@
+package payment_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestDepositAccountsLimit(t *testing.T) {
+	pt := newPaymentTest(t)
+	a, b := pt.account("a"), pt.account("b")
+
+	settings := make([]types.Setting, 999)
+	for i := range settings {
+		settings[i] = pt.setting(ctypes.NewStringAddress(fmt.Sprintf("account%d", i)), 1000)
+	}
+
+	pt.setDesign(settings...)
+
+	ops := make([]base.Operation, 2)
+	for i, account := range []base.Address{a, b} {
+		pt.NewTestBalanceState(account, pt.GenesisCurrency, 1000, true)
+
+		op, err := payment.NewDeposit(payment.NewDepositFact(
+			[]byte("token"), account, pt.contract, common.NewBig(100), common.NewBig(1000),
+			1, pt.now+3600, 1, nil, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ops[i] = op
+	}
+
+	reasons, err := pt.process(payment.NewDepositProcessor(), ops...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range reasons {
+		if reasons[i] != nil {
+			t.Fatal(reasons[i])
+		}
+	}
+
+	// NOTE the account of the lower fact hash is added.
+	added, rejected := a, b
+	if ops[1].Fact().Hash().String() < ops[0].Fact().Hash().String() {
+		added, rejected = b, a
+	}
+
+	design := pt.design()
+
+	switch {
+	case len(design.AccountSettings()) != 1000:
+		t.Errorf("%d accounts", len(design.AccountSettings()))
+	case design.AccountSetting(added.String()) == nil:
+		t.Error("account of lower fact hash not added")
+	case design.AccountSetting(rejected.String()) != nil:
+		t.Error("account over limit added")
+	}
+
+	op, err := payment.NewWithdraw(payment.NewWithdrawFact([]byte("token"), rejected, pt.contract, pt.GenesisCurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err = pt.process(payment.NewWithdrawProcessor(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	if am := pt.deposit(rejected); !am.Equal(common.ZeroBig) {
+		t.Errorf("deposit of account over limit, %v", am)
+	}
+
+	if i := pt.balance(rejected); !i.Equal(common.NewBig(1000)) {
+		t.Errorf("balance of account over limit, %v", i)
+	}
+
+	if len(pt.design().AccountSettings()) != 1000 {
+		t.Error("design changed by withdraw without setting")
+	}
+}
@@ -0,0 +1,17 @@
+package payment
+
+import (
+	"fmt"
+
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+)
+
+// DuplicationKeyTypeDepositor allows one operation which modifies the deposit
+// record of a depositor in a proposal; the operations of the different
+// depositors of a contract can be processed in the same block.
+const DuplicationKeyTypeDepositor ctypes.DuplicationKeyType = "payment-depositor"
+
+func depositorDupKey(contract, depositor base.Address) string {
+	return fmt.Sprintf("%s:%s", contract.String(), depositor.String())
+}
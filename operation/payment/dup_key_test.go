@@ -0,0 +1,97 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+)
+
+type dupKeyer interface {
+	DupKey() (map[ctypes.DuplicationKeyType][]string, error)
+}
+
+func sharesDupKey(t *testing.T, a, b dupKeyer) bool {
+	t.Helper()
+
+	ak, err := a.DupKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bk, err := b.DupKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, keys := range ak {
+		for i := range keys {
+			for j := range bk[k] {
+				if keys[i] == bk[k][j] {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func TestDupKeyDepositors(t *testing.T) {
+	pt := newPaymentTest(t)
+	a, b, receiver := pt.account("a"), pt.account("b"), pt.account("receiver")
+
+	deposit := func(sender base.Address) payment.DepositFact {
+		return payment.NewDepositFact([]byte("token"), sender, pt.contract, common.NewBig(100),
+			common.NewBig(100), 1, pt.now+3600, 1, nil, pt.GenesisCurrency)
+	}
+
+	depositItems := payment.NewDepositItemsFact([]byte("token"), b, pt.contract,
+		[]payment.DepositItem{payment.NewDepositItem(
+			pt.GenesisCurrency, common.NewBig(100), common.NewBig(100), 1, pt.now+3600, 1)},
+		pt.GenesisCurrency)
+
+	transfer := func(sender base.Address) payment.TransferFact {
+		return payment.NewTransferFact([]byte("token"), sender, pt.contract, receiver,
+			common.NewBig(10), pt.GenesisCurrency)
+	}
+
+	if sharesDupKey(t, transfer(a), transfer(b)) {
+		t.Error("transfers of different depositors share dup key")
+	}
+
+	if !sharesDupKey(t, transfer(a), transfer(a)) {
+		t.Error("transfers of same depositor do not share dup key")
+	}
+
+	if sharesDupKey(t, deposit(a), transfer(b)) {
+		t.Error("deposit and transfer of different depositors share dup key")
+	}
+
+	if sharesDupKey(t, deposit(a), deposit(b)) {
+		t.Error("deposits of different depositors share dup key")
+	}
+
+	if sharesDupKey(t, deposit(a), depositItems) {
+		t.Error("deposit and deposit items of different depositors share dup key")
+	}
+
+	if !sharesDupKey(t, deposit(b), depositItems) {
+		t.Error("deposit and deposit items of same depositor do not share dup key")
+	}
+
+	refund := func(sender base.Address, hashLock string) payment.RefundTransferFact {
+		return payment.NewRefundTransferFact([]byte("token"), sender, pt.contract, hashLock, pt.GenesisCurrency)
+	}
+
+	// NOTE the refunds of a sender append to the same event log.
+	if !sharesDupKey(t, refund(a, "a"), refund(a, "b")) {
+		t.Error("refunds of same sender do not share dup key")
+	}
+
+	if sharesDupKey(t, refund(a, "a"), refund(b, "b")) {
+		t.Error("refunds of different senders share dup key")
+	}
+}
@@ -0,0 +1,97 @@
+package payment_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+// setLock sets the lock of the sender to the receiver and returns the
+// preimage of it.
+func (pt *paymentTest) setLock(sender, receiver base.Address, amount int64, timeout uint64) (string, string) {
+	preimage := hex.EncodeToString([]byte(fmt.Sprintf("preimage-%d-%d", amount, timeout)))
+
+	b, _ := hex.DecodeString(preimage)
+	h := sha256.Sum256(b)
+	hashLock := hex.EncodeToString(h[:])
+
+	pt.setState(state.LockStateKey(pt.contract.String(), hashLock), state.NewLockStateValue(types.NewLock(
+		sender, receiver, pt.GenesisCurrency, common.NewBig(amount), hashLock, timeout, "", types.LockStatusLocked)))
+	pt.NewTestBalanceState(pt.contract, pt.GenesisCurrency, amount, true)
+
+	return hashLock, preimage
+}
+
+func (pt *paymentTest) events(account base.Address) []types.Event {
+	st, found, _ := pt.GetStateFunc(state.EventLogStateKey(pt.contract.String(), account.String()))
+	if !found {
+		return nil
+	}
+
+	log, err := state.GetEventLogFromState(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	return log.Events()
+}
+
+func TestEventLogClaimsInBlock(t *testing.T) {
+	defer func(size int) {
+		types.MaxEventLogSize = size
+	}(types.MaxEventLogSize)
+
+	types.MaxEventLogSize = 2
+
+	pt := newPaymentTest(t)
+	sender, receiver := pt.account("sender"), pt.account("receiver")
+
+	pt.setDesign(pt.setting(sender, 1000))
+
+	ops := make([]base.Operation, 3)
+	for i := range ops {
+		hashLock, preimage := pt.setLock(sender, receiver, int64(i+1), pt.now+3600)
+
+		op, err := payment.NewClaimTransfer(payment.NewClaimTransferFact(
+			[]byte("token"), receiver, pt.contract, hashLock, preimage, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ops[i] = op
+	}
+
+	reasons, err := pt.process(payment.NewClaimTransferProcessor(), ops...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range reasons {
+		if reasons[i] != nil {
+			t.Fatal(reasons[i])
+		}
+	}
+
+	// NOTE all the claims of the block are kept for the digest.
+	evs := pt.events(sender)
+	if len(evs) != len(ops) {
+		t.Fatalf("%d events, not %d", len(evs), len(ops))
+	}
+
+	for i := range evs {
+		if evs[i].Type != types.EventTypeClaimTransfer || evs[i].Height != testHeight.Int64() {
+			t.Errorf("event %d, %v", i, evs[i])
+		}
+	}
+
+	if i := pt.balance(receiver); !i.Equal(common.NewBig(1 + 2 + 3)) {
+		t.Errorf("receiver balance %v", i)
+	}
+}
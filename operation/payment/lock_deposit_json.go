@@ -0,0 +1,86 @@
+package payment
+
+import (
+	"encoding/json"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type LockDepositFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	Sender    base.Address      `json:"sender"`
+	Contract  base.Address      `json:"contract"`
+	Depositor base.Address      `json:"depositor"`
+	Sign      base.Sign         `json:"sign"`
+	Currency  ctypes.CurrencyID `json:"currency"`
+}
+
+func (fact LockDepositFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(LockDepositFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Sender:                fact.sender,
+		Contract:              fact.contract,
+		Depositor:             fact.depositor,
+		Sign:                  fact.sign,
+		Currency:              fact.currency,
+	})
+}
+
+type LockDepositFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	Sender    string          `json:"sender"`
+	Contract  string          `json:"contract"`
+	Depositor string          `json:"depositor"`
+	Sign      json.RawMessage `json:"sign"`
+	Currency  string          `json:"currency"`
+}
+
+func (fact *LockDepositFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var u LockDepositFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+
+	sign, err := decodeKeySignJSON(u.Sign, enc)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	if err := fact.unpack(enc, u.Sender, u.Contract, u.Depositor, sign, u.Currency); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op LockDeposit) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *LockDeposit) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package payment_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+)
+
+// newLockDepositTest sets the deposit of the depositor with the lock key and
+// the recovery key.
+func newLockDepositTest(t *testing.T, locked bool) (*paymentTest, base.Address, base.Privatekey, base.Privatekey) {
+	pt := newPaymentTest(t)
+	depositor := pt.account("depositor")
+	lockKey, recoveryKey := base.NewMPrivatekey(), base.NewMPrivatekey()
+
+	setting := pt.setting(depositor, 1000)
+	setting.SetKeys(lockKey.Publickey(), recoveryKey.Publickey())
+	setting.SetLocked(locked)
+
+	pt.setDesign(setting)
+	pt.setDeposit(depositor, 1000, 1)
+	pt.NewTestBalanceState(depositor, pt.GenesisCurrency, 1000, true)
+
+	return pt, depositor, lockKey, recoveryKey
+}
+
+func TestLockDeposit(t *testing.T) {
+	for name, c := range map[string]struct {
+		key    func(lockKey, recoveryKey base.Privatekey) base.Privatekey
+		locked bool
+	}{
+		"lock key":     {key: func(lockKey, _ base.Privatekey) base.Privatekey { return lockKey }, locked: true},
+		"recovery key": {key: func(_, recoveryKey base.Privatekey) base.Privatekey { return recoveryKey }},
+		"other key":    {key: func(base.Privatekey, base.Privatekey) base.Privatekey { return base.NewMPrivatekey() }},
+	} {
+		pt, depositor, lockKey, recoveryKey := newLockDepositTest(t, false)
+
+		// NOTE any account can submit the lock signed by the lock key.
+		fact := payment.NewLockDepositFact([]byte("token"), pt.account("other"), pt.contract, depositor, pt.GenesisCurrency)
+		if err := fact.SignWithKey(c.key(lockKey, recoveryKey), pt.NetworkID); err != nil {
+			t.Fatal(err)
+		}
+
+		op, err := payment.NewLockDeposit(fact)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewLockDepositProcessor(pt.NetworkID), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if locked := reasons[0] == nil; locked != c.locked {
+			t.Errorf("%s: locked %v, not %v: %v", name, locked, c.locked, reasons[0])
+		}
+
+		if locked := pt.design().AccountSetting(depositor.String()).IsLocked(); locked != c.locked {
+			t.Errorf("%s: setting locked %v", name, locked)
+		}
+	}
+}
+
+func TestUnlockDeposit(t *testing.T) {
+	for name, c := range map[string]struct {
+		key      func(lockKey, recoveryKey base.Privatekey) base.Privatekey
+		unlocked bool
+	}{
+		"recovery key": {key: func(_, recoveryKey base.Privatekey) base.Privatekey { return recoveryKey }, unlocked: true},
+		"lock key":     {key: func(lockKey, _ base.Privatekey) base.Privatekey { return lockKey }},
+	} {
+		pt, depositor, lockKey, recoveryKey := newLockDepositTest(t, true)
+
+		fact := payment.NewUnlockDepositFact([]byte("token"), depositor, pt.contract, depositor, pt.GenesisCurrency)
+		if err := fact.SignWithKey(c.key(lockKey, recoveryKey), pt.NetworkID); err != nil {
+			t.Fatal(err)
+		}
+
+		op, err := payment.NewUnlockDeposit(fact)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewUnlockDepositProcessor(pt.NetworkID), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if unlocked := reasons[0] == nil; unlocked != c.unlocked {
+			t.Errorf("%s: unlocked %v, not %v: %v", name, unlocked, c.unlocked, reasons[0])
+		}
+
+		if locked := pt.design().AccountSetting(depositor.String()).IsLocked(); locked == c.unlocked {
+			t.Errorf("%s: setting locked %v", name, locked)
+		}
+	}
+}
+
+func TestLockedDeposit(t *testing.T) {
+	pt, depositor, _, _ := newLockDepositTest(t, true)
+	receiver := pt.account("receiver")
+
+	newOp := func(op base.Operation, err error) base.Operation {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return op
+	}
+
+	for name, c := range map[string]struct {
+		op           base.Operation
+		newProcessor ctypes.GetNewProcessorWithProposal
+	}{
+		"deposit": {
+			op: newOp(payment.NewDeposit(payment.NewDepositFact(
+				[]byte("token"), depositor, pt.contract, common.NewBig(100), common.NewBig(100000),
+				1, pt.now+360000, 1, nil, pt.GenesisCurrency))),
+			newProcessor: payment.NewDepositProcessor(),
+		},
+		"deposit items": {
+			op: newOp(payment.NewDepositItems(payment.NewDepositItemsFact(
+				[]byte("token"), depositor, pt.contract, []payment.DepositItem{payment.NewDepositItem(
+					pt.GenesisCurrency, common.NewBig(100), common.NewBig(100000), 1, pt.now+360000, 1)},
+				pt.GenesisCurrency))),
+			newProcessor: payment.NewDepositItemsProcessor(),
+		},
+		"update setting": {
+			op: newOp(payment.NewUpdateAccountSetting(payment.NewUpdateAccountSettingFact(
+				[]byte("token"), depositor, pt.contract, common.NewBig(100000), 1, pt.now+360000, 1, nil,
+				pt.GenesisCurrency))),
+			newProcessor: payment.NewUpdateAccountSettingProcessor(),
+		},
+		"transfer": {
+			op: newOp(payment.NewTransfer(payment.NewTransferFact(
+				[]byte("token"), depositor, pt.contract, receiver, common.NewBig(10), pt.GenesisCurrency))),
+			newProcessor: payment.NewTransferProcessor(),
+		},
+		"withdraw": {
+			op: newOp(payment.NewWithdraw(payment.NewWithdrawFact(
+				[]byte("token"), depositor, pt.contract, pt.GenesisCurrency))),
+			newProcessor: payment.NewWithdrawProcessor(),
+		},
+	} {
+		reasons, err := pt.process(c.newProcessor, c.op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reasons[0] == nil || !strings.Contains(reasons[0].Error(), "is locked") {
+			t.Errorf("%s: not rejected by lock: %v", name, reasons[0])
+		}
+	}
+
+	setting := pt.design().AccountSetting(depositor.String())
+
+	switch {
+	case !setting.IsLocked():
+		t.Error("unlocked")
+	case !setting.TransferLimit(pt.GenesisCurrency.String()).Equal(common.NewBig(1000)):
+		t.Errorf("transfer limit changed, %v", setting.TransferLimit(pt.GenesisCurrency.String()))
+	case !pt.deposit(depositor).Equal(common.NewBig(1000)):
+		t.Errorf("deposit changed, %v", pt.deposit(depositor))
+	}
+}
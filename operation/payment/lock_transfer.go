@@ -0,0 +1,196 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	LockTransferFactHint = hint.MustNewHint("mitum-payment-lock-transfer-operation-fact-v0.0.1")
+	LockTransferHint     = hint.MustNewHint("mitum-payment-lock-transfer-operation-v0.0.1")
+)
+
+type LockTransferFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	receiver base.Address
+	amount   common.Big
+	hashLock string
+	timeout  uint64
+	currency ctypes.CurrencyID
+}
+
+func NewLockTransferFact(
+	token []byte,
+	sender, contract, receiver base.Address,
+	amount common.Big,
+	hashLock string,
+	timeout uint64,
+	currency ctypes.CurrencyID,
+) LockTransferFact {
+	bf := base.NewBaseFact(LockTransferFactHint, token)
+	fact := LockTransferFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		receiver: receiver,
+		amount:   amount,
+		hashLock: hashLock,
+		timeout:  timeout,
+		currency: currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact LockTransferFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact LockTransferFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact LockTransferFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.receiver.Bytes(),
+		fact.amount.Bytes(),
+		[]byte(fact.hashLock),
+		util.Uint64ToBytes(fact.timeout),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact LockTransferFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	} else if fact.sender.Equal(fact.receiver) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with receiver", fact.sender)))
+	}
+
+	if !fact.amount.OverZero() {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValOOR.Wrap(errors.Errorf("lock amount should be over zero")))
+	} else if fact.timeout == 0 {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("timeout cannot be zero"))
+	}
+
+	if err := types.IsValidHashLock(fact.hashLock); err != nil {
+		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Wrap(err))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.receiver,
+		fact.amount,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact LockTransferFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact LockTransferFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact LockTransferFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact LockTransferFact) Receiver() base.Address {
+	return fact.receiver
+}
+
+func (fact LockTransferFact) Amount() common.Big {
+	return fact.amount
+}
+
+func (fact LockTransferFact) HashLock() string {
+	return fact.hashLock
+}
+
+func (fact LockTransferFact) Timeout() uint64 {
+	return fact.timeout
+}
+
+func (fact LockTransferFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact LockTransferFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact LockTransferFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender, fact.receiver}, nil
+}
+
+func (fact LockTransferFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact LockTransferFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact LockTransferFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact LockTransferFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact LockTransferFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	r[extras.DuplicationKeyTypeContractWithdraw] = []string{
+		fmt.Sprintf("%s:%s", fact.contract.String(), fact.hashLock),
+	}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type LockTransfer struct {
+	extras.ExtendedOperation
+}
+
+func (op LockTransfer) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	return contractWithdrawOperationDupKey(op)
+}
+
+func NewLockTransfer(fact LockTransferFact) (LockTransfer, error) {
+	return LockTransfer{
+		ExtendedOperation: extras.NewExtendedOperation(LockTransferHint, fact),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type LockTransferFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	Sender   base.Address      `json:"sender"`
+	Contract base.Address      `json:"contract"`
+	Receiver base.Address      `json:"receiver"`
+	Amount   common.Big        `json:"amount"`
+	HashLock string            `json:"hash_lock"`
+	Timeout  uint64            `json:"timeout"`
+	Currency ctypes.CurrencyID `json:"currency"`
+}
+
+func (fact LockTransferFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(LockTransferFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Sender:                fact.sender,
+		Contract:              fact.contract,
+		Receiver:              fact.receiver,
+		Amount:                fact.amount,
+		HashLock:              fact.hashLock,
+		Timeout:               fact.timeout,
+		Currency:              fact.currency,
+	})
+}
+
+type LockTransferFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	Sender   string     `json:"sender"`
+	Contract string     `json:"contract"`
+	Receiver string     `json:"receiver"`
+	Amount   common.Big `json:"amount"`
+	HashLock string     `json:"hash_lock"`
+	Timeout  uint64     `json:"timeout"`
+	Currency string     `json:"currency"`
+}
+
+func (fact *LockTransferFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var u LockTransferFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+
+	if err := fact.unpack(
+		enc, u.Sender, u.Contract, u.Receiver, u.Amount, u.HashLock, u.Timeout, u.Currency,
+	); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op LockTransfer) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *LockTransfer) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var lockTransferProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(LockTransferProcessor)
+	},
+}
+
+func (LockTransfer) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type LockTransferProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewLockTransferProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new LockTransferProcessor")
+
+		nopp := lockTransferProcessorPool.Get()
+		opp, ok := nopp.(*LockTransferProcessor)
+		if !ok {
+			return nil, e.Errorf("expected LockTransferProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *LockTransferProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(LockTransferFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", LockTransferFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	cid := fact.Currency()
+	st, err := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("service design value for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	setting := design.AccountSetting(fact.Sender().String())
+	if setting == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("setting of account, %v not found in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	} else if tLimit := setting.TransferLimit(cid.String()); tLimit == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("setting for currency, %v of account, %v not found in contract account %v",
+				cid, fact.Sender(), fact.Contract(),
+			)), nil
+	} else if tLimit.Compare(fact.Amount()) < 0 {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf(
+				"lock amount(%v) exceeds the limit(%v) of account, %v in contract account %v.",
+				fact.Amount(), *tLimit, fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	if setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	_, err = cstate.ExistsState(currency.BalanceStateKey(fact.Contract(), cid),
+		fmt.Sprintf("balance of account, %v", fact.Contract()), getStateFunc,
+	)
+	if err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.Wrap(common.ErrMStateNF).
+				Errorf("%v", err)), nil
+	}
+
+	st, err = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		"account record", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("record of account, %v in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("record of account, %v not found in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+	amount := record.Amount(cid.String())
+	if amount == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit for currency, %v of account, %v not found in contract account %v",
+				cid, fact.Sender(), fact.Contract(),
+			)), nil
+	} else if amount.Compare(fact.Amount()) < 0 {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("lock amount(%v) exceeds the deposit(%v) of account %v in contract account %v",
+				fact.Amount(), amount, fact.Sender(), fact.Contract(),
+			)), nil
+	} else if lastTime := record.TransferredAt(cid.String()); lastTime == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf(
+				"last transferred time of account %v not found in contract account %v.",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	if found, _ := cstate.CheckNotExistsState(
+		state.LockStateKey(fact.Contract().String(), fact.HashLock()), getStateFunc); found {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateE).Errorf("lock for hash lock, %v already exists in contract account %v",
+				fact.HashLock(), fact.Contract(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *LockTransferProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(LockTransferFact)
+
+	cid := fact.Currency()
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+	var pTime *[3]uint64
+
+	if fact.Timeout() <= nowTime {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"timeout, %v is not later than current time, %v", fact.Timeout(), nowTime,
+		), nil
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+
+	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	design, _ := state.GetDesignFromState(st)
+	setting := design.AccountSetting(fact.Sender().String())
+	pTime = setting.PeriodTime(cid.String())
+
+	if err := checkCategoryLimit(
+		fact.Contract(), *setting, cid.String(), fact.Receiver(), fact.Amount(), getStateFunc); err != nil {
+		return nil, err, nil
+	}
+
+	if pTime[0] > nowTime {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"current time, %v is earlier than start time, %v for account, %v in contract account %v.",
+			nowTime, pTime[0], fact.Sender(), fact.Contract(),
+		), nil
+	} else if pTime[1] < nowTime {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"current time, %v is beyond the end time, %v for account, %v in contract account %v.",
+			nowTime, pTime[1], fact.Sender(), fact.Contract(),
+		), nil
+	}
+
+	st, _ = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		"account record", getStateFunc)
+	record, _ := state.GetDepositRecordFromState(st)
+	if lastTime := record.TransferredAt(cid.String()); (*lastTime + pTime[2]) > nowTime {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"last transfer time, %v is too recent. Wait for the required cool time, %v seconds for account, %v in contract account %v.",
+			*lastTime, pTime[2], fact.Sender(), fact.Contract(),
+		), nil
+	}
+
+	if err := checkChannelReserved(fact.Contract(), fact.Sender(), cid.String(),
+		*record.Amount(cid.String()), fact.Amount(), nowTime, getStateFunc); err != nil {
+		return nil, err, nil
+	}
+
+	sts = append(sts, state.NewDepositRecordStateMergeValue(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		state.NewDeductDepositRecordStateValue(fact.Sender(), cid.String(), fact.Amount(), &nowTime),
+	))
+
+	lock := types.NewLock(
+		fact.Sender(), fact.Receiver(), cid, fact.Amount(), fact.HashLock(), fact.Timeout(), "", types.LockStatusLocked,
+	)
+	if err := lock.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid lock of account, %v in contract account %v: %w", fact.Sender(), fact.Contract(), err), nil
+	}
+
+	sts = append(sts, cstate.NewStateMergeValue(
+		state.LockStateKey(fact.Contract().String(), fact.HashLock()),
+		state.NewLockStateValue(lock),
+	))
+
+	sts = append(sts, common.NewBaseStateMergeValue(
+		state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()),
+		state.NewAppendEventLogStateValue(fact.Sender(), types.NewEvent(
+			types.EventTypeLockTransfer, cid.String(), fact.Amount(), fact.Receiver().String(), fact.Hash(), nowTime, opp.Height(),
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()), st,
+			)
+		},
+	))
+
+	return sts, nil, nil
+}
+
+func (opp *LockTransferProcessor) Close() error {
+	opp.proposal = nil
+	lockTransferProcessorPool.Put(opp)
+
+	return nil
+}
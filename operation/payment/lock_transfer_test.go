@@ -0,0 +1,209 @@
+package payment_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func (pt *paymentTest) lock(hashLock string) *types.Lock {
+	st, found, _ := pt.GetStateFunc(state.LockStateKey(pt.contract.String(), hashLock))
+	if !found {
+		return nil
+	}
+
+	lock, err := state.GetLockFromState(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	return lock
+}
+
+func TestLockTransfer(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, receiver := pt.account("sender"), pt.account("receiver")
+
+	pt.setDesign(pt.setting(sender, 1000))
+	pt.setDeposit(sender, 1000, 1)
+
+	h := sha256.Sum256([]byte("preimage"))
+	hashLock := hex.EncodeToString(h[:])
+
+	newOp := func(amount int64, timeout uint64) base.Operation {
+		op, err := payment.NewLockTransfer(payment.NewLockTransferFact(
+			[]byte("token"), sender, pt.contract, receiver, common.NewBig(amount), hashLock, timeout, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return op
+	}
+
+	for name, op := range map[string]base.Operation{
+		"over limit":      newOp(1001, pt.now+3600),
+		"timeout reached": newOp(100, pt.now),
+	} {
+		reasons, err := pt.process(payment.NewLockTransferProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reasons[0] == nil {
+			t.Errorf("%s: lock transfer not rejected", name)
+		}
+	}
+
+	reasons, err := pt.process(payment.NewLockTransferProcessor(), newOp(100, pt.now+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	switch lock := pt.lock(hashLock); {
+	case lock == nil:
+		t.Fatal("lock not stored")
+	case lock.Status() != types.LockStatusLocked, !lock.Amount().Equal(common.NewBig(100)):
+		t.Errorf("lock %v, %v", lock.Status(), lock.Amount())
+	}
+
+	if am := pt.deposit(sender); !am.Equal(common.NewBig(900)) {
+		t.Errorf("deposit %v", am)
+	}
+
+	if i := pt.balance(pt.contract); !i.Equal(common.NewBig(1000)) {
+		t.Errorf("contract balance %v", i)
+	}
+
+	// NOTE the same hash lock can not be locked again.
+	pt.setDeposit(sender, 900, 1)
+
+	reasons, err = pt.process(payment.NewLockTransferProcessor(), newOp(100, pt.now+3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] == nil {
+		t.Error("lock of same hash lock not rejected")
+	}
+}
+
+func TestClaimTransfer(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, receiver := pt.account("sender"), pt.account("receiver")
+
+	pt.setDesign(pt.setting(sender, 1000))
+
+	claim := func(by base.Address, hashLock, preimage string) base.OperationProcessReasonError {
+		op, err := payment.NewClaimTransfer(payment.NewClaimTransferFact(
+			[]byte("token"), by, pt.contract, hashLock, preimage, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewClaimTransferProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return reasons[0]
+	}
+
+	hashLock, preimage := pt.setLock(sender, receiver, 100, pt.now+1)
+
+	if claim(receiver, hashLock, hex.EncodeToString([]byte("other"))) == nil {
+		t.Error("claim with wrong preimage not rejected")
+	}
+
+	if claim(sender, hashLock, preimage) == nil {
+		t.Error("claim by sender not rejected")
+	}
+
+	if reason := claim(receiver, hashLock, preimage); reason != nil {
+		t.Fatal(reason)
+	}
+
+	switch lock := pt.lock(hashLock); {
+	case lock.Status() != types.LockStatusClaimed:
+		t.Errorf("lock %v", lock.Status())
+	case lock.Preimage() != preimage:
+		t.Errorf("preimage %q not revealed", lock.Preimage())
+	}
+
+	if i := pt.balance(receiver); !i.Equal(common.NewBig(100)) {
+		t.Errorf("receiver balance %v", i)
+	}
+
+	if claim(receiver, hashLock, preimage) == nil {
+		t.Error("claimed lock claimed again")
+	}
+
+	// NOTE the lock can not be claimed at the timeout.
+	hashLock, preimage = pt.setLock(sender, receiver, 200, pt.now)
+
+	if claim(receiver, hashLock, preimage) == nil {
+		t.Error("claim at timeout not rejected")
+	}
+}
+
+func TestRefundTransfer(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, receiver := pt.account("sender"), pt.account("receiver")
+
+	pt.setDesign(pt.setting(sender, 1000))
+
+	refund := func(by base.Address, hashLock string) base.OperationProcessReasonError {
+		op, err := payment.NewRefundTransfer(payment.NewRefundTransferFact(
+			[]byte("token"), by, pt.contract, hashLock, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewRefundTransferProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return reasons[0]
+	}
+
+	hashLock, _ := pt.setLock(sender, receiver, 100, pt.now+1)
+
+	if refund(sender, hashLock) == nil {
+		t.Error("refund before timeout not rejected")
+	}
+
+	// NOTE the lock can be refunded at the timeout.
+	hashLock, _ = pt.setLock(sender, receiver, 200, pt.now)
+
+	if refund(receiver, hashLock) == nil {
+		t.Error("refund by receiver not rejected")
+	}
+
+	senderBalance := pt.balance(sender)
+
+	if reason := refund(sender, hashLock); reason != nil {
+		t.Fatal(reason)
+	}
+
+	if lock := pt.lock(hashLock); lock.Status() != types.LockStatusRefunded {
+		t.Errorf("lock %v", lock.Status())
+	}
+
+	if i := pt.balance(sender); !i.Equal(senderBalance.Add(common.NewBig(200))) {
+		t.Errorf("sender balance %v", i)
+	}
+
+	if refund(sender, hashLock) == nil {
+		t.Error("refunded lock refunded again")
+	}
+}
@@ -0,0 +1,96 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func newLockUpTest(t *testing.T) (*paymentTest, base.Address, base.Address, types.LockUp) {
+	pt := newPaymentTest(t)
+	sender, receiver := pt.account("sender"), pt.account("penalty")
+
+	lockUp := types.NewLockUp(pt.now+3600, 1000, receiver)
+
+	setting := pt.setting(sender, 1000)
+	setting.SetLockUp(pt.GenesisCurrency.String(), &lockUp)
+
+	pt.setDesign(setting)
+	pt.setDeposit(sender, 1000, 1)
+	pt.NewTestBalanceState(sender, pt.GenesisCurrency, 1000, true)
+
+	return pt, sender, receiver, lockUp
+}
+
+func TestDepositLockUpPenaltyReceiver(t *testing.T) {
+	for name, c := range map[string]struct {
+		receiver func(sender, receiver base.Address) base.Address
+		rate     uint64
+		accepted bool
+	}{
+		"same receiver": {
+			receiver: func(_, receiver base.Address) base.Address { return receiver },
+			rate:     1000, accepted: true,
+		},
+		"sender as receiver": {
+			receiver: func(sender, _ base.Address) base.Address { return sender },
+			rate:     1000,
+		},
+		"cleared receiver": {
+			receiver: func(base.Address, base.Address) base.Address { return nil },
+		},
+	} {
+		pt, sender, receiver, prev := newLockUpTest(t)
+
+		lockUp := types.NewLockUp(prev.MaturityTime, c.rate, c.receiver(sender, receiver))
+
+		op, err := payment.NewDeposit(payment.NewDepositFact(
+			[]byte("token"), sender, pt.contract, common.NewBig(100), common.NewBig(1000),
+			1, pt.now+3600, 1, &lockUp, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewDepositProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if accepted := reasons[0] == nil; accepted != c.accepted {
+			t.Errorf("%s: accepted %v, not %v: %v", name, accepted, c.accepted, reasons[0])
+		}
+	}
+}
+
+func TestWithdrawLockUpPenalty(t *testing.T) {
+	pt, sender, receiver, _ := newLockUpTest(t)
+
+	op, err := payment.NewWithdraw(payment.NewWithdrawFact([]byte("token"), sender, pt.contract, pt.GenesisCurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err := pt.process(payment.NewWithdrawProcessor(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	if i := pt.balance(receiver); !i.Equal(common.NewBig(100)) {
+		t.Errorf("penalty %v", i)
+	}
+
+	if i := pt.balance(sender); !i.Equal(common.NewBig(1000 + 900)) {
+		t.Errorf("sender balance %v", i)
+	}
+
+	if i := pt.balance(pt.contract); !i.Equal(common.ZeroBig) {
+		t.Errorf("contract balance %v", i)
+	}
+}
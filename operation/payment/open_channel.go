@@ -0,0 +1,189 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	OpenChannelFactHint = hint.MustNewHint("mitum-payment-open-channel-operation-fact-v0.0.1")
+	OpenChannelHint     = hint.MustNewHint("mitum-payment-open-channel-operation-v0.0.1")
+)
+
+type OpenChannelFact struct {
+	base.BaseFact
+	sender        base.Address
+	contract      base.Address
+	receiver      base.Address
+	capacity      common.Big
+	disputeWindow uint64
+	currency      ctypes.CurrencyID
+}
+
+func NewOpenChannelFact(
+	token []byte,
+	sender, contract, receiver base.Address,
+	capacity common.Big,
+	disputeWindow uint64,
+	currency ctypes.CurrencyID,
+) OpenChannelFact {
+	bf := base.NewBaseFact(OpenChannelFactHint, token)
+	fact := OpenChannelFact{
+		BaseFact:      bf,
+		sender:        sender,
+		contract:      contract,
+		receiver:      receiver,
+		capacity:      capacity,
+		disputeWindow: disputeWindow,
+		currency:      currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact OpenChannelFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact OpenChannelFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact OpenChannelFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.receiver.Bytes(),
+		fact.capacity.Bytes(),
+		util.Uint64ToBytes(fact.disputeWindow),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact OpenChannelFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	} else if fact.sender.Equal(fact.receiver) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with receiver", fact.sender)))
+	} else if fact.contract.Equal(fact.receiver) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("receiver %v is same with contract account", fact.receiver)))
+	}
+
+	if !fact.capacity.OverZero() {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("capacity must be over zero"))
+	} else if fact.disputeWindow == 0 {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("dispute window cannot be zero"))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.receiver,
+		fact.capacity,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact OpenChannelFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact OpenChannelFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact OpenChannelFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact OpenChannelFact) Receiver() base.Address {
+	return fact.receiver
+}
+
+func (fact OpenChannelFact) Capacity() common.Big {
+	return fact.capacity
+}
+
+func (fact OpenChannelFact) DisputeWindow() uint64 {
+	return fact.disputeWindow
+}
+
+func (fact OpenChannelFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact OpenChannelFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact OpenChannelFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender, fact.receiver}, nil
+}
+
+func (fact OpenChannelFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact OpenChannelFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact OpenChannelFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact OpenChannelFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact OpenChannelFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[extras.DuplicationKeyTypeSender] = []string{fmt.Sprintf("%s:%s", fact.sender.String(), fact.currency.String())}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type OpenChannel struct {
+	extras.ExtendedOperation
+}
+
+func (op OpenChannel) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewOpenChannel(fact OpenChannelFact) (OpenChannel, error) {
+	return OpenChannel{
+		ExtendedOperation: extras.NewExtendedOperation(OpenChannelHint, fact),
+	}, nil
+}
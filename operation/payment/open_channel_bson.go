@@ -0,0 +1,96 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (fact OpenChannelFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":          fact.Hint().String(),
+			"hash":           fact.BaseFact.Hash().String(),
+			"token":          fact.BaseFact.Token(),
+			"sender":         fact.sender,
+			"contract":       fact.contract,
+			"receiver":       fact.receiver,
+			"capacity":       fact.capacity,
+			"dispute_window": fact.disputeWindow,
+			"currency":       fact.currency,
+		},
+	)
+}
+
+type OpenChannelFactBSONUnmarshaler struct {
+	Hint          string     `bson:"_hint"`
+	Sender        string     `bson:"sender"`
+	Contract      string     `bson:"contract"`
+	Receiver      string     `bson:"receiver"`
+	Capacity      common.Big `bson:"capacity"`
+	DisputeWindow uint64     `bson:"dispute_window"`
+	Currency      string     `bson:"currency"`
+}
+
+func (fact *OpenChannelFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var u common.BaseFactBSONUnmarshaler
+
+	err := enc.Unmarshal(b, &u)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	fact.BaseFact.SetHash(valuehash.NewBytesFromString(u.Hash))
+	fact.BaseFact.SetToken(u.Token)
+
+	var uf OpenChannelFactBSONUnmarshaler
+	if err := bson.Unmarshal(b, &uf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	ht, err := hint.ParseHint(uf.Hint)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+	fact.BaseHinter = hint.NewBaseHinter(ht)
+	fact.capacity = uf.Capacity
+
+	if err := fact.unpack(
+		enc, uf.Sender, uf.Contract, uf.Receiver, uf.DisputeWindow, uf.Currency,
+	); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	return nil
+}
+
+func (op OpenChannel) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint": op.Hint().String(),
+			"hash":  op.Hash().String(),
+			"fact":  op.Fact(),
+			"signs": op.Signs(),
+		})
+}
+
+func (op *OpenChannel) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+func (fact *OpenChannelFact) unpack(
+	enc encoder.Encoder,
+	sa, ca, ra string,
+	dw uint64,
+	cid string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	switch receiver, err := base.DecodeAddress(ra, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.receiver = receiver
+	}
+
+	fact.disputeWindow = dw
+	fact.currency = ctypes.CurrencyID(cid)
+
+	return nil
+}
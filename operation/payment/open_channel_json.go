@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type OpenChannelFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	Sender        base.Address      `json:"sender"`
+	Contract      base.Address      `json:"contract"`
+	Receiver      base.Address      `json:"receiver"`
+	Capacity      common.Big        `json:"capacity"`
+	DisputeWindow uint64            `json:"dispute_window"`
+	Currency      ctypes.CurrencyID `json:"currency"`
+}
+
+func (fact OpenChannelFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OpenChannelFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Sender:                fact.sender,
+		Contract:              fact.contract,
+		Receiver:              fact.receiver,
+		Capacity:              fact.capacity,
+		DisputeWindow:         fact.disputeWindow,
+		Currency:              fact.currency,
+	})
+}
+
+type OpenChannelFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	Sender        string     `json:"sender"`
+	Contract      string     `json:"contract"`
+	Receiver      string     `json:"receiver"`
+	Capacity      common.Big `json:"capacity"`
+	DisputeWindow uint64     `json:"dispute_window"`
+	Currency      string     `json:"currency"`
+}
+
+func (fact *OpenChannelFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var u OpenChannelFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+	fact.capacity = u.Capacity
+
+	if err := fact.unpack(
+		enc, u.Sender, u.Contract, u.Receiver, u.DisputeWindow, u.Currency,
+	); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op OpenChannel) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *OpenChannel) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
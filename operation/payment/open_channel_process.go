@@ -0,0 +1,224 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var openChannelProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(OpenChannelProcessor)
+	},
+}
+
+func (OpenChannel) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type OpenChannelProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewOpenChannelProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new OpenChannelProcessor")
+
+		nopp := openChannelProcessorPool.Get()
+		opp, ok := nopp.(*OpenChannelProcessor)
+		if !ok {
+			return nil, e.Errorf("expected OpenChannelProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *OpenChannelProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(OpenChannelFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", OpenChannelFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	cid := fact.Currency()
+	st, err := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("service design value for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	setting := design.AccountSetting(fact.Sender().String())
+	if setting == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("setting of account, %v not found in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	} else if tLimit := setting.TransferLimit(cid.String()); tLimit == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("setting for currency, %v of account, %v not found in contract account %v",
+				cid, fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	if setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	st, err = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		"account record", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("record of account, %v in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("record of account, %v not found in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	if amount := record.Amount(cid.String()); amount == nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit for currency, %v of account, %v not found in contract account %v",
+				cid, fact.Sender(), fact.Contract(),
+			)), nil
+	} else if amount.Compare(fact.Capacity()) < 0 {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValOOR).Errorf("capacity(%v) exceeds the deposit(%v) of account %v in contract account %v",
+				fact.Capacity(), amount, fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *OpenChannelProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(OpenChannelFact)
+
+	cid := fact.Currency()
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	design, _ := state.GetDesignFromState(st)
+	setting := design.AccountSetting(fact.Sender().String())
+	if err := checkCategoryLimit(
+		fact.Contract(), *setting, cid.String(), fact.Receiver(), fact.Capacity(), getStateFunc); err != nil {
+		return nil, err, nil
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+	smv, err := cstate.CreateNotExistAccount(fact.Receiver(), getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError("%w", err), nil
+	} else if smv != nil {
+		sts = append(sts, smv)
+	}
+
+	var nonce uint64 = 1
+	k := state.ChannelStateKey(fact.Contract().String(), fact.Sender().String(), cid.String())
+	switch st, found, err := getStateFunc(k); {
+	case err != nil:
+		return nil, base.NewBaseOperationProcessReasonError(
+			"failed to get channel of account, %v for currency, %v in contract account %v: %w",
+			fact.Sender(), cid, fact.Contract(), err), nil
+	case found:
+		channel, err := state.GetChannelFromState(st)
+		if err != nil {
+			return nil, base.NewBaseOperationProcessReasonError(
+				"invalid channel of account, %v for currency, %v in contract account %v: %w",
+				fact.Sender(), cid, fact.Contract(), err), nil
+		} else if !channel.IsClosed(nowTime) {
+			return nil, base.NewBaseOperationProcessReasonError(
+				"channel of account, %v for currency, %v is not closed in contract account %v",
+				fact.Sender(), cid, fact.Contract()), nil
+		}
+
+		nonce = channel.Nonce() + 1
+	}
+
+	channel := types.NewChannel(
+		fact.Sender(), fact.Receiver(), cid, fact.Capacity(), common.ZeroBig, nonce, fact.DisputeWindow(), 0,
+	)
+	if err := channel.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid channel of account, %v in contract account %v: %w", fact.Sender(), fact.Contract(), err), nil
+	}
+
+	sts = append(sts, cstate.NewStateMergeValue(k, state.NewChannelStateValue(channel)))
+
+	return sts, nil, nil
+}
+
+func (opp *OpenChannelProcessor) Close() error {
+	opp.proposal = nil
+	openChannelProcessorPool.Put(opp)
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package payment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	operationtest "github.com/imfact-labs/currency-model/operation/test"
+	"github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var testHeight = base.Height(2)
+
+// paymentTest is the states of a contract account with the payment design.
+type paymentTest struct {
+	operationtest.TestProcessor
+	t        *testing.T
+	contract base.Address
+	proposal base.ProposalSignFact
+	now      uint64
+}
+
+func newPaymentTest(t *testing.T, settings ...types.Setting) *paymentTest {
+	t.Helper()
+
+	pt := &paymentTest{t: t}
+	pt.Setup(operationtest.NewMockStateGetter())
+
+	pt.contract, _ = pt.NewTestContractAccountState(pt.GenesisAddr, pt.NewPrivateKey("contract"), true)
+	pt.proposal = isaac.NewProposalSignFact(isaac.NewProposalFact(base.NewPoint(testHeight, 0), pt.NodeAddr, nil, nil))
+	pt.now = uint64(pt.proposal.ProposalFact().ProposedAt().Unix())
+
+	pt.setDesign(settings...)
+
+	return pt
+}
+
+func (pt *paymentTest) account(seed string) base.Address {
+	addr, _, _ := pt.NewTestAccountState(pt.NewPrivateKey(seed), true)
+
+	return addr
+}
+
+func (pt *paymentTest) setState(key string, value base.StateValue) {
+	pt.SetState(common.NewBaseState(base.Height(1), key, value, nil, []util.Hash{}), true)
+}
+
+func (pt *paymentTest) setDesign(settings ...types.Setting) {
+	design := types.NewDesign()
+	for i := range settings {
+		if err := design.AddAccountSetting(settings[i]); err != nil {
+			pt.t.Fatal(err)
+		}
+	}
+
+	pt.setState(state.DesignStateKey(pt.contract.String()), state.NewDesignStateValue(design))
+}
+
+// setDeposit sets the deposit of the account, which is also in the balance of
+// the contract account.
+func (pt *paymentTest) setDeposit(account base.Address, amount int64, transferredAt uint64) {
+	record := types.NewDepositRecord(account)
+	record.SetItem(pt.GenesisCurrency.String(), common.NewBig(amount), transferredAt)
+
+	pt.setState(state.DepositRecordStateKey(pt.contract.String(), account.String()),
+		state.NewDepositRecordStateValue(record))
+	pt.NewTestBalanceState(pt.contract, pt.GenesisCurrency, amount, true)
+}
+
+// setting returns the setting of the account which allows the transfer of
+// limit now.
+func (pt *paymentTest) setting(account base.Address, limit int64) types.Setting {
+	s := types.NewSettings(account)
+	s.SetItem(pt.GenesisCurrency.String(), common.NewBig(limit), 1, pt.now+3600, 1)
+
+	return s
+}
+
+// process processes the operations in a block; the operations are processed
+// with the states before the block and the merge values of the same key are
+// merged by one merger. It returns the reasons of the operations.
+func (pt *paymentTest) process(
+	newProcessor ctypes.GetNewProcessorWithProposal, ops ...base.Operation,
+) ([]base.OperationProcessReasonError, error) {
+	pt.t.Helper()
+
+	reasons := make([]base.OperationProcessReasonError, len(ops))
+
+	var keys []string
+	mergers := map[string]base.StateValueMerger{}
+
+	for i := range ops {
+		opp, err := newProcessor(testHeight, &pt.proposal, pt.GetStateFunc, nil, nil)
+		if err != nil {
+			pt.t.Fatal(err)
+		}
+
+		ctx, reason, err := opp.PreProcess(context.Background(), ops[i], pt.GetStateFunc)
+		if err != nil {
+			pt.t.Fatal(err)
+		}
+
+		if reason == nil {
+			var stmvs []base.StateMergeValue
+
+			stmvs, reason, err = opp.Process(ctx, ops[i], pt.GetStateFunc)
+			if err != nil {
+				pt.t.Fatal(err)
+			}
+
+			for j := range stmvs {
+				k := stmvs[j].Key()
+
+				m, found := mergers[k]
+				if !found {
+					st, _, _ := pt.GetStateFunc(k)
+					m = stmvs[j].Merger(testHeight, st)
+					mergers[k] = m
+					keys = append(keys, k)
+				}
+
+				if err := m.Merge(stmvs[j].Value(), ops[i].Fact().Hash()); err != nil {
+					pt.t.Fatal(err)
+				}
+			}
+		}
+
+		reasons[i] = reason
+
+		if c, ok := opp.(interface{ Close() error }); ok {
+			_ = c.Close()
+		}
+	}
+
+	for _, k := range keys {
+		st, err := mergers[k].CloseValue()
+		if err != nil {
+			return reasons, err
+		}
+
+		pt.SetState(st, true)
+	}
+
+	return reasons, nil
+}
+
+func (pt *paymentTest) balance(addr base.Address) common.Big {
+	st, found, _ := pt.GetStateFunc(currency.BalanceStateKey(addr, pt.GenesisCurrency))
+	if !found {
+		return common.ZeroBig
+	}
+
+	am, err := currency.StateBalanceValue(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	return am.Big()
+}
+
+func (pt *paymentTest) design() types.Design {
+	st, _, _ := pt.GetStateFunc(state.DesignStateKey(pt.contract.String()))
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	return design
+}
+
+func (pt *paymentTest) deposit(account base.Address) common.Big {
+	st, found, _ := pt.GetStateFunc(state.DepositRecordStateKey(pt.contract.String(), account.String()))
+	if !found {
+		return common.ZeroBig
+	}
+
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	if am := record.Amount(pt.GenesisCurrency.String()); am != nil {
+		return *am
+	}
+
+	return common.ZeroBig
+}
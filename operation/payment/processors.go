@@ -0,0 +1,49 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+type ProcessorInfoA struct {
+	Hint      hint.Hint
+	Processor ctypes.GetNewProcessor
+}
+
+type ProcessorInfoB struct {
+	Hint      hint.Hint
+	Processor ctypes.GetNewProcessorWithProposal
+}
+
+// ProcessorsA returns the processors of the operations which do not need the
+// proposal.
+func ProcessorsA() []ProcessorInfoA {
+	return []ProcessorInfoA{
+		{RegisterModelHint, NewRegisterModelProcessor()},
+		{UpdateMerchantHint, NewUpdateMerchantProcessor()},
+	}
+}
+
+// ProcessorsB returns the processors of the operations which need the
+// proposal.
+func ProcessorsB(networkID base.NetworkID) []ProcessorInfoB {
+	return []ProcessorInfoB{
+		{DepositHint, NewDepositProcessor()},
+		{DepositItemsHint, NewDepositItemsProcessor()},
+		{UpdateAccountSettingHint, NewUpdateAccountSettingProcessor()},
+		{WithdrawHint, NewWithdrawProcessor()},
+		{WithdrawAllHint, NewWithdrawAllProcessor()},
+		{TransferHint, NewTransferProcessor()},
+		{SplitTransferHint, NewSplitTransferProcessor()},
+		{OpenChannelHint, NewOpenChannelProcessor()},
+		{CloseChannelHint, NewCloseChannelProcessor()},
+		{ClaimVoucherHint, NewClaimVoucherProcessor(networkID)},
+		{LockTransferHint, NewLockTransferProcessor()},
+		{ClaimTransferHint, NewClaimTransferProcessor()},
+		{RefundTransferHint, NewRefundTransferProcessor()},
+		{RegisterDepositKeysHint, NewRegisterDepositKeysProcessor(networkID)},
+		{LockDepositHint, NewLockDepositProcessor(networkID)},
+		{UnlockDepositHint, NewUnlockDepositProcessor(networkID)},
+	}
+}
@@ -0,0 +1,159 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	RefundTransferFactHint = hint.MustNewHint("mitum-payment-refund-transfer-operation-fact-v0.0.1")
+	RefundTransferHint     = hint.MustNewHint("mitum-payment-refund-transfer-operation-v0.0.1")
+)
+
+type RefundTransferFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	hashLock string
+	currency ctypes.CurrencyID
+}
+
+func NewRefundTransferFact(
+	token []byte,
+	sender, contract base.Address,
+	hashLock string,
+	currency ctypes.CurrencyID,
+) RefundTransferFact {
+	bf := base.NewBaseFact(RefundTransferFactHint, token)
+	fact := RefundTransferFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		hashLock: hashLock,
+		currency: currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact RefundTransferFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact RefundTransferFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact RefundTransferFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		[]byte(fact.hashLock),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact RefundTransferFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := types.IsValidHashLock(fact.hashLock); err != nil {
+		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Wrap(err))
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact RefundTransferFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact RefundTransferFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact RefundTransferFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact RefundTransferFact) HashLock() string {
+	return fact.hashLock
+}
+
+func (fact RefundTransferFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact RefundTransferFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact RefundTransferFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender}, nil
+}
+
+func (fact RefundTransferFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact RefundTransferFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact RefundTransferFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact RefundTransferFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact RefundTransferFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	r[extras.DuplicationKeyTypeContractWithdraw] = []string{
+		fmt.Sprintf("%s:%s", fact.contract.String(), fact.hashLock)}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type RefundTransfer struct {
+	extras.ExtendedOperation
+}
+
+func (op RefundTransfer) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	return contractWithdrawOperationDupKey(op)
+}
+
+func NewRefundTransfer(fact RefundTransferFact) (RefundTransfer, error) {
+	return RefundTransfer{
+		ExtendedOperation: extras.NewExtendedOperation(RefundTransferHint, fact),
+	}, nil
+}
@@ -0,0 +1,136 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var refundTransferProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(RefundTransferProcessor)
+	},
+}
+
+func (RefundTransfer) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type RefundTransferProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewRefundTransferProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new RefundTransferProcessor")
+
+		nopp := refundTransferProcessorPool.Get()
+		opp, ok := nopp.(*RefundTransferProcessor)
+		if !ok {
+			return nil, e.Errorf("expected RefundTransferProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *RefundTransferProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(RefundTransferFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", RefundTransferFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	lock, reason := existsLockedLock(fact.Contract(), fact.HashLock(), getStateFunc)
+	if reason != nil {
+		return ctx, reason, nil
+	}
+
+	if !lock.Sender().Equal(fact.Sender()) {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMAccountNAth).Errorf("sender %v is not sender of lock, %v",
+				fact.Sender(), lock.Sender(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *RefundTransferProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(RefundTransferFact)
+
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	k := state.LockStateKey(fact.Contract().String(), fact.HashLock())
+	st, _ := cstate.ExistsState(k, "lock", getStateFunc)
+	lock, _ := state.GetLockFromState(st)
+	if !lock.IsExpired(nowTime) {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"lock for hash lock, %v in contract account %v does not expire until %v",
+			fact.HashLock(), fact.Contract(), lock.Timeout(),
+		), nil
+	}
+
+	nLock := types.NewLock(
+		lock.Sender(), lock.Receiver(), lock.Currency(), lock.Amount(),
+		lock.HashLock(), lock.Timeout(), "", types.LockStatusRefunded,
+	)
+	if err := nLock.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid lock for hash lock, %v in contract account %v: %w", fact.HashLock(), fact.Contract(), err), nil
+	}
+
+	sts := []base.StateMergeValue{cstate.NewStateMergeValue(k, state.NewLockStateValue(nLock))}
+	sts = append(sts, releaseLockStateMergeValues(
+		fact.Contract(), *lock, lock.Sender(), types.EventTypeRefundTransfer, fact.Hash(), nowTime, opp.Height(),
+	)...)
+
+	return sts, nil, nil
+}
+
+func (opp *RefundTransferProcessor) Close() error {
+	opp.proposal = nil
+	refundTransferProcessorPool.Put(opp)
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	RegisterDepositKeysFactHint = hint.MustNewHint("mitum-payment-register-deposit-keys-operation-fact-v0.0.1")
+	RegisterDepositKeysHint     = hint.MustNewHint("mitum-payment-register-deposit-keys-operation-v0.0.1")
+)
+
+// RegisterDepositKeysFact registers the lock key and the recovery key of the
+// sender's deposit. Replacing keys already registered needs the sign of the
+// current recovery key.
+type RegisterDepositKeysFact struct {
+	base.BaseFact
+	sender      base.Address
+	contract    base.Address
+	lockKey     base.Publickey
+	recoveryKey base.Publickey
+	sign        base.Sign
+	currency    ctypes.CurrencyID
+}
+
+func NewRegisterDepositKeysFact(
+	token []byte, sender, contract base.Address, lockKey, recoveryKey base.Publickey, currency ctypes.CurrencyID,
+) RegisterDepositKeysFact {
+	bf := base.NewBaseFact(RegisterDepositKeysFactHint, token)
+	fact := RegisterDepositKeysFact{
+		BaseFact:    bf,
+		sender:      sender,
+		contract:    contract,
+		lockKey:     lockKey,
+		recoveryKey: recoveryKey,
+		currency:    currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact RegisterDepositKeysFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact RegisterDepositKeysFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+// SignedBytes returns the bytes signed by the recovery key.
+func (fact RegisterDepositKeysFact) SignedBytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.lockKey.Bytes(),
+		fact.recoveryKey.Bytes(),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact RegisterDepositKeysFact) Bytes() []byte {
+	var sign []byte
+	if fact.sign != nil {
+		sign = fact.sign.Bytes()
+	}
+
+	return util.ConcatBytesSlice(
+		fact.SignedBytes(),
+		sign,
+	)
+}
+
+func (fact RegisterDepositKeysFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.lockKey,
+		fact.recoveryKey,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if fact.lockKey.Equal(fact.recoveryKey) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrDupVal.Errorf("lock key is same with recovery key"))
+	}
+
+	if fact.sign != nil {
+		if err := fact.sign.IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact RegisterDepositKeysFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact RegisterDepositKeysFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact RegisterDepositKeysFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact RegisterDepositKeysFact) LockKey() base.Publickey {
+	return fact.lockKey
+}
+
+func (fact RegisterDepositKeysFact) RecoveryKey() base.Publickey {
+	return fact.recoveryKey
+}
+
+// KeySign returns the sign of the current recovery key; it is nil when the
+// keys are registered for the first time.
+func (fact RegisterDepositKeysFact) KeySign() base.Sign {
+	return fact.sign
+}
+
+// SignWithKey signs the fact with the current recovery key.
+func (fact *RegisterDepositKeysFact) SignWithKey(priv base.Privatekey, networkID base.NetworkID) error {
+	sign, err := base.NewBaseSignFromBytes(priv, networkID, fact.SignedBytes())
+	if err != nil {
+		return err
+	}
+
+	fact.sign = sign
+	fact.SetHash(fact.GenerateHash())
+
+	return nil
+}
+
+func (fact RegisterDepositKeysFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact RegisterDepositKeysFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact RegisterDepositKeysFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender}, nil
+}
+
+func (fact RegisterDepositKeysFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact RegisterDepositKeysFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact RegisterDepositKeysFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact RegisterDepositKeysFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact RegisterDepositKeysFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type RegisterDepositKeys struct {
+	extras.ExtendedOperation
+}
+
+func (op RegisterDepositKeys) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewRegisterDepositKeys(fact RegisterDepositKeysFact) (RegisterDepositKeys, error) {
+	return RegisterDepositKeys{
+		ExtendedOperation: extras.NewExtendedOperation(RegisterDepositKeysHint, fact),
+	}, nil
+}
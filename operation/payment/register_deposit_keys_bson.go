@@ -0,0 +1,130 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (fact RegisterDepositKeysFact) MarshalBSON() ([]byte, error) {
+	m := bson.M{
+		"_hint":        fact.Hint().String(),
+		"hash":         fact.BaseFact.Hash().String(),
+		"token":        fact.BaseFact.Token(),
+		"sender":       fact.sender,
+		"contract":     fact.contract,
+		"lock_key":     fact.lockKey.String(),
+		"recovery_key": fact.recoveryKey.String(),
+		"currency":     fact.currency,
+	}
+
+	if fact.sign != nil {
+		m["sign"] = keySignBSON(fact.sign)
+	}
+
+	return bsonenc.Marshal(m)
+}
+
+type RegisterDepositKeysFactBSONUnmarshaler struct {
+	Hint        string   `bson:"_hint"`
+	Sender      string   `bson:"sender"`
+	Contract    string   `bson:"contract"`
+	LockKey     string   `bson:"lock_key"`
+	RecoveryKey string   `bson:"recovery_key"`
+	Sign        bson.Raw `bson:"sign,omitempty"`
+	Currency    string   `bson:"currency"`
+}
+
+func (fact *RegisterDepositKeysFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var u common.BaseFactBSONUnmarshaler
+
+	err := enc.Unmarshal(b, &u)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	fact.BaseFact.SetHash(valuehash.NewBytesFromString(u.Hash))
+	fact.BaseFact.SetToken(u.Token)
+
+	var uf RegisterDepositKeysFactBSONUnmarshaler
+	if err := bson.Unmarshal(b, &uf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	ht, err := hint.ParseHint(uf.Hint)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+	fact.BaseHinter = hint.NewBaseHinter(ht)
+
+	sign, err := decodeKeySignBSON(uf.Sign, enc)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	if err := fact.unpack(enc, uf.Sender, uf.Contract, uf.LockKey, uf.RecoveryKey, sign, uf.Currency); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	return nil
+}
+
+func (op RegisterDepositKeys) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint": op.Hint().String(),
+			"hash":  op.Hash().String(),
+			"fact":  op.Fact(),
+			"signs": op.Signs(),
+		})
+}
+
+func (op *RegisterDepositKeys) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
+
+func keySignBSON(sign base.Sign) bson.M {
+	return bson.M{
+		"signer":    sign.Signer().String(),
+		"signature": sign.Signature().String(),
+		"signed_at": sign.SignedAt(),
+	}
+}
+
+// decodeKeySignBSON decodes the sign of a deposit key; empty input gives nil
+// sign.
+func decodeKeySignBSON(b bson.Raw, enc *bsonenc.Encoder) (base.Sign, error) {
+	if len(b) < 1 {
+		return nil, nil
+	}
+
+	var us common.BaseSignBSONUnmarshaler
+	if err := enc.Unmarshal(b, &us); err != nil {
+		return nil, err
+	}
+
+	pub, err := base.DecodePublickeyFromString(us.Signer, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return base.NewBaseSign(pub, us.Signature, us.SignedAt), nil
+}
@@ -0,0 +1,47 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+func (fact *RegisterDepositKeysFact) unpack(
+	enc encoder.Encoder,
+	sa, ca, lk, rk string,
+	sign base.Sign,
+	cid string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	switch pub, err := base.DecodePublickeyFromString(lk, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.lockKey = pub
+	}
+
+	switch pub, err := base.DecodePublickeyFromString(rk, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.recoveryKey = pub
+	}
+
+	fact.sign = sign
+	fact.currency = ctypes.CurrencyID(cid)
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package payment
+
+import (
+	"encoding/json"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type RegisterDepositKeysFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	Sender      base.Address      `json:"sender"`
+	Contract    base.Address      `json:"contract"`
+	LockKey     base.Publickey    `json:"lock_key"`
+	RecoveryKey base.Publickey    `json:"recovery_key"`
+	Sign        base.Sign         `json:"sign,omitempty"`
+	Currency    ctypes.CurrencyID `json:"currency"`
+}
+
+func (fact RegisterDepositKeysFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(RegisterDepositKeysFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Sender:                fact.sender,
+		Contract:              fact.contract,
+		LockKey:               fact.lockKey,
+		RecoveryKey:           fact.recoveryKey,
+		Sign:                  fact.sign,
+		Currency:              fact.currency,
+	})
+}
+
+type RegisterDepositKeysFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	Sender      string          `json:"sender"`
+	Contract    string          `json:"contract"`
+	LockKey     string          `json:"lock_key"`
+	RecoveryKey string          `json:"recovery_key"`
+	Sign        json.RawMessage `json:"sign"`
+	Currency    string          `json:"currency"`
+}
+
+func (fact *RegisterDepositKeysFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var u RegisterDepositKeysFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+
+	sign, err := decodeKeySignJSON(u.Sign, enc)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	if err := fact.unpack(enc, u.Sender, u.Contract, u.LockKey, u.RecoveryKey, sign, u.Currency); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op RegisterDepositKeys) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *RegisterDepositKeys) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
+
+// decodeKeySignJSON decodes the sign of a deposit key; empty or null input
+// gives nil sign.
+func decodeKeySignJSON(b json.RawMessage, enc encoder.Encoder) (base.Sign, error) {
+	if len(b) < 1 || string(b) == "null" {
+		return nil, nil
+	}
+
+	var ub base.BaseSign
+	if err := ub.DecodeJSON(b, enc); err != nil {
+		return nil, err
+	}
+
+	return ub, nil
+}
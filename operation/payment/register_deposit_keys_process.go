@@ -0,0 +1,243 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var registerDepositKeysProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(RegisterDepositKeysProcessor)
+	},
+}
+
+func (RegisterDepositKeys) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type RegisterDepositKeysProcessor struct {
+	*base.BaseOperationProcessor
+	proposal  *base.ProposalSignFact
+	networkID base.NetworkID
+}
+
+func NewRegisterDepositKeysProcessor(networkID base.NetworkID) ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new RegisterDepositKeysProcessor")
+
+		nopp := registerDepositKeysProcessorPool.Get()
+		opp, ok := nopp.(*RegisterDepositKeysProcessor)
+		if !ok {
+			return nil, e.Errorf("expected RegisterDepositKeysProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+		opp.networkID = networkID
+
+		return opp, nil
+	}
+}
+
+func (opp *RegisterDepositKeysProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(RegisterDepositKeysFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", RegisterDepositKeysFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	_, setting, rerr := depositSettingFromState(fact.Contract(), fact.Sender(), getStateFunc)
+	if rerr != nil {
+		return nil, rerr, nil
+	}
+
+	if setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	if setting.RecoveryKey() != nil {
+		if err := checkDepositKeySign(
+			opp.networkID, fact.KeySign(), setting.RecoveryKey(), fact.SignedBytes()); err != nil {
+			return nil, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.
+					Wrap(common.ErrMSignInvalid).Errorf("recovery key of account, %v in contract account %v: %v",
+					fact.Sender(), fact.Contract(), err,
+				)), nil
+		}
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *RegisterDepositKeysProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(RegisterDepositKeysFact)
+
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	design, _ := state.GetDesignFromState(st)
+	setting := design.AccountSetting(fact.Sender().String())
+
+	nSetting := copyAccountSetting(*setting)
+	nSetting.SetKeys(fact.LockKey(), fact.RecoveryKey())
+
+	smv, err := accountSettingStateMergeValue(fact.Contract(), *setting, nSetting)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"failed to update setting of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+	sts = append(sts, smv)
+
+	sts = append(sts, depositKeyEventStateMergeValue(
+		fact.Contract(), fact.Sender(), fact.Sender(), types.EventTypeRegisterDepositKeys,
+		fact.Currency(), fact.Hash(), nowTime, opp.Height(),
+	))
+
+	return sts, nil, nil
+}
+
+func (opp *RegisterDepositKeysProcessor) Close() error {
+	opp.proposal = nil
+	opp.networkID = nil
+	registerDepositKeysProcessorPool.Put(opp)
+
+	return nil
+}
+
+// depositSettingFromState returns the service design and the setting of
+// depositor in the contract account.
+func depositSettingFromState(
+	contract, depositor base.Address, getStateFunc base.GetStateFunc,
+) (*types.Design, *types.Setting, base.OperationProcessReasonError) {
+	st, err := cstate.ExistsState(state.DesignStateKey(contract.String()), "service design", getStateFunc)
+	if err != nil {
+		return nil, nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				contract,
+			))
+	}
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return nil, nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("service design value for contract account %v",
+				contract,
+			))
+	}
+
+	setting := design.AccountSetting(depositor.String())
+	if setting == nil {
+		return nil, nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("setting of account, %v not found in contract account %v",
+				depositor, contract,
+			))
+	}
+
+	return &design, setting, nil
+}
+
+func checkDepositKeySign(networkID base.NetworkID, sign base.Sign, key base.Publickey, b []byte) error {
+	switch {
+	case sign == nil:
+		return errors.Errorf("empty sign")
+	case !sign.Signer().Equal(key):
+		return errors.Errorf("signer, %v is not registered key", sign.Signer())
+	}
+
+	return sign.Verify(networkID, b)
+}
+
+func copyAccountSetting(setting types.Setting) types.Setting {
+	nSetting := types.NewSettings(setting.Address())
+	for k, v := range setting.Items() {
+		nSetting.SetItem(k, v.TransferLimit, v.StartTime, v.EndTime, v.Duration)
+		nSetting.SetCategoryLimits(k, v.CategoryLimits)
+		nSetting.SetLockUp(k, v.LockUp)
+	}
+	nSetting.SetKeys(setting.LockKey(), setting.RecoveryKey())
+	nSetting.SetLocked(setting.IsLocked())
+
+	return nSetting
+}
+
+// accountSettingStateMergeValue changes the setting of the account in the
+// service design from prev.
+func accountSettingStateMergeValue(
+	contract base.Address, prev, setting types.Setting,
+) (base.StateMergeValue, error) {
+	if err := setting.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return state.NewDesignStateMergeValue(
+		state.DesignStateKey(contract.String()),
+		state.NewSetDesignAccountStateValue(&prev, setting),
+	), nil
+}
+
+func depositKeyEventStateMergeValue(
+	contract, depositor, counterparty base.Address,
+	eventType string,
+	cid ctypes.CurrencyID,
+	factHash util.Hash,
+	nowTime uint64,
+	height base.Height,
+) base.StateMergeValue {
+	return common.NewBaseStateMergeValue(
+		state.EventLogStateKey(contract.String(), depositor.String()),
+		state.NewAppendEventLogStateValue(depositor, types.NewEvent(
+			eventType, cid.String(), common.ZeroBig, counterparty.String(), factHash, nowTime, height,
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(contract.String(), depositor.String()), st,
+			)
+		},
+	)
+}
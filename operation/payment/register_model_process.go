@@ -93,7 +93,7 @@ func (opp *RegisterModelProcessor) Process(
 		return nil, base.NewBaseOperationProcessReasonError("invalid timestamp design, %q; %w", fact.Contract(), err), nil
 	}
 
-	sts = append(sts, cstate.NewStateMergeValue(
+	sts = append(sts, state.NewDesignStateMergeValue(
 		state.DesignStateKey(fact.Contract().String()),
 		state.NewDesignStateValue(design),
 	))
@@ -0,0 +1,218 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+// SimulateResult is the outcome of the dry-run of a payment operation; either
+// the states which would be written or the reason of the rejection.
+type SimulateResult struct {
+	height     base.Height
+	proposedAt time.Time
+	states     []base.State
+	reason     base.OperationProcessReasonError
+}
+
+func (r SimulateResult) Height() base.Height {
+	return r.height
+}
+
+func (r SimulateResult) ProposedAt() time.Time {
+	return r.proposedAt
+}
+
+func (r SimulateResult) States() []base.State {
+	return r.states
+}
+
+func (r SimulateResult) Reason() base.OperationProcessReasonError {
+	return r.reason
+}
+
+func (r SimulateResult) Accepted() bool {
+	return r.reason == nil
+}
+
+type simulateProposalFact struct {
+	isaac.ProposalFact
+	proposedAt time.Time
+}
+
+func (fact simulateProposalFact) ProposedAt() time.Time {
+	return fact.proposedAt
+}
+
+type simulateProposalSignFact struct {
+	isaac.ProposalSignFact
+	fact simulateProposalFact
+}
+
+func (sf simulateProposalSignFact) ProposalFact() base.ProposalFact {
+	return sf.fact
+}
+
+// Simulate runs PreProcess and Process of the payment operation against the
+// states of getStateFunc as if it is included in the block of height,
+// proposed at proposedAt. The operation may not be signed; the signs are
+// checked only when it has. The fee and the authentication of the sender
+// account are handled by the currency operation processor, so they are not
+// simulated.
+func Simulate(
+	ctx context.Context,
+	op base.Operation,
+	networkID base.NetworkID,
+	height base.Height,
+	proposedAt time.Time,
+	getStateFunc base.GetStateFunc,
+) (SimulateResult, error) {
+	e := util.StringError("simulate operation")
+
+	result := SimulateResult{height: height, proposedAt: proposedAt.UTC()}
+
+	if op == nil || op.Fact() == nil {
+		return result, e.Errorf("empty operation")
+	}
+
+	if len(op.Signs()) > 0 {
+		if err := op.IsValid(networkID); err != nil {
+			result.reason = base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.Errorf("%v", err))
+
+			return result, nil
+		}
+	} else if err := op.Fact().IsValid(nil); err != nil {
+		result.reason = base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.Errorf("%v", err))
+
+		return result, nil
+	}
+
+	opp, err := newSimulateProcessor(op, networkID, height, proposedAt, getStateFunc)
+	if err != nil {
+		return result, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = opp.Close()
+	}()
+
+	ctx, reason, err := opp.PreProcess(ctx, op, getStateFunc)
+	switch {
+	case err != nil:
+		return result, e.Wrap(err)
+	case reason != nil:
+		result.reason = reason
+
+		return result, nil
+	}
+
+	smvs, reason, err := opp.Process(ctx, op, getStateFunc)
+	switch {
+	case err != nil:
+		return result, e.Wrap(err)
+	case reason != nil:
+		result.reason = reason
+
+		return result, nil
+	}
+
+	states, err := mergeSimulatedStates(height, op.Fact().Hash(), smvs, getStateFunc)
+	if err != nil {
+		return result, e.Wrap(err)
+	}
+
+	result.states = states
+
+	return result, nil
+}
+
+func newSimulateProcessor(
+	op base.Operation,
+	networkID base.NetworkID,
+	height base.Height,
+	proposedAt time.Time,
+	getStateFunc base.GetStateFunc,
+) (base.OperationProcessor, error) {
+	ht := op.Hint()
+
+	for _, p := range ProcessorsA() {
+		if p.Hint.Equal(ht) {
+			return p.Processor(height, getStateFunc, nil, nil)
+		}
+	}
+
+	for _, p := range ProcessorsB(networkID) {
+		if !p.Hint.Equal(ht) {
+			continue
+		}
+
+		fact := isaac.NewProposalFact(base.NewPoint(height, base.Round(0)), nil, nil, nil)
+		var proposal base.ProposalSignFact = simulateProposalSignFact{
+			ProposalSignFact: isaac.NewProposalSignFact(fact),
+			fact:             simulateProposalFact{ProposalFact: fact, proposedAt: proposedAt},
+		}
+
+		return p.Processor(height, &proposal, getStateFunc, nil, nil)
+	}
+
+	return nil, errors.Errorf("not payment operation, %q", ht)
+}
+
+func mergeSimulatedStates(
+	height base.Height,
+	factHash util.Hash,
+	smvs []base.StateMergeValue,
+	getStateFunc base.GetStateFunc,
+) ([]base.State, error) {
+	var keys []string
+	mergers := map[string]base.StateValueMerger{}
+
+	defer func() {
+		for _, m := range mergers {
+			_ = m.Close()
+		}
+	}()
+
+	for i := range smvs {
+		smv := smvs[i]
+
+		merger, found := mergers[smv.Key()]
+		if !found {
+			st, _, err := getStateFunc(smv.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			merger = smv.Merger(height, st)
+			mergers[smv.Key()] = merger
+			keys = append(keys, smv.Key())
+		}
+
+		if err := merger.Merge(smv.Value(), factHash); err != nil {
+			return nil, err
+		}
+	}
+
+	states := make([]base.State, 0, len(keys))
+
+	for i := range keys {
+		st, err := mergers[keys[i]].CloseValue()
+		switch {
+		case errors.Is(err, base.ErrIgnoreStateValue):
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		states = append(states, st)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/localtime"
+)
+
+type SimulateResultJSONMarshaler struct {
+	Height     base.Height                      `json:"height"`
+	ProposedAt localtime.Time                   `json:"proposed_at"`
+	Accepted   bool                             `json:"accepted"`
+	States     []base.State                     `json:"states,omitempty"`
+	Reason     base.OperationProcessReasonError `json:"reason,omitempty"`
+}
+
+func (r SimulateResult) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(SimulateResultJSONMarshaler{
+		Height:     r.height,
+		ProposedAt: localtime.New(r.proposedAt),
+		Accepted:   r.Accepted(),
+		States:     r.states,
+		Reason:     r.reason,
+	})
+}
@@ -0,0 +1,84 @@
+package payment_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/state/currency"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+)
+
+func TestSimulate(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, receiver := pt.account("sender"), pt.account("receiver")
+
+	pt.setDesign(pt.setting(sender, 100))
+	pt.setDeposit(sender, 1000, 1)
+
+	networkID := base.NetworkID("test")
+	now := time.Unix(int64(pt.now), 0)
+
+	simulate := func(amount int64, proposedAt time.Time) payment.SimulateResult {
+		op, err := payment.NewTransfer(payment.NewTransferFact(
+			[]byte("token"), sender, pt.contract, receiver, common.NewBig(amount), pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := payment.Simulate(context.Background(), op, networkID, testHeight, proposedAt, pt.GetStateFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return result
+	}
+
+	result := simulate(100, now)
+	if !result.Accepted() {
+		t.Fatal(result.Reason())
+	}
+
+	keys := map[string]base.State{}
+	for _, st := range result.States() {
+		keys[st.Key()] = st
+	}
+
+	st, found := keys[state.DepositRecordStateKey(pt.contract.String(), sender.String())]
+	if !found {
+		t.Fatal("deposit record not simulated")
+	}
+
+	record, err := state.GetDepositRecordFromState(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if am := record.Amount(pt.GenesisCurrency.String()); !am.Equal(common.NewBig(900)) {
+		t.Errorf("simulated deposit %v", am)
+	}
+
+	if _, found := keys[currency.BalanceStateKey(receiver, pt.GenesisCurrency)]; !found {
+		t.Error("balance of receiver not simulated")
+	}
+
+	// NOTE the simulation does not change the states.
+	if am := pt.deposit(sender); !am.Equal(common.NewBig(1000)) {
+		t.Errorf("deposit changed, %v", am)
+	}
+
+	for name, c := range map[string]struct {
+		amount     int64
+		proposedAt time.Time
+	}{
+		"over limit":    {101, now},
+		"after setting": {100, now.Add(time.Hour * 2)},
+	} {
+		if r := simulate(c.amount, c.proposedAt); r.Accepted() || len(r.States()) > 0 {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
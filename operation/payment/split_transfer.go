@@ -0,0 +1,278 @@
+package payment
+
+import (
+	"math/big"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	SplitTransferFactHint = hint.MustNewHint("mitum-payment-split-transfer-operation-fact-v0.0.1")
+	SplitTransferHint     = hint.MustNewHint("mitum-payment-split-transfer-operation-v0.0.1")
+)
+
+var MaxSplitReceivers = 10
+
+// SplitShareBasis is the sum of shares in basis points.
+const SplitShareBasis uint64 = 10000
+
+type SplitReceiver struct {
+	Receiver base.Address `json:"receiver"`
+	Share    uint64       `json:"share"`
+}
+
+func NewSplitReceiver(receiver base.Address, share uint64) SplitReceiver {
+	return SplitReceiver{
+		Receiver: receiver,
+		Share:    share,
+	}
+}
+
+func (r SplitReceiver) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		r.Receiver.Bytes(),
+		util.Uint64ToBytes(r.Share),
+	)
+}
+
+func (r SplitReceiver) IsValid([]byte) error {
+	if r.Share == 0 {
+		return common.ErrValueInvalid.Errorf("share of receiver, %v cannot be zero", r.Receiver)
+	}
+
+	if r.Share > SplitShareBasis {
+		return common.ErrValueInvalid.Errorf(
+			"share of receiver, %v over %d, %d", r.Receiver, SplitShareBasis, r.Share)
+	}
+
+	return util.CheckIsValiders(nil, false, r.Receiver)
+}
+
+type SplitTransferFact struct {
+	base.BaseFact
+	sender    base.Address
+	contract  base.Address
+	amount    common.Big
+	receivers []SplitReceiver
+	currency  ctypes.CurrencyID
+}
+
+func NewSplitTransferFact(
+	token []byte,
+	sender, contract base.Address,
+	amount common.Big,
+	receivers []SplitReceiver,
+	currency ctypes.CurrencyID,
+) SplitTransferFact {
+	bf := base.NewBaseFact(SplitTransferFactHint, token)
+	fact := SplitTransferFact{
+		BaseFact:  bf,
+		sender:    sender,
+		contract:  contract,
+		amount:    amount,
+		receivers: receivers,
+		currency:  currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact SplitTransferFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact SplitTransferFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact SplitTransferFact) Bytes() []byte {
+	bs := make([][]byte, len(fact.receivers))
+	for i := range fact.receivers {
+		bs[i] = fact.receivers[i].Bytes()
+	}
+
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.amount.Bytes(),
+		util.ConcatBytesSlice(bs...),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact SplitTransferFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if !fact.amount.OverZero() {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValOOR.Wrap(errors.Errorf("transfer amount should be over zero")))
+	}
+
+	if n := len(fact.receivers); n < 1 {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrArrayLen.Wrap(errors.Errorf("empty receivers")))
+	} else if n > MaxSplitReceivers {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrArrayLen.Wrap(errors.Errorf("receivers over allowed, %d > %d", n, MaxSplitReceivers)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.amount,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	founds := map[string]struct{}{}
+	var total uint64
+	for i := range fact.receivers {
+		r := fact.receivers[i]
+		if err := r.IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+
+		if r.Receiver.Equal(fact.sender) {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrSelfTarget.Wrap(errors.Errorf("receiver %v is same with sender", r.Receiver)))
+		} else if r.Receiver.Equal(fact.contract) {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrSelfTarget.Wrap(errors.Errorf("receiver %v is same with contract account", r.Receiver)))
+		}
+
+		if _, found := founds[r.Receiver.String()]; found {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrDupVal.Wrap(errors.Errorf("receiver %v", r.Receiver)))
+		}
+		founds[r.Receiver.String()] = struct{}{}
+
+		if total > SplitShareBasis-r.Share {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrValueInvalid.Errorf("sum of shares over %d", SplitShareBasis))
+		}
+
+		total += r.Share
+	}
+
+	if total != SplitShareBasis {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("sum of shares, %d is not %d", total, SplitShareBasis))
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact SplitTransferFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact SplitTransferFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact SplitTransferFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact SplitTransferFact) Amount() common.Big {
+	return fact.amount
+}
+
+func (fact SplitTransferFact) Receivers() []SplitReceiver {
+	return fact.receivers
+}
+
+func (fact SplitTransferFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+// SplitAmounts returns the amount of each receiver in the order of receivers.
+// Every amount is rounded down from the share of total amount and the
+// remainder left by rounding is added to the first receiver.
+func (fact SplitTransferFact) SplitAmounts() []common.Big {
+	amounts := make([]common.Big, len(fact.receivers))
+	basis := common.NewBigFromBigInt(new(big.Int).SetUint64(SplitShareBasis))
+
+	remainder := fact.amount
+	for i := range fact.receivers {
+		share := common.NewBigFromBigInt(new(big.Int).SetUint64(fact.receivers[i].Share))
+		amounts[i] = fact.amount.Mul(share).Div(basis)
+		remainder = remainder.Sub(amounts[i])
+	}
+
+	if len(amounts) > 0 {
+		amounts[0] = amounts[0].Add(remainder)
+	}
+
+	return amounts
+}
+
+func (fact SplitTransferFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact SplitTransferFact) Addresses() ([]base.Address, error) {
+	as := make([]base.Address, len(fact.receivers)+1)
+	as[0] = fact.sender
+	for i := range fact.receivers {
+		as[i+1] = fact.receivers[i].Receiver
+	}
+
+	return as, nil
+}
+
+func (fact SplitTransferFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), len(fact.receivers), len(fact.Bytes()), extras.HasItem
+}
+
+func (fact SplitTransferFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact SplitTransferFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact SplitTransferFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact SplitTransferFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type SplitTransfer struct {
+	extras.ExtendedOperation
+}
+
+func (op SplitTransfer) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	return contractWithdrawOperationDupKey(op)
+}
+
+func NewSplitTransfer(fact SplitTransferFact) (SplitTransfer, error) {
+	return SplitTransfer{
+		ExtendedOperation: extras.NewExtendedOperation(SplitTransferHint, fact),
+	}, nil
+}
@@ -0,0 +1,51 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type SplitReceiverUnmarshaler struct {
+	Receiver string `bson:"receiver" json:"receiver"`
+	Share    uint64 `bson:"share" json:"share"`
+}
+
+func (fact *SplitTransferFact) unpack(
+	enc encoder.Encoder,
+	sa, ca string,
+	amount common.Big,
+	urs []SplitReceiverUnmarshaler,
+	ci string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	receivers := make([]SplitReceiver, len(urs))
+	for i := range urs {
+		receiver, err := base.DecodeAddress(urs[i].Receiver, enc)
+		if err != nil {
+			return err
+		}
+
+		receivers[i] = NewSplitReceiver(receiver, urs[i].Share)
+	}
+
+	fact.amount = amount
+	fact.receivers = receivers
+	fact.currency = ctypes.CurrencyID(ci)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type SplitTransferFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	Sender    base.Address      `json:"sender"`
+	Contract  base.Address      `json:"contract"`
+	Amount    common.Big        `json:"amount"`
+	Receivers []SplitReceiver   `json:"receivers"`
+	Currency  ctypes.CurrencyID `json:"currency"`
+}
+
+func (fact SplitTransferFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(SplitTransferFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Sender:                fact.sender,
+		Contract:              fact.contract,
+		Amount:                fact.amount,
+		Receivers:             fact.receivers,
+		Currency:              fact.currency,
+	})
+}
+
+type SplitTransferFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	Sender    string                     `json:"sender"`
+	Contract  string                     `json:"contract"`
+	Amount    common.Big                 `json:"amount"`
+	Receivers []SplitReceiverUnmarshaler `json:"receivers"`
+	Currency  string                     `json:"currency"`
+}
+
+func (fact *SplitTransferFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var u SplitTransferFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+
+	if err := fact.unpack(enc, u.Sender, u.Contract, u.Amount, u.Receivers, u.Currency); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op SplitTransfer) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *SplitTransfer) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/state/currency"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+)
+
+func TestSplitTransferFactShares(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, a, b := pt.account("sender"), pt.account("a"), pt.account("b")
+
+	for name, shares := range map[string][2]uint64{
+		"overflow":   {1 << 63, 1<<63 + payment.SplitShareBasis},
+		"over basis": {payment.SplitShareBasis + 1, 1},
+		"under":      {1, 1},
+	} {
+		fact := payment.NewSplitTransferFact([]byte("token"), sender, pt.contract, common.NewBig(100),
+			[]payment.SplitReceiver{payment.NewSplitReceiver(a, shares[0]), payment.NewSplitReceiver(b, shares[1])},
+			pt.GenesisCurrency)
+
+		if err := fact.IsValid(nil); err == nil {
+			t.Errorf("%s: shares %v accepted", name, shares)
+		}
+	}
+}
+
+func TestSplitTransferProcess(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, a, b, c := pt.account("sender"), pt.account("a"), pt.account("b"), pt.account("c")
+
+	pt.setDesign(pt.setting(sender, 10000))
+	pt.setDeposit(sender, 5000, 1)
+
+	fact := payment.NewSplitTransferFact([]byte("token"), sender, pt.contract, common.NewBig(1001),
+		[]payment.SplitReceiver{
+			payment.NewSplitReceiver(a, 3333),
+			payment.NewSplitReceiver(b, 3333),
+			payment.NewSplitReceiver(c, 3334),
+		}, pt.GenesisCurrency)
+
+	op, err := payment.NewSplitTransfer(fact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err := pt.process(payment.NewSplitTransferProcessor(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	received := common.ZeroBig
+	for _, r := range []base.Address{a, b, c} {
+		received = received.Add(pt.balance(r))
+	}
+
+	if !received.Equal(fact.Amount()) {
+		t.Errorf("receivers got %v, not %v", received, fact.Amount())
+	}
+
+	if i := pt.balance(pt.contract); !i.Equal(common.NewBig(5000 - 1001)) {
+		t.Errorf("contract balance %v", i)
+	}
+
+	if i := pt.deposit(sender); !i.Equal(common.NewBig(5000 - 1001)) {
+		t.Errorf("deposit %v", i)
+	}
+}
+
+func TestSplitTransferZeroAmount(t *testing.T) {
+	pt := newPaymentTest(t)
+	sender, a := pt.account("sender"), pt.account("a")
+	b, _, _ := pt.NewTestAccountState(pt.NewPrivateKey("b"), false)
+
+	pt.setDesign(pt.setting(sender, 10000))
+	pt.setDeposit(sender, 5000, 1)
+
+	// NOTE b gets nothing from 1.
+	op, err := payment.NewSplitTransfer(payment.NewSplitTransferFact([]byte("token"), sender, pt.contract, common.NewBig(1),
+		[]payment.SplitReceiver{payment.NewSplitReceiver(a, 5000), payment.NewSplitReceiver(b, 5000)},
+		pt.GenesisCurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons, err := pt.process(payment.NewSplitTransferProcessor(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		t.Fatal(reasons[0])
+	}
+
+	if i := pt.balance(a); !i.Equal(common.NewBig(1)) {
+		t.Errorf("balance of a %v", i)
+	}
+
+	if _, found, _ := pt.GetStateFunc(currency.AccountStateKey(b)); found {
+		t.Error("account of unpaid receiver created")
+	}
+}
@@ -119,6 +119,14 @@ func (opp *TransferProcessor) PreProcess(
 			)), nil
 	}
 
+	if setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
 	_, err = cstate.ExistsState(currency.BalanceStateKey(fact.Contract(), cid),
 		fmt.Sprintf("balance of account, %v", fact.Contract()), getStateFunc,
 	)
@@ -195,6 +203,11 @@ func (opp *TransferProcessor) Process( // nolint:dupl
 	setting := design.AccountSetting(fact.Sender().String())
 	pTime = setting.PeriodTime(cid.String())
 
+	if err := checkCategoryLimit(
+		fact.Contract(), *setting, cid.String(), fact.Receiver(), fact.Amount(), getStateFunc); err != nil {
+		return nil, err, nil
+	}
+
 	if pTime[0] > nowTime {
 		return nil, base.NewBaseOperationProcessReasonError(
 			"current time, %v is earlier than start time, %v for account, %v in contract account %v.",
@@ -218,21 +231,14 @@ func (opp *TransferProcessor) Process( // nolint:dupl
 		), nil
 	}
 
-	nAmount := record.Amount(cid.String()).Sub(fact.Amount())
-	nRecord := types.NewDepositRecord(fact.Sender())
-	for k, v := range record.Items() {
-		nRecord.SetItem(k, v.Amount, v.TransferredAt)
+	if err := checkChannelReserved(fact.Contract(), fact.Sender(), cid.String(),
+		*record.Amount(cid.String()), fact.Amount(), nowTime, getStateFunc); err != nil {
+		return nil, err, nil
 	}
-	nRecord.SetItem(cid.String(), nAmount, nowTime)
 
-	if err := nRecord.IsValid(nil); err != nil {
-		return nil, base.NewBaseOperationProcessReasonError(
-			"invalid record of account, %v in contract account %v: %w", fact.Sender(), fact.Contract(), err), nil
-	}
-
-	sts = append(sts, cstate.NewStateMergeValue(
+	sts = append(sts, state.NewDepositRecordStateMergeValue(
 		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
-		state.NewDepositRecordStateValue(nRecord),
+		state.NewDeductDepositRecordStateValue(fact.Sender(), cid.String(), fact.Amount(), &nowTime),
 	))
 
 	am := ctypes.NewAmount(fact.Amount(), cid)
@@ -260,6 +266,18 @@ func (opp *TransferProcessor) Process( // nolint:dupl
 		},
 	))
 
+	sts = append(sts, common.NewBaseStateMergeValue(
+		state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()),
+		state.NewAppendEventLogStateValue(fact.Sender(), types.NewEvent(
+			types.EventTypeTransfer, cid.String(), fact.Amount(), fact.Receiver().String(), fact.Hash(), nowTime, opp.Height(),
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()), st,
+			)
+		},
+	))
+
 	return sts, nil, nil
 }
 
@@ -0,0 +1,196 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	UnlockDepositFactHint = hint.MustNewHint("mitum-payment-unlock-deposit-operation-fact-v0.0.1")
+	UnlockDepositHint     = hint.MustNewHint("mitum-payment-unlock-deposit-operation-v0.0.1")
+)
+
+// UnlockDepositFact unlocks the deposit of depositor. It carries the sign of
+// the recovery key of depositor, so any account, sender, can submit it and pay
+// fee.
+type UnlockDepositFact struct {
+	base.BaseFact
+	sender    base.Address
+	contract  base.Address
+	depositor base.Address
+	sign      base.Sign
+	currency  ctypes.CurrencyID
+}
+
+func NewUnlockDepositFact(
+	token []byte, sender, contract, depositor base.Address, currency ctypes.CurrencyID,
+) UnlockDepositFact {
+	bf := base.NewBaseFact(UnlockDepositFactHint, token)
+	fact := UnlockDepositFact{
+		BaseFact:  bf,
+		sender:    sender,
+		contract:  contract,
+		depositor: depositor,
+		currency:  currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact UnlockDepositFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact UnlockDepositFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+// SignedBytes returns the bytes signed by the recovery key.
+func (fact UnlockDepositFact) SignedBytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.depositor.Bytes(),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact UnlockDepositFact) Bytes() []byte {
+	var sign []byte
+	if fact.sign != nil {
+		sign = fact.sign.Bytes()
+	}
+
+	return util.ConcatBytesSlice(
+		fact.SignedBytes(),
+		sign,
+	)
+}
+
+func (fact UnlockDepositFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	} else if fact.depositor.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("depositor %v is same with contract account", fact.depositor)))
+	}
+
+	if fact.sign == nil {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValueInvalid.Errorf("empty sign of recovery key"))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.depositor,
+		fact.sign,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact UnlockDepositFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact UnlockDepositFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact UnlockDepositFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact UnlockDepositFact) Depositor() base.Address {
+	return fact.depositor
+}
+
+func (fact UnlockDepositFact) KeySign() base.Sign {
+	return fact.sign
+}
+
+// SignWithKey signs the fact with the recovery key of depositor.
+func (fact *UnlockDepositFact) SignWithKey(priv base.Privatekey, networkID base.NetworkID) error {
+	sign, err := base.NewBaseSignFromBytes(priv, networkID, fact.SignedBytes())
+	if err != nil {
+		return err
+	}
+
+	fact.sign = sign
+	fact.SetHash(fact.GenerateHash())
+
+	return nil
+}
+
+func (fact UnlockDepositFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact UnlockDepositFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact UnlockDepositFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender, fact.depositor}, nil
+}
+
+func (fact UnlockDepositFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact UnlockDepositFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact UnlockDepositFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact UnlockDepositFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact UnlockDepositFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.depositor)}
+
+	return r, nil
+}
+
+type UnlockDeposit struct {
+	extras.ExtendedOperation
+}
+
+func (op UnlockDeposit) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewUnlockDeposit(fact UnlockDepositFact) (UnlockDeposit, error) {
+	return UnlockDeposit{
+		ExtendedOperation: extras.NewExtendedOperation(UnlockDepositHint, fact),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+func (fact *UnlockDepositFact) unpack(
+	enc encoder.Encoder,
+	sa, ca, da string,
+	sign base.Sign,
+	cid string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	switch depositor, err := base.DecodeAddress(da, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.depositor = depositor
+	}
+
+	fact.sign = sign
+	fact.currency = ctypes.CurrencyID(cid)
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var unlockDepositProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(UnlockDepositProcessor)
+	},
+}
+
+func (UnlockDeposit) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type UnlockDepositProcessor struct {
+	*base.BaseOperationProcessor
+	proposal  *base.ProposalSignFact
+	networkID base.NetworkID
+}
+
+func NewUnlockDepositProcessor(networkID base.NetworkID) ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new UnlockDepositProcessor")
+
+		nopp := unlockDepositProcessorPool.Get()
+		opp, ok := nopp.(*UnlockDepositProcessor)
+		if !ok {
+			return nil, e.Errorf("expected UnlockDepositProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+		opp.networkID = networkID
+
+		return opp, nil
+	}
+}
+
+func (opp *UnlockDepositProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(UnlockDepositFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", UnlockDepositFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	_, setting, rerr := depositSettingFromState(fact.Contract(), fact.Depositor(), getStateFunc)
+	if rerr != nil {
+		return nil, rerr, nil
+	}
+
+	switch {
+	case setting.RecoveryKey() == nil:
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("recovery key of account, %v not registered in contract account %v",
+				fact.Depositor(), fact.Contract(),
+			)), nil
+	case !setting.IsLocked():
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v not locked in contract account %v",
+				fact.Depositor(), fact.Contract(),
+			)), nil
+	}
+
+	if err := checkDepositKeySign(
+		opp.networkID, fact.KeySign(), setting.RecoveryKey(), fact.SignedBytes()); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMSignInvalid).Errorf("recovery key of account, %v in contract account %v: %v",
+				fact.Depositor(), fact.Contract(), err,
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *UnlockDepositProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(UnlockDepositFact)
+
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	design, _ := state.GetDesignFromState(st)
+	setting := design.AccountSetting(fact.Depositor().String())
+
+	nSetting := copyAccountSetting(*setting)
+	nSetting.SetLocked(false)
+
+	smv, err := accountSettingStateMergeValue(fact.Contract(), *setting, nSetting)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"failed to update setting of account, %v in contract account, %v: %w",
+			fact.Depositor(), fact.Contract(), err), nil
+	}
+
+	var sts []base.StateMergeValue // nolint:prealloc
+	sts = append(sts, smv)
+
+	sts = append(sts, depositKeyEventStateMergeValue(
+		fact.Contract(), fact.Depositor(), fact.Sender(), types.EventTypeUnlockDeposit,
+		fact.Currency(), fact.Hash(), nowTime, opp.Height(),
+	))
+
+	return sts, nil, nil
+}
+
+func (opp *UnlockDepositProcessor) Close() error {
+	opp.proposal = nil
+	opp.networkID = nil
+	unlockDepositProcessorPool.Put(opp)
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	UpdateMerchantFactHint = hint.MustNewHint("mitum-payment-update-merchant-operation-fact-v0.0.1")
+	UpdateMerchantHint     = hint.MustNewHint("mitum-payment-update-merchant-operation-v0.0.1")
+)
+
+// UpdateMerchantFact registers the merchant in the contract account or
+// replaces its categories; empty categories leave the merchant uncategorized.
+type UpdateMerchantFact struct {
+	base.BaseFact
+	sender     base.Address
+	contract   base.Address
+	merchant   base.Address
+	categories []string
+	currency   ctypes.CurrencyID
+}
+
+func NewUpdateMerchantFact(
+	token []byte, sender, contract, merchant base.Address, categories []string, currency ctypes.CurrencyID,
+) UpdateMerchantFact {
+	bf := base.NewBaseFact(UpdateMerchantFactHint, token)
+	fact := UpdateMerchantFact{
+		BaseFact:   bf,
+		sender:     sender,
+		contract:   contract,
+		merchant:   merchant,
+		categories: categories,
+		currency:   currency,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact UpdateMerchantFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact UpdateMerchantFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact UpdateMerchantFact) Bytes() []byte {
+	bs := make([][]byte, len(fact.categories))
+	for i := range fact.categories {
+		bs[i] = []byte(fact.categories[i])
+	}
+
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.merchant.Bytes(),
+		util.ConcatBytesSlice(bs...),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact UpdateMerchantFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	} else if fact.merchant.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("merchant %v is same with contract account", fact.merchant)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.merchant,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := types.NewMerchant(fact.merchant, fact.categories).IsValid(nil); err != nil {
+		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Wrap(err))
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact UpdateMerchantFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact UpdateMerchantFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact UpdateMerchantFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact UpdateMerchantFact) Merchant() base.Address {
+	return fact.merchant
+}
+
+func (fact UpdateMerchantFact) Categories() []string {
+	return fact.categories
+}
+
+func (fact UpdateMerchantFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact UpdateMerchantFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact UpdateMerchantFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender, fact.merchant}, nil
+}
+
+func (fact UpdateMerchantFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact UpdateMerchantFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact UpdateMerchantFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact UpdateMerchantFact) ActiveContractOwnerHandlerOnly() [][2]base.Address {
+	return [][2]base.Address{{fact.contract, fact.sender}}
+}
+
+func (fact UpdateMerchantFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[extras.DuplicationKeyTypeContractStatus] = []string{
+		fmt.Sprintf("%s:%s", fact.contract.String(), fact.merchant.String())}
+
+	return r, nil
+}
+
+type UpdateMerchant struct {
+	extras.ExtendedOperation
+}
+
+func (op UpdateMerchant) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewUpdateMerchant(fact UpdateMerchantFact) (UpdateMerchant, error) {
+	return UpdateMerchant{
+		ExtendedOperation: extras.NewExtendedOperation(UpdateMerchantHint, fact),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+func (fact *UpdateMerchantFact) unpack(
+	enc encoder.Encoder,
+	sa, ca, ma string,
+	categories []string,
+	cid string,
+) error {
+	switch sender, err := base.DecodeAddress(sa, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.sender = sender
+	}
+
+	switch contract, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = contract
+	}
+
+	switch merchant, err := base.DecodeAddress(ma, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.merchant = merchant
+	}
+
+	fact.categories = categories
+	fact.currency = ctypes.CurrencyID(cid)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+)
+
+type UpdateMerchantFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	Sender     base.Address      `json:"sender"`
+	Contract   base.Address      `json:"contract"`
+	Merchant   base.Address      `json:"merchant"`
+	Categories []string          `json:"categories"`
+	Currency   ctypes.CurrencyID `json:"currency"`
+}
+
+func (fact UpdateMerchantFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(UpdateMerchantFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Sender:                fact.sender,
+		Contract:              fact.contract,
+		Merchant:              fact.merchant,
+		Categories:            fact.categories,
+		Currency:              fact.currency,
+	})
+}
+
+type UpdateMerchantFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	Sender     string   `json:"sender"`
+	Contract   string   `json:"contract"`
+	Merchant   string   `json:"merchant"`
+	Categories []string `json:"categories"`
+	Currency   string   `json:"currency"`
+}
+
+func (fact *UpdateMerchantFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var u UpdateMerchantFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+
+	if err := fact.unpack(enc, u.Sender, u.Contract, u.Merchant, u.Categories, u.Currency); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op UpdateMerchant) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *UpdateMerchant) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
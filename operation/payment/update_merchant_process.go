@@ -0,0 +1,171 @@
+package payment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+var updateMerchantProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(UpdateMerchantProcessor)
+	},
+}
+
+func (UpdateMerchant) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type UpdateMerchantProcessor struct {
+	*base.BaseOperationProcessor
+}
+
+func NewUpdateMerchantProcessor() ctypes.GetNewProcessor {
+	return func(
+		height base.Height,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new UpdateMerchantProcessor")
+
+		nopp := updateMerchantProcessorPool.Get()
+		opp, ok := nopp.(*UpdateMerchantProcessor)
+		if !ok {
+			return nil, errors.Errorf("expected UpdateMerchantProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+
+		return opp, nil
+	}
+}
+
+func (opp *UpdateMerchantProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(UpdateMerchantFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", UpdateMerchantFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	if _, err := cstate.ExistsState(
+		state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *UpdateMerchantProcessor) Process(
+	_ context.Context, op base.Operation, _ base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(UpdateMerchantFact)
+
+	merchant := types.NewMerchant(fact.Merchant(), fact.Categories())
+	if err := merchant.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid merchant, %v in contract account %v: %w", fact.Merchant(), fact.Contract(), err), nil
+	}
+
+	return []base.StateMergeValue{
+		cstate.NewStateMergeValue(
+			state.MerchantStateKey(fact.Contract().String(), fact.Merchant().String()),
+			state.NewMerchantStateValue(merchant),
+		),
+	}, nil, nil
+}
+
+func (opp *UpdateMerchantProcessor) Close() error {
+	updateMerchantProcessorPool.Put(opp)
+
+	return nil
+}
+
+// checkCategoryLimit checks the receiver against the category limits of cid in
+// the setting. Without category limits any receiver is allowed; otherwise the
+// receiver must be the merchant of the limited category and the amount must not
+// exceed the strictest limit among the categories of the merchant.
+func checkCategoryLimit(
+	contract base.Address,
+	setting types.Setting,
+	cid string,
+	receiver base.Address,
+	amount common.Big,
+	getStateFunc base.GetStateFunc,
+) base.OperationProcessReasonError {
+	limits := setting.CategoryLimits(cid)
+	if len(limits) < 1 {
+		return nil
+	}
+
+	st, found, err := getStateFunc(state.MerchantStateKey(contract.String(), receiver.String()))
+	switch {
+	case err != nil:
+		return base.NewBaseOperationProcessReasonError("%w", err)
+	case !found || st == nil:
+		return base.NewBaseOperationProcessReasonError(
+			"receiver %v is not merchant in contract account %v", receiver, contract)
+	}
+
+	merchant, err := state.GetMerchantFromState(st)
+	if err != nil {
+		return base.NewBaseOperationProcessReasonError(
+			"invalid merchant, %v in contract account %v: %w", receiver, contract, err)
+	}
+
+	var limit *common.Big
+	for _, c := range merchant.Categories() {
+		l, found := limits[c]
+		if !found {
+			continue
+		}
+
+		if limit == nil || l.Compare(*limit) < 0 {
+			limit = &l
+		}
+	}
+
+	switch {
+	case limit == nil:
+		return base.NewBaseOperationProcessReasonError(
+			"categories, %v of merchant %v are not allowed for account, %v in contract account %v",
+			merchant.Categories(), receiver, setting.Address(), contract)
+	case limit.Compare(amount) < 0:
+		return base.NewBaseOperationProcessReasonError(
+			"transfer amount(%v) exceeds the category limit(%v) of account, %v for merchant %v in contract account %v",
+			amount, *limit, setting.Address(), receiver, contract)
+	}
+
+	return nil
+}
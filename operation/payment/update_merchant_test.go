@@ -0,0 +1,115 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/state"
+)
+
+// withoutProposal processes the operations which do not depend on the
+// proposal by pt.process.
+func withoutProposal(f ctypes.GetNewProcessor) ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		_ *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		return f(height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+	}
+}
+
+func (pt *paymentTest) setMerchant(merchant base.Address, categories ...string) {
+	op, err := payment.NewUpdateMerchant(payment.NewUpdateMerchantFact(
+		[]byte("token"), pt.GenesisAddr, pt.contract, merchant, categories, pt.GenesisCurrency))
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	reasons, err := pt.process(withoutProposal(payment.NewUpdateMerchantProcessor()), op)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+
+	if reasons[0] != nil {
+		pt.t.Fatal(reasons[0])
+	}
+}
+
+func TestUpdateMerchant(t *testing.T) {
+	pt := newPaymentTest(t)
+	merchant := pt.account("merchant")
+
+	pt.setMerchant(merchant, "food", "book")
+
+	st, found, _ := pt.GetStateFunc(state.MerchantStateKey(pt.contract.String(), merchant.String()))
+	if !found {
+		t.Fatal("merchant not stored")
+	}
+
+	m, err := state.GetMerchantFromState(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cs := m.Categories(); len(cs) != 2 {
+		t.Errorf("categories %v", cs)
+	}
+}
+
+func TestTransferCategoryLimits(t *testing.T) {
+	for name, c := range map[string]struct {
+		categories []string
+		amount     int64
+		accepted   bool
+	}{
+		"in limit":           {[]string{"food"}, 10, true},
+		"over limit":         {[]string{"food"}, 11, false},
+		"strictest category": {[]string{"book", "food"}, 10, false},
+		"in strictest":       {[]string{"book", "food"}, 5, true},
+		"other category":     {[]string{"toy"}, 1, false},
+		"not merchant":       {nil, 1, false},
+	} {
+		pt := newPaymentTest(t)
+		depositor, receiver := pt.account("depositor"), pt.account("receiver")
+
+		setting := pt.setting(depositor, 1000)
+		setting.SetCategoryLimits(pt.GenesisCurrency.String(), map[string]common.Big{
+			"food": common.NewBig(10),
+			"book": common.NewBig(5),
+		})
+
+		pt.setDesign(setting)
+		pt.setDeposit(depositor, 1000, 1)
+
+		if len(c.categories) > 0 {
+			pt.setMerchant(receiver, c.categories...)
+		}
+
+		op, err := payment.NewTransfer(payment.NewTransferFact(
+			[]byte("token"), depositor, pt.contract, receiver, common.NewBig(c.amount), pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewTransferProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if (reasons[0] == nil) != c.accepted {
+			t.Errorf("%s: accepted %v, %v", name, !c.accepted, reasons[0])
+
+			continue
+		}
+
+		if i := pt.balance(receiver); c.accepted && !i.Equal(common.NewBig(c.amount)) {
+			t.Errorf("%s: receiver balance %v", name, i)
+		}
+	}
+}
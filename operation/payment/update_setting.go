@@ -1,6 +1,8 @@
 package payment
 
 import (
+	"sort"
+
 	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/currency-model/operation/extras"
 	ctypes "github.com/imfact-labs/currency-model/types"
@@ -8,6 +10,7 @@ import (
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/hint"
 	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
 	"github.com/pkg/errors"
 )
 
@@ -18,28 +21,31 @@ var (
 
 type UpdateAccountSettingFact struct {
 	base.BaseFact
-	sender        base.Address
-	contract      base.Address
-	transferLimit common.Big
-	startTime     uint64
-	endTime       uint64
-	duration      uint64
-	currency      ctypes.CurrencyID
+	sender         base.Address
+	contract       base.Address
+	transferLimit  common.Big
+	startTime      uint64
+	endTime        uint64
+	duration       uint64
+	categoryLimits map[string]common.Big
+	currency       ctypes.CurrencyID
 }
 
 func NewUpdateAccountSettingFact(
 	token []byte, sender, contract base.Address,
-	transferLimit common.Big, starTime, endTime, duration uint64, currency ctypes.CurrencyID) UpdateAccountSettingFact {
+	transferLimit common.Big, starTime, endTime, duration uint64, categoryLimits map[string]common.Big,
+	currency ctypes.CurrencyID) UpdateAccountSettingFact {
 	bf := base.NewBaseFact(UpdateAccountSettingFactHint, token)
 	fact := UpdateAccountSettingFact{
-		BaseFact:      bf,
-		sender:        sender,
-		contract:      contract,
-		transferLimit: transferLimit,
-		startTime:     starTime,
-		endTime:       endTime,
-		duration:      duration,
-		currency:      currency,
+		BaseFact:       bf,
+		sender:         sender,
+		contract:       contract,
+		transferLimit:  transferLimit,
+		startTime:      starTime,
+		endTime:        endTime,
+		duration:       duration,
+		categoryLimits: categoryLimits,
+		currency:       currency,
 	}
 
 	fact.SetHash(fact.GenerateHash())
@@ -73,6 +79,23 @@ func (fact UpdateAccountSettingFact) IsValid(b []byte) error {
 		return common.ErrFactInvalid.Wrap(err)
 	}
 
+	if len(fact.categoryLimits) > types.MaxMerchantCategories {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrArrayLen.Errorf(
+				"category limits over max, %d > %d", len(fact.categoryLimits), types.MaxMerchantCategories))
+	}
+
+	for k, v := range fact.categoryLimits {
+		if !types.IsValidCategory(k) {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrValueInvalid.Errorf("invalid category, %q", k))
+		}
+
+		if err := v.IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+	}
+
 	if err := common.IsValidOperationFact(fact, b); err != nil {
 		return common.ErrFactInvalid.Wrap(err)
 	}
@@ -97,6 +120,7 @@ func (fact UpdateAccountSettingFact) Bytes() []byte {
 		util.Uint64ToBytes(fact.startTime),
 		util.Uint64ToBytes(fact.endTime),
 		util.Uint64ToBytes(fact.duration),
+		categoryLimitsBytes(fact.categoryLimits),
 		fact.currency.Bytes(),
 	)
 }
@@ -129,6 +153,12 @@ func (fact UpdateAccountSettingFact) Duration() uint64 {
 	return fact.duration
 }
 
+// CategoryLimits returns the new category limits of currency; nil keeps the
+// current limits and the empty limits clear them.
+func (fact UpdateAccountSettingFact) CategoryLimits() map[string]common.Big {
+	return fact.categoryLimits
+}
+
 func (fact UpdateAccountSettingFact) Currency() ctypes.CurrencyID {
 	return fact.currency
 }
@@ -159,7 +189,7 @@ func (fact UpdateAccountSettingFact) ActiveContract() []base.Address {
 
 func (fact UpdateAccountSettingFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
 	r := make(map[ctypes.DuplicationKeyType][]string)
-	r[extras.DuplicationKeyTypeContractStatus] = []string{fact.contract.String()}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
 
 	return r, nil
 }
@@ -183,3 +213,27 @@ func NewUpdateAccountSetting(fact UpdateAccountSettingFact) (UpdateAccountSettin
 		ExtendedOperation: extras.NewExtendedOperation(UpdateAccountSettingHint, fact),
 	}, nil
 }
+
+// categoryLimitsBytes returns nil for the omitted limits; the empty limits,
+// which clear the category rule, have their own bytes.
+func categoryLimitsBytes(limits map[string]common.Big) []byte {
+	switch {
+	case limits == nil:
+		return nil
+	case len(limits) < 1:
+		return []byte{0}
+	}
+
+	keys := make([]string, 0, len(limits))
+	for k := range limits {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bs := make([][]byte, len(keys))
+	for i, k := range keys {
+		bs[i] = util.ConcatBytesSlice([]byte(k), limits[k].Bytes())
+	}
+
+	return util.ConcatBytesSlice(bs...)
+}
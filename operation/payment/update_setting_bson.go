@@ -10,31 +10,36 @@ import (
 )
 
 func (fact UpdateAccountSettingFact) MarshalBSON() ([]byte, error) {
-	return bsonenc.Marshal(
-		bson.M{
-			"_hint":          fact.Hint().String(),
-			"hash":           fact.BaseFact.Hash().String(),
-			"token":          fact.BaseFact.Token(),
-			"sender":         fact.sender,
-			"contract":       fact.contract,
-			"transfer_limit": fact.transferLimit,
-			"start_time":     fact.startTime,
-			"end_time":       fact.endTime,
-			"duration":       fact.duration,
-			"currency":       fact.currency,
-		},
-	)
+	m := bson.M{
+		"_hint":          fact.Hint().String(),
+		"hash":           fact.BaseFact.Hash().String(),
+		"token":          fact.BaseFact.Token(),
+		"sender":         fact.sender,
+		"contract":       fact.contract,
+		"transfer_limit": fact.transferLimit,
+		"start_time":     fact.startTime,
+		"end_time":       fact.endTime,
+		"duration":       fact.duration,
+		"currency":       fact.currency,
+	}
+
+	if fact.categoryLimits != nil {
+		m["category_limits"] = fact.categoryLimits
+	}
+
+	return bsonenc.Marshal(m)
 }
 
 type UpdateAccountInfoFactBSONUnmarshaler struct {
-	Hint          string     `bson:"_hint"`
-	Sender        string     `bson:"sender"`
-	Contract      string     `bson:"contract"`
-	TransferLimit common.Big `bson:"transfer_limit"`
-	StartTime     uint64     `bson:"start_time"`
-	EndTime       uint64     `bson:"end_time"`
-	Duration      uint64     `bson:"duration"`
-	Currency      string     `bson:"currency"`
+	Hint           string                `bson:"_hint"`
+	Sender         string                `bson:"sender"`
+	Contract       string                `bson:"contract"`
+	TransferLimit  common.Big            `bson:"transfer_limit"`
+	StartTime      uint64                `bson:"start_time"`
+	EndTime        uint64                `bson:"end_time"`
+	Duration       uint64                `bson:"duration"`
+	CategoryLimits map[string]common.Big `bson:"category_limits,omitempty"`
+	Currency       string                `bson:"currency"`
 }
 
 func (fact *UpdateAccountSettingFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
@@ -59,6 +64,7 @@ func (fact *UpdateAccountSettingFact) DecodeBSON(b []byte, enc *bsonenc.Encoder)
 	}
 	fact.BaseHinter = hint.NewBaseHinter(ht)
 	fact.transferLimit = uf.TransferLimit
+	fact.categoryLimits = uf.CategoryLimits
 
 	if err := fact.unpack(
 		enc, uf.Sender, uf.Contract, uf.StartTime, uf.EndTime, uf.Duration, uf.Currency,
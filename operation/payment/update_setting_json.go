@@ -11,16 +11,22 @@ import (
 
 type UpdateAccountInfoFactJSONMarshaler struct {
 	base.BaseFactJSONMarshaler
-	Sender        base.Address      `json:"sender"`
-	Contract      base.Address      `json:"contract"`
-	TransferLimit common.Big        `json:"transfer_limit"`
-	StartTime     uint64            `json:"start_time"`
-	EndTime       uint64            `json:"end_time"`
-	Duration      uint64            `json:"duration"`
-	Currency      ctypes.CurrencyID `json:"currency"`
+	Sender         base.Address           `json:"sender"`
+	Contract       base.Address           `json:"contract"`
+	TransferLimit  common.Big             `json:"transfer_limit"`
+	StartTime      uint64                 `json:"start_time"`
+	EndTime        uint64                 `json:"end_time"`
+	Duration       uint64                 `json:"duration"`
+	CategoryLimits *map[string]common.Big `json:"category_limits,omitempty"`
+	Currency       ctypes.CurrencyID      `json:"currency"`
 }
 
 func (fact UpdateAccountSettingFact) MarshalJSON() ([]byte, error) {
+	var limits *map[string]common.Big
+	if fact.categoryLimits != nil {
+		limits = &fact.categoryLimits
+	}
+
 	return util.MarshalJSON(UpdateAccountInfoFactJSONMarshaler{
 		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
 		Sender:                fact.sender,
@@ -29,19 +35,21 @@ func (fact UpdateAccountSettingFact) MarshalJSON() ([]byte, error) {
 		StartTime:             fact.startTime,
 		EndTime:               fact.endTime,
 		Duration:              fact.duration,
+		CategoryLimits:        limits,
 		Currency:              fact.currency,
 	})
 }
 
 type UpdateAccountInfoFactJSONUnmarshaler struct {
 	base.BaseFactJSONUnmarshaler
-	Sender        string     `json:"sender"`
-	Contract      string     `json:"contract"`
-	TransferLimit common.Big `json:"transfer_limit"`
-	StartTime     uint64     `json:"start_time"`
-	EndTime       uint64     `json:"end_time"`
-	Duration      uint64     `json:"duration"`
-	Currency      string     `json:"currency"`
+	Sender         string                `json:"sender"`
+	Contract       string                `json:"contract"`
+	TransferLimit  common.Big            `json:"transfer_limit"`
+	StartTime      uint64                `json:"start_time"`
+	EndTime        uint64                `json:"end_time"`
+	Duration       uint64                `json:"duration"`
+	CategoryLimits map[string]common.Big `json:"category_limits"`
+	Currency       string                `json:"currency"`
 }
 
 func (fact *UpdateAccountSettingFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
@@ -52,6 +60,7 @@ func (fact *UpdateAccountSettingFact) DecodeJSON(b []byte, enc encoder.Encoder)
 
 	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
 	fact.transferLimit = u.TransferLimit
+	fact.categoryLimits = u.CategoryLimits
 
 	if err := fact.unpack(
 		enc, u.Sender, u.Contract, u.StartTime, u.EndTime, u.Duration, u.Currency,
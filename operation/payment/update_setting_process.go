@@ -27,11 +27,13 @@ func (UpdateAccountSetting) Process(
 
 type UpdateAccountSettingProcessor struct {
 	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
 }
 
-func NewUpdateAccountSettingProcessor() ctypes.GetNewProcessor {
+func NewUpdateAccountSettingProcessor() ctypes.GetNewProcessorWithProposal {
 	return func(
 		height base.Height,
+		proposal *base.ProposalSignFact,
 		getStateFunc base.GetStateFunc,
 		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
 		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
@@ -51,6 +53,7 @@ func NewUpdateAccountSettingProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
 
 		return opp, nil
 	}
@@ -102,6 +105,14 @@ func (opp *UpdateAccountSettingProcessor) PreProcess(
 			)), nil
 	}
 
+	if setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
 	big := setting.TransferLimit(cid.String())
 	if big == nil {
 		return nil, base.NewBaseOperationProcessReasonError(
@@ -121,38 +132,45 @@ func (opp *UpdateAccountSettingProcessor) Process( // nolint:dupl
 	fact, _ := op.Fact().(UpdateAccountSettingFact)
 
 	cid := fact.Currency()
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
 	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
 	design, _ := state.GetDesignFromState(st)
 	setting := design.AccountSetting(fact.Sender().String())
-	nSetting := types.NewSettings(fact.Sender())
-	for k, v := range setting.Items() {
-		nSetting.SetItem(k, v.TransferLimit, v.StartTime, v.EndTime, v.Duration)
-	}
+	nSetting := copyAccountSetting(*setting)
 	nSetting.SetItem(cid.String(), fact.TransferLimit(), fact.StartTime(), fact.EndTime(), fact.Duration())
-
-	nDesign := types.NewDesign()
-	for _, v := range design.AccountSettings() {
-		nDesign.AddAccountSetting(v)
-	}
-	if err := nDesign.UpdateAccountSetting(nSetting); err != nil {
-		return nil, base.NewBaseOperationProcessReasonError(
-			"failed to update setting of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
+	if limits := fact.CategoryLimits(); limits != nil {
+		nSetting.SetCategoryLimits(cid.String(), limits)
 	}
 
-	if err := nDesign.IsValid(nil); err != nil {
-		return nil, base.NewBaseOperationProcessReasonError("invalid service design, %q; %w", fact.Contract(), err), nil
+	if err := nSetting.IsValid(nil); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			"invalid setting of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
 	}
 
 	var sts []base.StateMergeValue // nolint:prealloc
-	sts = append(sts, cstate.NewStateMergeValue(
+	sts = append(sts, state.NewDesignStateMergeValue(
 		state.DesignStateKey(fact.Contract().String()),
-		state.NewDesignStateValue(nDesign),
+		state.NewSetDesignAccountStateValue(setting, nSetting),
+	))
+
+	sts = append(sts, common.NewBaseStateMergeValue(
+		state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()),
+		state.NewAppendEventLogStateValue(fact.Sender(), types.NewEvent(
+			types.EventTypeUpdateSetting, cid.String(), fact.TransferLimit(), "", fact.Hash(), nowTime, opp.Height(),
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(fact.Contract().String(), fact.Sender().String()), st,
+			)
+		},
 	))
 
 	return sts, nil, nil
 }
 
 func (opp *UpdateAccountSettingProcessor) Close() error {
+	opp.proposal = nil
 	updateAccountSettingProcessorPool.Put(opp)
 
 	return nil
@@ -0,0 +1,141 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/app/runtime/steps"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	jsonenc "github.com/imfact-labs/mitum2/util/encoder/json"
+	"github.com/imfact-labs/payment-model/operation/payment"
+)
+
+func newTestEncoders(t *testing.T) (*jsonenc.Encoder, *bsonenc.Encoder) {
+	t.Helper()
+
+	jenc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(jenc, jenc)
+	benc := bsonenc.NewEncoder()
+
+	if err := encs.AddEncoder(benc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := steps.LoadHinters(encs); err != nil {
+		t.Fatal(err)
+	}
+
+	return jenc, benc
+}
+
+func TestUpdateAccountSettingCategoryLimits(t *testing.T) {
+	for name, c := range map[string]struct {
+		limits map[string]common.Big
+		result map[string]common.Big
+	}{
+		"omitted": {
+			result: map[string]common.Big{"food": common.NewBig(10)},
+		},
+		"cleared": {
+			limits: map[string]common.Big{},
+		},
+		"replaced": {
+			limits: map[string]common.Big{"book": common.NewBig(20)},
+			result: map[string]common.Big{"book": common.NewBig(20)},
+		},
+	} {
+		pt := newPaymentTest(t)
+		depositor := pt.account("depositor")
+
+		setting := pt.setting(depositor, 1000)
+		setting.SetCategoryLimits(pt.GenesisCurrency.String(), map[string]common.Big{"food": common.NewBig(10)})
+
+		pt.setDesign(setting)
+		pt.setDeposit(depositor, 1000, 1)
+
+		op, err := payment.NewUpdateAccountSetting(payment.NewUpdateAccountSettingFact(
+			[]byte("token"), depositor, pt.contract, common.NewBig(2000), 1, pt.now+3600, 1, c.limits,
+			pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reasons, err := pt.process(payment.NewUpdateAccountSettingProcessor(), op)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reasons[0] != nil {
+			t.Fatalf("%s: %v", name, reasons[0])
+		}
+
+		nSetting := pt.design().AccountSetting(depositor.String())
+		if i := nSetting.TransferLimit(pt.GenesisCurrency.String()); !i.Equal(common.NewBig(2000)) {
+			t.Errorf("%s: transfer limit %v", name, i)
+		}
+
+		limits := nSetting.CategoryLimits(pt.GenesisCurrency.String())
+		if len(limits) != len(c.result) {
+			t.Errorf("%s: category limits %v, not %v", name, limits, c.result)
+
+			continue
+		}
+
+		for k, v := range c.result {
+			if l, found := limits[k]; !found || !l.Equal(v) {
+				t.Errorf("%s: category limits %v, not %v", name, limits, c.result)
+			}
+		}
+	}
+}
+
+func TestUpdateAccountSettingFactEncode(t *testing.T) {
+	pt := newPaymentTest(t)
+	depositor := pt.account("depositor")
+	jenc, benc := newTestEncoders(t)
+
+	newFact := func(limits map[string]common.Big) payment.UpdateAccountSettingFact {
+		return payment.NewUpdateAccountSettingFact(
+			[]byte("token"), depositor, pt.contract, common.NewBig(2000), 1, pt.now+3600, 1, limits,
+			pt.GenesisCurrency)
+	}
+
+	omitted, cleared := newFact(nil), newFact(map[string]common.Big{})
+
+	if omitted.Hash().Equal(cleared.Hash()) {
+		t.Error("omitted and cleared category limits have same hash")
+	}
+
+	for name, fact := range map[string]payment.UpdateAccountSettingFact{"omitted": omitted, "cleared": cleared} {
+		b, err := util.MarshalJSON(fact)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var jfact payment.UpdateAccountSettingFact
+		if err := jfact.DecodeJSON(b, jenc); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err = benc.Marshal(fact)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var bfact payment.UpdateAccountSettingFact
+		if err := bfact.DecodeBSON(b, benc); err != nil {
+			t.Fatal(err)
+		}
+
+		for enc, decoded := range map[string]payment.UpdateAccountSettingFact{"json": jfact, "bson": bfact} {
+			switch {
+			case (decoded.CategoryLimits() == nil) != (fact.CategoryLimits() == nil):
+				t.Errorf("%s: %s decoded category limits, %v", name, enc, decoded.CategoryLimits())
+			case !decoded.GenerateHash().Equal(fact.Hash()):
+				t.Errorf("%s: %s decoded hash does not match", name, enc)
+			}
+		}
+	}
+}
@@ -1,8 +1,6 @@
 package payment
 
 import (
-	"fmt"
-
 	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/currency-model/operation/extras"
 	ctypes "github.com/imfact-labs/currency-model/types"
@@ -120,9 +118,7 @@ func (fact WithdrawFact) ActiveContract() []base.Address {
 
 func (fact WithdrawFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
 	r := make(map[ctypes.DuplicationKeyType][]string)
-
-	r[extras.DuplicationKeyTypeContractWithdraw] = []string{
-		fmt.Sprintf("%s:%s", fact.contract.String(), fact.currency.String())}
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
 
 	return r, nil
 }
@@ -132,34 +128,7 @@ type Withdraw struct {
 }
 
 func (op Withdraw) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
-	r := make(map[ctypes.DuplicationKeyType][]string)
-
-	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
-		return nil, err
-	}
-
-	factDupKeyer, ok := op.Fact().(extras.DeDupeKeyer)
-	if !ok {
-		return nil, errors.Errorf("%T does not implement DeDupeKeyer", op.Fact())
-	}
-
-	factDupKeys, err := factDupKeyer.DupKey()
-	if err != nil {
-		return nil, err
-	}
-
-	remainingContractWithdrawKeys := excludeDuplicatedContractWithdrawKeys(
-		r[extras.DuplicationKeyTypeContractWithdraw],
-		factDupKeys[extras.DuplicationKeyTypeContractWithdraw],
-	)
-
-	if len(remainingContractWithdrawKeys) == 0 {
-		delete(r, extras.DuplicationKeyTypeContractWithdraw)
-	} else {
-		r[extras.DuplicationKeyTypeContractWithdraw] = remainingContractWithdrawKeys
-	}
-
-	return r, nil
+	return contractWithdrawOperationDupKey(op)
 }
 
 func NewWithdraw(fact WithdrawFact) (Withdraw, error) {
@@ -0,0 +1,144 @@
+package payment
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	WithdrawAllFactHint = hint.MustNewHint("mitum-payment-withdraw-all-operation-fact-v0.0.1")
+	WithdrawAllHint     = hint.MustNewHint("mitum-payment-withdraw-all-operation-v0.0.1")
+)
+
+type WithdrawAllFact struct {
+	base.BaseFact
+	sender   base.Address
+	contract base.Address
+	currency ctypes.CurrencyID
+}
+
+func NewWithdrawAllFact(
+	token []byte, sender, contract base.Address, currency ctypes.CurrencyID) WithdrawAllFact {
+	bf := base.NewBaseFact(WithdrawAllFactHint, token)
+	fact := WithdrawAllFact{
+		BaseFact: bf,
+		sender:   sender,
+		contract: contract,
+		currency: currency,
+	}
+
+	fact.SetHash(fact.GenerateHash())
+	return fact
+}
+
+func (fact WithdrawAllFact) IsValid(b []byte) error {
+	if fact.sender.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("sender %v is same with contract account", fact.sender)))
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter,
+		fact.sender,
+		fact.contract,
+		fact.currency,
+	); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact WithdrawAllFact) Hash() util.Hash {
+	return fact.BaseFact.Hash()
+}
+
+func (fact WithdrawAllFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact WithdrawAllFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.Token(),
+		fact.sender.Bytes(),
+		fact.contract.Bytes(),
+		fact.currency.Bytes(),
+	)
+}
+
+func (fact WithdrawAllFact) Token() base.Token {
+	return fact.BaseFact.Token()
+}
+
+func (fact WithdrawAllFact) Sender() base.Address {
+	return fact.sender
+}
+
+func (fact WithdrawAllFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact WithdrawAllFact) Currency() ctypes.CurrencyID {
+	return fact.currency
+}
+
+func (fact WithdrawAllFact) Addresses() ([]base.Address, error) {
+	return []base.Address{fact.sender}, nil
+}
+
+func (fact WithdrawAllFact) FeeBase() (ctypes.CurrencyID, int, int, bool) {
+	return fact.Currency(), extras.NoItemFeeBaseItemCount, len(fact.Bytes()), extras.HasNoItem
+}
+
+func (fact WithdrawAllFact) FeePayer() base.Address {
+	return fact.sender
+}
+
+func (fact WithdrawAllFact) FactUser() base.Address {
+	return fact.sender
+}
+
+func (fact WithdrawAllFact) Signer() base.Address {
+	return fact.sender
+}
+
+func (fact WithdrawAllFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+func (fact WithdrawAllFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[DuplicationKeyTypeDepositor] = []string{depositorDupKey(fact.contract, fact.sender)}
+
+	return r, nil
+}
+
+type WithdrawAll struct {
+	extras.ExtendedOperation
+}
+
+func (op WithdrawAll) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewWithdrawAll(fact WithdrawAllFact) (WithdrawAll, error) {
+	return WithdrawAll{
+		ExtendedOperation: extras.NewExtendedOperation(WithdrawAllHint, fact),
+	}, nil
+}
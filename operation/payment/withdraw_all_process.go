@@ -0,0 +1,203 @@
+package payment
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var withdrawAllProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(WithdrawAllProcessor)
+	},
+}
+
+func (WithdrawAll) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type WithdrawAllProcessor struct {
+	*base.BaseOperationProcessor
+	proposal *base.ProposalSignFact
+}
+
+func NewWithdrawAllProcessor() ctypes.GetNewProcessorWithProposal {
+	return func(
+		height base.Height,
+		proposal *base.ProposalSignFact,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		e := util.StringError("failed to create new WithdrawAllProcessor")
+
+		nopp := withdrawAllProcessorPool.Get()
+		opp, ok := nopp.(*WithdrawAllProcessor)
+		if !ok {
+			return nil, e.Errorf("expected WithdrawAllProcessor, not %T", nopp)
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.proposal = proposal
+
+		return opp, nil
+	}
+}
+
+func (opp *WithdrawAllProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(WithdrawAllFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", WithdrawAllFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	st, err := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("payment service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	design, err := state.GetDesignFromState(st)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("service design value for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	// NOTE the deposit of the account which was not added to the design over
+	// the accounts limit can be withdrawn without the setting.
+	if setting := design.AccountSetting(fact.Sender().String()); setting != nil && setting.IsLocked() {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	st, err = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		"account record", getStateFunc)
+	if err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("record of account, %v in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	if _, err := state.GetDepositRecordFromState(st); err != nil {
+		return nil, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("record of account, %v not found in contract account %v",
+				fact.Sender(), fact.Contract(),
+			)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *WithdrawAllProcessor) Process( // nolint:dupl
+	_ context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, _ := op.Fact().(WithdrawAllFact)
+
+	var sts []base.StateMergeValue // nolint:prealloc
+	proposal := *opp.proposal
+	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
+
+	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
+	design, _ := state.GetDesignFromState(st)
+	setting := design.AccountSetting(fact.Sender().String())
+	if setting != nil {
+		sts = append(sts, state.NewDesignStateMergeValue(
+			state.DesignStateKey(fact.Contract().String()),
+			state.NewRemoveDesignAccountStateValue(*setting),
+		))
+	}
+
+	st, _ = cstate.ExistsState(
+		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+		"account record", getStateFunc)
+	record, _ := state.GetDepositRecordFromState(st)
+
+	cids := make([]string, 0, len(record.Items()))
+	for k := range record.Items() {
+		cids = append(cids, k)
+	}
+	sort.Strings(cids)
+
+	for _, k := range cids {
+		if err := checkChannelClosed(fact.Contract(), fact.Sender(), k, nowTime, getStateFunc); err != nil {
+			return nil, err, nil
+		}
+	}
+
+	for _, k := range cids {
+		sts = append(sts, state.NewDepositRecordStateMergeValue(
+			state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
+			state.NewDeductDepositRecordStateValue(fact.Sender(), k, *record.Amount(k), &nowTime),
+		))
+	}
+
+	for _, k := range cids {
+		big := *record.Amount(k)
+		if !big.OverZero() {
+			continue
+		}
+
+		var lockUp *types.LockUp
+		if setting != nil {
+			lockUp = setting.LockUp(k)
+		}
+
+		smvs, err := withdrawStateMergeValues(
+			fact.Contract(), fact.Sender(), ctypes.CurrencyID(k), big, lockUp,
+			fact.Hash(), nowTime, opp.Height(), getStateFunc,
+		)
+		if err != nil {
+			return nil, err, nil
+		}
+		sts = append(sts, smvs...)
+	}
+
+	return sts, nil, nil
+}
+
+func (opp *WithdrawAllProcessor) Close() error {
+	opp.proposal = nil
+	withdrawAllProcessorPool.Put(opp)
+
+	return nil
+}
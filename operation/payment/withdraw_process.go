@@ -96,17 +96,17 @@ func (opp *WithdrawProcessor) PreProcess(
 			)), nil
 	}
 
-	setting := design.AccountSetting(fact.Sender().String())
-	if setting == nil {
+	// NOTE the deposit of the account which was not added to the design over
+	// the accounts limit can be withdrawn without the setting.
+	switch setting := design.AccountSetting(fact.Sender().String()); {
+	case setting == nil:
+	case setting.IsLocked():
 		return nil, base.NewBaseOperationProcessReasonError(
 			common.ErrMPreProcess.
-				Wrap(common.ErrMValueInvalid).Errorf("setting of account, %v not found in contract account %v",
+				Wrap(common.ErrMValueInvalid).Errorf("deposit of account, %v is locked in contract account %v",
 				fact.Sender(), fact.Contract(),
 			)), nil
-	}
-
-	big := setting.TransferLimit(cid.String())
-	if big == nil {
+	case setting.TransferLimit(cid.String()) == nil:
 		return nil, base.NewBaseOperationProcessReasonError(
 			common.ErrMPreProcess.
 				Wrap(common.ErrMValueInvalid).Errorf("setting for currency, %v of account, %v not found in contract account %v",
@@ -156,87 +156,173 @@ func (opp *WithdrawProcessor) Process( // nolint:dupl
 	proposal := *opp.proposal
 	nowTime := uint64(proposal.ProposalFact().ProposedAt().Unix())
 	cid := fact.Currency()
+	if err := checkChannelClosed(fact.Contract(), fact.Sender(), cid.String(), nowTime, getStateFunc); err != nil {
+		return nil, err, nil
+	}
+
 	st, _ := cstate.ExistsState(state.DesignStateKey(fact.Contract().String()), "service design", getStateFunc)
 	design, _ := state.GetDesignFromState(st)
 	setting := design.AccountSetting(fact.Sender().String())
-	nSetting := types.NewSettings(fact.Sender())
-	for k, v := range setting.Items() {
-		nSetting.SetItem(k, v.TransferLimit, v.StartTime, v.EndTime, v.Duration)
-	}
-	nSetting.Remove(cid.String())
-	nDesign := types.NewDesign()
-	for _, v := range design.AccountSettings() {
-		nDesign.AddAccountSetting(v)
-	}
-	if len(nSetting.Items()) < 1 {
-		nDesign.RemoveAccountSetting(fact.Sender())
-		if err := nDesign.IsValid(nil); err != nil {
-			return nil, base.NewBaseOperationProcessReasonError("invalid service design, %q; %w", fact.Contract(), err), nil
-		}
-	} else {
-		nDesign.UpdateAccountSetting(nSetting)
-		if err := nDesign.IsValid(nil); err != nil {
-			return nil, base.NewBaseOperationProcessReasonError("invalid service design, %q; %w", fact.Contract(), err), nil
+
+	var lockUp *types.LockUp
+	if setting != nil {
+		lockUp = setting.LockUp(cid.String())
+
+		nSetting := copyAccountSetting(*setting)
+		nSetting.Remove(cid.String())
+
+		// update Design
+		if len(nSetting.Items()) < 1 {
+			sts = append(sts, state.NewDesignStateMergeValue(
+				state.DesignStateKey(fact.Contract().String()),
+				state.NewRemoveDesignAccountStateValue(*setting),
+			))
+		} else {
+			sts = append(sts, state.NewDesignStateMergeValue(
+				state.DesignStateKey(fact.Contract().String()),
+				state.NewSetDesignAccountStateValue(setting, nSetting),
+			))
 		}
 	}
-	// update Design
-	sts = append(sts, cstate.NewStateMergeValue(
-		state.DesignStateKey(fact.Contract().String()),
-		state.NewDesignStateValue(nDesign),
-	))
 
 	st, _ = cstate.ExistsState(
 		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
 		"account record", getStateFunc)
 	record, _ := state.GetDepositRecordFromState(st)
 	big := record.Amount(cid.String())
-	nRecord := types.NewDepositRecord(fact.Sender())
-	for k, v := range record.Items() {
-		nRecord.SetItem(k, v.Amount, v.TransferredAt)
-	}
-	nRecord.SetItem(cid.String(), common.ZeroBig, nowTime)
 
-	if err := nRecord.IsValid(nil); err != nil {
-		return nil, base.NewBaseOperationProcessReasonError(
-			"invalid record of account, %v in contract account, %v: %w", fact.Sender(), fact.Contract(), err), nil
-	}
 	// update Record
-	sts = append(sts, cstate.NewStateMergeValue(
+	sts = append(sts, state.NewDepositRecordStateMergeValue(
 		state.DepositRecordStateKey(fact.Contract().String(), fact.Sender().String()),
-		state.NewDepositRecordStateValue(nRecord),
+		state.NewDeductDepositRecordStateValue(fact.Sender(), cid.String(), *big, &nowTime),
 	))
 
-	am := ctypes.NewAmount(*big, cid)
+	smvs, err := withdrawStateMergeValues(
+		fact.Contract(), fact.Sender(), cid, *big, lockUp,
+		fact.Hash(), nowTime, opp.Height(), getStateFunc,
+	)
+	if err != nil {
+		return nil, err, nil
+	}
+	sts = append(sts, smvs...)
+
+	return sts, nil, nil
+}
+
+func (opp *WithdrawProcessor) Close() error {
+	opp.proposal = nil
+	withdrawProcessorPool.Put(opp)
+
+	return nil
+}
+
+// withdrawStateMergeValues moves amount of the deposit to the depositor. The
+// withdraw before the maturity of lockUp is rejected unless the lock-up
+// allows it with the penalty, which is sent to the penalty receiver.
+func withdrawStateMergeValues(
+	contract, depositor base.Address,
+	cid ctypes.CurrencyID,
+	amount common.Big,
+	lockUp *types.LockUp,
+	factHash util.Hash,
+	nowTime uint64,
+	height base.Height,
+	getStateFunc base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError) {
+	var sts []base.StateMergeValue // nolint:prealloc
+
+	penalty := common.ZeroBig
+	var receiver base.Address
+
+	if lockUp != nil && nowTime < lockUp.MaturityTime {
+		if !lockUp.AllowsEarlyWithdraw() {
+			return nil, base.NewBaseOperationProcessReasonError(
+				"deposit of account, %v for currency id, %v is locked up until %d in contract account, %v",
+				depositor, cid, lockUp.MaturityTime, contract)
+		}
+
+		r, err := lockUp.Receiver()
+		if err != nil {
+			return nil, base.NewBaseOperationProcessReasonError(
+				"invalid penalty receiver of account, %v in contract account, %v: %w", depositor, contract, err)
+		}
+		receiver = r
+		penalty = lockUp.Penalty(amount)
+
+		smv, err := cstate.CreateNotExistAccount(receiver, getStateFunc)
+		if err != nil {
+			return nil, base.NewBaseOperationProcessReasonError("%w", err)
+		} else if smv != nil {
+			sts = append(sts, smv)
+		}
+	}
+
+	withdrawn := amount.Sub(penalty)
+
 	sts = append(
 		sts,
 		common.NewBaseStateMergeValue(
-			currency.BalanceStateKey(fact.Contract(), cid),
-			currency.NewDeductBalanceStateValue(am),
+			currency.BalanceStateKey(contract, cid),
+			currency.NewDeductBalanceStateValue(ctypes.NewAmount(amount, cid)),
 			func(height base.Height, st base.State) base.StateValueMerger {
 				return currency.NewBalanceStateValueMerger(
-					height, currency.BalanceStateKey(fact.Contract(), cid),
+					height, currency.BalanceStateKey(contract, cid),
 					cid, st,
 				)
 			}),
 	)
 
+	if withdrawn.OverZero() {
+		sts = append(sts, common.NewBaseStateMergeValue(
+			currency.BalanceStateKey(depositor, cid),
+			currency.NewAddBalanceStateValue(ctypes.NewAmount(withdrawn, cid)),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return currency.NewBalanceStateValueMerger(height,
+					currency.BalanceStateKey(depositor, cid),
+					cid, st,
+				)
+			},
+		))
+	}
+
 	sts = append(sts, common.NewBaseStateMergeValue(
-		currency.BalanceStateKey(fact.Sender(), cid),
-		currency.NewAddBalanceStateValue(am),
+		state.EventLogStateKey(contract.String(), depositor.String()),
+		state.NewAppendEventLogStateValue(depositor, types.NewEvent(
+			types.EventTypeWithdraw, cid.String(), withdrawn, "", factHash, nowTime, height,
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(contract.String(), depositor.String()), st,
+			)
+		},
+	))
+
+	if !penalty.OverZero() {
+		return sts, nil
+	}
+
+	sts = append(sts, common.NewBaseStateMergeValue(
+		currency.BalanceStateKey(receiver, cid),
+		currency.NewAddBalanceStateValue(ctypes.NewAmount(penalty, cid)),
 		func(height base.Height, st base.State) base.StateValueMerger {
 			return currency.NewBalanceStateValueMerger(height,
-				currency.BalanceStateKey(fact.Sender(), cid),
+				currency.BalanceStateKey(receiver, cid),
 				cid, st,
 			)
 		},
 	))
 
-	return sts, nil, nil
-}
-
-func (opp *WithdrawProcessor) Close() error {
-	opp.proposal = nil
-	withdrawProcessorPool.Put(opp)
+	sts = append(sts, common.NewBaseStateMergeValue(
+		state.EventLogStateKey(contract.String(), depositor.String()),
+		state.NewAppendEventLogStateValue(depositor, types.NewEvent(
+			types.EventTypeWithdrawPenalty, cid.String(), penalty, receiver.String(), factHash, nowTime, height,
+		)),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewEventLogStateValueMerger(height,
+				state.EventLogStateKey(contract.String(), depositor.String()), st,
+			)
+		},
+	))
 
-	return nil
+	return sts, nil
 }
@@ -0,0 +1,76 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/payment-model/operation/payment"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestWithdrawDepositors(t *testing.T) {
+	pt := newPaymentTest(t)
+	a, b, c := pt.account("a"), pt.account("b"), pt.account("c")
+
+	other := pt.setting(b, 1000)
+	other.SetItem("PEN", common.NewBig(1000), 1, pt.now+3600, 1)
+
+	pt.setDesign(pt.setting(a, 1000), other, pt.setting(c, 1000))
+
+	for _, account := range []base.Address{a, b, c} {
+		pt.setDeposit(account, 1000, 1)
+	}
+	pt.NewTestBalanceState(pt.contract, pt.GenesisCurrency, 3000, true)
+
+	ops := make([]base.Operation, 2)
+	for i, account := range []base.Address{a, b} {
+		op, err := payment.NewWithdraw(payment.NewWithdrawFact([]byte("token"), account, pt.contract, pt.GenesisCurrency))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ops[i] = op
+	}
+
+	reasons, err := pt.process(payment.NewWithdrawProcessor(), ops...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range reasons {
+		if reasons[i] != nil {
+			t.Fatal(reasons[i])
+		}
+	}
+
+	design := pt.design()
+
+	if design.AccountSetting(a.String()) != nil {
+		t.Error("setting of a not removed")
+	}
+
+	var setting *types.Setting
+	switch setting = design.AccountSetting(b.String()); {
+	case setting == nil:
+		t.Fatal("setting of b removed")
+	case setting.TransferLimit(pt.GenesisCurrency.String()) != nil:
+		t.Error("currency of b not removed")
+	case setting.TransferLimit("PEN") == nil:
+		t.Error("other currency of b removed")
+	}
+
+	if design.AccountSetting(c.String()) == nil {
+		t.Error("setting of c removed")
+	}
+
+	for i, account := range []base.Address{a, b, c} {
+		if am := pt.deposit(account); !am.Equal(common.NewBig([]int64{0, 0, 1000}[i])) {
+			t.Errorf("deposit of %v, %v", account, am)
+		}
+	}
+
+	if i := pt.balance(pt.contract); !i.Equal(common.NewBig(1000)) {
+		t.Errorf("contract balance %v", i)
+	}
+}
@@ -13,21 +13,56 @@ var AddedHinters = []encoder.DecodeDetail{
 	{Hint: types.DesignHint, Instance: types.Design{}},
 	{Hint: types.SettingHint, Instance: types.Setting{}},
 	{Hint: types.DepositRecordHint, Instance: types.DepositRecord{}},
+	{Hint: types.EventLogHint, Instance: types.EventLog{}},
+	{Hint: types.ChannelHint, Instance: types.Channel{}},
+	{Hint: types.VoucherHint, Instance: types.Voucher{}},
+	{Hint: types.LockHint, Instance: types.Lock{}},
+	{Hint: types.MerchantHint, Instance: types.Merchant{}},
 
+	{Hint: payment.ClaimTransferHint, Instance: payment.ClaimTransfer{}},
+	{Hint: payment.ClaimVoucherHint, Instance: payment.ClaimVoucher{}},
+	{Hint: payment.CloseChannelHint, Instance: payment.CloseChannel{}},
 	{Hint: payment.DepositHint, Instance: payment.Deposit{}},
+	{Hint: payment.DepositItemsHint, Instance: payment.DepositItems{}},
+	{Hint: payment.LockDepositHint, Instance: payment.LockDeposit{}},
+	{Hint: payment.LockTransferHint, Instance: payment.LockTransfer{}},
+	{Hint: payment.OpenChannelHint, Instance: payment.OpenChannel{}},
+	{Hint: payment.RefundTransferHint, Instance: payment.RefundTransfer{}},
+	{Hint: payment.RegisterDepositKeysHint, Instance: payment.RegisterDepositKeys{}},
 	{Hint: payment.RegisterModelHint, Instance: payment.RegisterModel{}},
+	{Hint: payment.SplitTransferHint, Instance: payment.SplitTransfer{}},
 	{Hint: payment.TransferHint, Instance: payment.Transfer{}},
+	{Hint: payment.UnlockDepositHint, Instance: payment.UnlockDeposit{}},
 	{Hint: payment.UpdateAccountSettingHint, Instance: payment.UpdateAccountSetting{}},
+	{Hint: payment.UpdateMerchantHint, Instance: payment.UpdateMerchant{}},
 	{Hint: payment.WithdrawHint, Instance: payment.Withdraw{}},
+	{Hint: payment.WithdrawAllHint, Instance: payment.WithdrawAll{}},
 
 	{Hint: state.DesignStateValueHint, Instance: state.DesignStateValue{}},
 	{Hint: state.DepositRecordStateValueHint, Instance: state.DepositRecordStateValue{}},
+	{Hint: state.EventLogStateValueHint, Instance: state.EventLogStateValue{}},
+	{Hint: state.ChannelStateValueHint, Instance: state.ChannelStateValue{}},
+	{Hint: state.LockStateValueHint, Instance: state.LockStateValue{}},
+	{Hint: state.MerchantStateValueHint, Instance: state.MerchantStateValue{}},
 }
 
 var AddedSupportedHinters = []encoder.DecodeDetail{
+	{Hint: payment.ClaimTransferFactHint, Instance: payment.ClaimTransferFact{}},
+	{Hint: payment.ClaimVoucherFactHint, Instance: payment.ClaimVoucherFact{}},
+	{Hint: payment.CloseChannelFactHint, Instance: payment.CloseChannelFact{}},
 	{Hint: payment.DepositFactHint, Instance: payment.DepositFact{}},
+	{Hint: payment.DepositItemsFactHint, Instance: payment.DepositItemsFact{}},
+	{Hint: payment.LockDepositFactHint, Instance: payment.LockDepositFact{}},
+	{Hint: payment.LockTransferFactHint, Instance: payment.LockTransferFact{}},
+	{Hint: payment.OpenChannelFactHint, Instance: payment.OpenChannelFact{}},
+	{Hint: payment.RefundTransferFactHint, Instance: payment.RefundTransferFact{}},
+	{Hint: payment.RegisterDepositKeysFactHint, Instance: payment.RegisterDepositKeysFact{}},
 	{Hint: payment.RegisterModelFactHint, Instance: payment.RegisterModelFact{}},
+	{Hint: payment.SplitTransferFactHint, Instance: payment.SplitTransferFact{}},
 	{Hint: payment.TransferFactHint, Instance: payment.TransferFact{}},
+	{Hint: payment.UnlockDepositFactHint, Instance: payment.UnlockDepositFact{}},
 	{Hint: payment.UpdateAccountSettingFactHint, Instance: payment.UpdateAccountSettingFact{}},
+	{Hint: payment.UpdateMerchantFactHint, Instance: payment.UpdateMerchantFact{}},
 	{Hint: payment.WithdrawFactHint, Instance: payment.WithdrawFact{}},
+	{Hint: payment.WithdrawAllFactHint, Instance: payment.WithdrawAllFact{}},
 }
@@ -4,29 +4,19 @@ import (
 	"context"
 
 	cprocessor "github.com/imfact-labs/currency-model/operation/processor"
-	ctype "github.com/imfact-labs/currency-model/types"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/isaac"
 	"github.com/imfact-labs/mitum2/launch"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/hint"
 	"github.com/imfact-labs/mitum2/util/ps"
+	modapi "github.com/imfact-labs/payment-model/api"
 	"github.com/imfact-labs/payment-model/operation/payment"
 	"github.com/imfact-labs/payment-model/runtime/contracts"
 )
 
 var PNameOperationProcessorsMap = ps.Name("mitum-payment-operation-processors-map")
 
-type processorInfoA struct {
-	hint      hint.Hint
-	processor ctype.GetNewProcessor
-}
-
-type processorInfoB struct {
-	hint      hint.Hint
-	processor ctype.GetNewProcessorWithProposal
-}
-
 func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 	var isaacParams *isaac.Params
 	var db isaac.Database
@@ -53,24 +43,19 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
-	processorsA := []processorInfoA{
-		{payment.RegisterModelHint, payment.NewRegisterModelProcessor()},
-		{payment.DepositHint, payment.NewDepositProcessor()},
-		{payment.UpdateAccountSettingHint, payment.NewUpdateAccountSettingProcessor()},
-	}
-	processorsB := []processorInfoB{
-		{payment.WithdrawHint, payment.NewWithdrawProcessor()},
-		{payment.TransferHint, payment.NewTransferProcessor()},
-	}
+	modapi.SetSimulateDatabase(db, isaacParams.NetworkID())
+
+	processorsA := payment.ProcessorsA()
+	processorsB := payment.ProcessorsB(isaacParams.NetworkID())
 
 	for i := range processorsA {
 		p := processorsA[i]
 
-		if err := opr.SetProcessor(p.hint, p.processor); err != nil {
+		if err := opr.SetProcessor(p.Hint, p.Processor); err != nil {
 			return pctx, err
 		}
 
-		if err := setA.Add(p.hint,
+		if err := setA.Add(p.Hint,
 			func(height base.Height, getStatef base.GetStateFunc) (base.OperationProcessor, error) {
 				return opr.New(
 					height,
@@ -87,11 +72,11 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 	for i := range processorsB {
 		p := processorsB[i]
 
-		if err := opr.SetProcessorWithProposal(p.hint, p.processor); err != nil {
+		if err := opr.SetProcessorWithProposal(p.Hint, p.Processor); err != nil {
 			return pctx, err
 		}
 
-		if err := setB.Add(p.hint,
+		if err := setB.Add(p.Hint,
 			func(height base.Height, proposal base.ProposalSignFact, getStatef base.GetStateFunc) (base.OperationProcessor, error) {
 				if err := opr.SetProposal(&proposal); err != nil {
 					return nil, err
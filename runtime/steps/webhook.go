@@ -0,0 +1,60 @@
+package steps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/imfact-labs/payment-model/digest"
+	"gopkg.in/yaml.v3"
+)
+
+var PNameWebhookDesign = ps.Name("payment-webhook-design")
+
+// PLoadWebhookDesign loads the "payment_webhook" section of the node design.
+func PLoadWebhookDesign(pctx context.Context) (context.Context, error) {
+	e := util.StringError("load webhook design")
+
+	var flag launch.DesignFlag
+	if err := util.LoadFromContextOK(pctx, launch.DesignFlagContextKey, &flag); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var design digest.WebhookDesign
+
+	switch flag.Scheme() {
+	case "file":
+		b, err := os.ReadFile(filepath.Clean(flag.URL().Path))
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		nb, err := util.ReplaceEnvVariables(b)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		var m struct {
+			Webhook *digest.WebhookDesign `yaml:"payment_webhook"`
+		}
+
+		if err := yaml.Unmarshal(nb, &m); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		if m.Webhook != nil {
+			design = *m.Webhook
+		}
+	default:
+		return pctx, e.Errorf("unknown design uri, %q", flag.URL())
+	}
+
+	if err := design.IsValid(nil); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	return context.WithValue(pctx, digest.ContextValueWebhookDesign, design), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/hint"
@@ -133,3 +134,401 @@ func IsDepositRecordStateKey(key string) bool {
 func DepositRecordStateKey(addr string, acAddr string) string {
 	return fmt.Sprintf("%s:%s:%s", PaymentStateKey(addr), acAddr, DepositRecordStateKeySuffix)
 }
+
+var (
+	EventLogStateValueHint = hint.MustNewHint("mitum-payment-event-log-state-value-v0.0.1")
+	EventLogStateKeySuffix = "eventlog"
+)
+
+type EventLogStateValue struct {
+	hint.BaseHinter
+	Log types.EventLog
+}
+
+func NewEventLogStateValue(log types.EventLog) EventLogStateValue {
+	return EventLogStateValue{
+		BaseHinter: hint.NewBaseHinter(EventLogStateValueHint),
+		Log:        log,
+	}
+}
+
+func (sv EventLogStateValue) Hint() hint.Hint {
+	return sv.BaseHinter.Hint()
+}
+
+func (sv EventLogStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid EventLogStateValue")
+
+	if err := sv.BaseHinter.IsValid(EventLogStateValueHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sv.Log.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (sv EventLogStateValue) HashBytes() []byte {
+	return sv.Log.Bytes()
+}
+
+func GetEventLogFromState(st base.State) (*types.EventLog, error) {
+	v := st.Value()
+	if v == nil {
+		return nil, errors.Errorf("state value is nil")
+	}
+
+	lsv, ok := v.(EventLogStateValue)
+	if !ok {
+		return nil, errors.Errorf("expected EventLogStateValue but, %T", v)
+	}
+
+	return &lsv.Log, nil
+}
+
+func IsEventLogStateKey(key string) bool {
+	return strings.HasPrefix(key, PaymentStateKeyPrefix) && strings.HasSuffix(key, EventLogStateKeySuffix)
+}
+
+func EventLogStateKey(addr string, acAddr string) string {
+	return fmt.Sprintf("%s:%s:%s", PaymentStateKey(addr), acAddr, EventLogStateKeySuffix)
+}
+
+var (
+	ChannelStateValueHint = hint.MustNewHint("mitum-payment-channel-state-value-v0.0.1")
+	ChannelStateKeySuffix = "channel"
+)
+
+type ChannelStateValue struct {
+	hint.BaseHinter
+	Channel types.Channel
+}
+
+func NewChannelStateValue(channel types.Channel) ChannelStateValue {
+	return ChannelStateValue{
+		BaseHinter: hint.NewBaseHinter(ChannelStateValueHint),
+		Channel:    channel,
+	}
+}
+
+func (sv ChannelStateValue) Hint() hint.Hint {
+	return sv.BaseHinter.Hint()
+}
+
+func (sv ChannelStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid ChannelStateValue")
+
+	if err := sv.BaseHinter.IsValid(ChannelStateValueHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sv.Channel.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (sv ChannelStateValue) HashBytes() []byte {
+	return sv.Channel.Bytes()
+}
+
+func GetChannelFromState(st base.State) (*types.Channel, error) {
+	v := st.Value()
+	if v == nil {
+		return nil, errors.Errorf("state value is nil")
+	}
+
+	csv, ok := v.(ChannelStateValue)
+	if !ok {
+		return nil, errors.Errorf("expected ChannelStateValue but, %T", v)
+	}
+
+	return &csv.Channel, nil
+}
+
+func IsChannelStateKey(key string) bool {
+	return strings.HasPrefix(key, PaymentStateKeyPrefix) && strings.HasSuffix(key, ChannelStateKeySuffix)
+}
+
+func ChannelStateKey(addr string, acAddr string, cid string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", PaymentStateKey(addr), acAddr, cid, ChannelStateKeySuffix)
+}
+
+var (
+	LockStateValueHint = hint.MustNewHint("mitum-payment-lock-state-value-v0.0.1")
+	LockStateKeySuffix = "lock"
+)
+
+type LockStateValue struct {
+	hint.BaseHinter
+	Lock types.Lock
+}
+
+func NewLockStateValue(lock types.Lock) LockStateValue {
+	return LockStateValue{
+		BaseHinter: hint.NewBaseHinter(LockStateValueHint),
+		Lock:       lock,
+	}
+}
+
+func (sv LockStateValue) Hint() hint.Hint {
+	return sv.BaseHinter.Hint()
+}
+
+func (sv LockStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid LockStateValue")
+
+	if err := sv.BaseHinter.IsValid(LockStateValueHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sv.Lock.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (sv LockStateValue) HashBytes() []byte {
+	return sv.Lock.Bytes()
+}
+
+func GetLockFromState(st base.State) (*types.Lock, error) {
+	v := st.Value()
+	if v == nil {
+		return nil, errors.Errorf("state value is nil")
+	}
+
+	lsv, ok := v.(LockStateValue)
+	if !ok {
+		return nil, errors.Errorf("expected LockStateValue but, %T", v)
+	}
+
+	return &lsv.Lock, nil
+}
+
+func IsLockStateKey(key string) bool {
+	return strings.HasPrefix(key, PaymentStateKeyPrefix) && strings.HasSuffix(key, LockStateKeySuffix)
+}
+
+func LockStateKey(addr string, hashLock string) string {
+	return fmt.Sprintf("%s:%s:%s", PaymentStateKey(addr), hashLock, LockStateKeySuffix)
+}
+
+var (
+	MerchantStateValueHint = hint.MustNewHint("mitum-payment-merchant-state-value-v0.0.1")
+	MerchantStateKeySuffix = "merchant"
+)
+
+type MerchantStateValue struct {
+	hint.BaseHinter
+	Merchant types.Merchant
+}
+
+func NewMerchantStateValue(merchant types.Merchant) MerchantStateValue {
+	return MerchantStateValue{
+		BaseHinter: hint.NewBaseHinter(MerchantStateValueHint),
+		Merchant:   merchant,
+	}
+}
+
+func (sv MerchantStateValue) Hint() hint.Hint {
+	return sv.BaseHinter.Hint()
+}
+
+func (sv MerchantStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid MerchantStateValue")
+
+	if err := sv.BaseHinter.IsValid(MerchantStateValueHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sv.Merchant.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (sv MerchantStateValue) HashBytes() []byte {
+	return sv.Merchant.Bytes()
+}
+
+func GetMerchantFromState(st base.State) (*types.Merchant, error) {
+	v := st.Value()
+	if v == nil {
+		return nil, errors.Errorf("state value is nil")
+	}
+
+	msv, ok := v.(MerchantStateValue)
+	if !ok {
+		return nil, errors.Errorf("expected MerchantStateValue but, %T", v)
+	}
+
+	return &msv.Merchant, nil
+}
+
+func IsMerchantStateKey(key string) bool {
+	return strings.HasPrefix(key, PaymentStateKeyPrefix) && strings.HasSuffix(key, MerchantStateKeySuffix)
+}
+
+func MerchantStateKey(addr string, merchant string) string {
+	return fmt.Sprintf("%s:%s:%s", PaymentStateKey(addr), merchant, MerchantStateKeySuffix)
+}
+
+// AppendEventLogStateValue is merged by EventLogStateValueMerger and is not
+// stored as is.
+type AppendEventLogStateValue struct {
+	Account base.Address
+	Event   types.Event
+}
+
+func NewAppendEventLogStateValue(account base.Address, event types.Event) AppendEventLogStateValue {
+	return AppendEventLogStateValue{
+		Account: account,
+		Event:   event,
+	}
+}
+
+func (sv AppendEventLogStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid AppendEventLogStateValue")
+
+	if err := util.CheckIsValiders(nil, false, sv.Account, sv.Event); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (sv AppendEventLogStateValue) HashBytes() []byte {
+	return util.ConcatBytesSlice(sv.Account.Bytes(), []byte(sv.Event.Type), []byte(sv.Event.FactHash))
+}
+
+// UpdateDesignAccountStateValue changes the setting of Account in the design
+// from Prev, the setting read by the operation, to Setting; nil Prev adds the
+// account and nil Setting removes the currencies of Prev. Only the changes
+// from Prev are applied, so the updates of the different currencies of an
+// account are kept. It is merged by DesignStateValueMerger and is not stored
+// as is.
+type UpdateDesignAccountStateValue struct {
+	Account base.Address
+	Prev    *types.Setting
+	Setting *types.Setting
+}
+
+func NewSetDesignAccountStateValue(prev *types.Setting, setting types.Setting) UpdateDesignAccountStateValue {
+	return UpdateDesignAccountStateValue{
+		Account: setting.Address(),
+		Prev:    prev,
+		Setting: &setting,
+	}
+}
+
+func NewRemoveDesignAccountStateValue(prev types.Setting) UpdateDesignAccountStateValue {
+	return UpdateDesignAccountStateValue{
+		Account: prev.Address(),
+		Prev:    &prev,
+	}
+}
+
+func (sv UpdateDesignAccountStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid UpdateDesignAccountStateValue")
+
+	if err := util.CheckIsValiders(nil, false, sv.Account); err != nil {
+		return e.Wrap(err)
+	}
+
+	if sv.Prev == nil && sv.Setting == nil {
+		return e.Errorf("empty setting")
+	}
+
+	for _, setting := range []*types.Setting{sv.Prev, sv.Setting} {
+		if setting == nil {
+			continue
+		}
+
+		if err := setting.IsValid(nil); err != nil {
+			return e.Wrap(err)
+		}
+
+		if !setting.Address().Equal(sv.Account) {
+			return e.Errorf("setting address, %v not matched with account, %v", setting.Address(), sv.Account)
+		}
+	}
+
+	return nil
+}
+
+func (sv UpdateDesignAccountStateValue) HashBytes() []byte {
+	var prev, b []byte
+	if sv.Prev != nil {
+		prev = sv.Prev.Bytes()
+	}
+
+	if sv.Setting != nil {
+		b = sv.Setting.Bytes()
+	}
+
+	return util.ConcatBytesSlice(sv.Account.Bytes(), prev, b)
+}
+
+// UpdateDepositRecordStateValue adds Add to and deducts Deduct from the amount
+// of Currency in the deposit record of Account; TransferredAt replaces the
+// last transferred time when it is not nil. It is merged by
+// DepositRecordStateValueMerger and is not stored as is.
+type UpdateDepositRecordStateValue struct {
+	Account       base.Address
+	Currency      string
+	Add           common.Big
+	Deduct        common.Big
+	TransferredAt *uint64
+}
+
+func NewAddDepositRecordStateValue(account base.Address, cid string, amount common.Big) UpdateDepositRecordStateValue {
+	return UpdateDepositRecordStateValue{
+		Account:  account,
+		Currency: cid,
+		Add:      amount,
+		Deduct:   common.ZeroBig,
+	}
+}
+
+func NewDeductDepositRecordStateValue(
+	account base.Address, cid string, amount common.Big, transferredAt *uint64,
+) UpdateDepositRecordStateValue {
+	return UpdateDepositRecordStateValue{
+		Account:       account,
+		Currency:      cid,
+		Add:           common.ZeroBig,
+		Deduct:        amount,
+		TransferredAt: transferredAt,
+	}
+}
+
+func (sv UpdateDepositRecordStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid UpdateDepositRecordStateValue")
+
+	if err := util.CheckIsValiders(nil, false, sv.Account, sv.Add, sv.Deduct); err != nil {
+		return e.Wrap(err)
+	}
+
+	if len(sv.Currency) < 1 {
+		return e.Errorf("empty currency")
+	}
+
+	return nil
+}
+
+func (sv UpdateDepositRecordStateValue) HashBytes() []byte {
+	var ts []byte
+	if sv.TransferredAt != nil {
+		ts = util.Uint64ToBytes(*sv.TransferredAt)
+	}
+
+	return util.ConcatBytesSlice(
+		sv.Account.Bytes(), []byte(sv.Currency), sv.Add.Bytes(), sv.Deduct.Bytes(), ts,
+	)
+}
@@ -81,3 +81,151 @@ func (sv *DepositRecordStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) er
 
 	return nil
 }
+
+func (sv EventLogStateValue) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":     sv.Hint().String(),
+			"event_log": sv.Log,
+		},
+	)
+}
+
+type EventLogStateValueBSONUnmarshaler struct {
+	Hint     string   `bson:"_hint"`
+	EventLog bson.Raw `bson:"event_log"`
+}
+
+func (sv *EventLogStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of EventLogStateValue")
+
+	var u EventLogStateValueBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	sv.BaseHinter = hint.NewBaseHinter(ht)
+
+	var log types.EventLog
+	if err := log.DecodeBSON(u.EventLog, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Log = log
+
+	return nil
+}
+
+func (sv ChannelStateValue) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":   sv.Hint().String(),
+			"channel": sv.Channel,
+		},
+	)
+}
+
+type ChannelStateValueBSONUnmarshaler struct {
+	Hint    string   `bson:"_hint"`
+	Channel bson.Raw `bson:"channel"`
+}
+
+func (sv *ChannelStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of ChannelStateValue")
+
+	var u ChannelStateValueBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	sv.BaseHinter = hint.NewBaseHinter(ht)
+
+	var channel types.Channel
+	if err := channel.DecodeBSON(u.Channel, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Channel = channel
+
+	return nil
+}
+
+func (sv LockStateValue) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint": sv.Hint().String(),
+			"lock":  sv.Lock,
+		},
+	)
+}
+
+type LockStateValueBSONUnmarshaler struct {
+	Hint string   `bson:"_hint"`
+	Lock bson.Raw `bson:"lock"`
+}
+
+func (sv *LockStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of LockStateValue")
+
+	var u LockStateValueBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	sv.BaseHinter = hint.NewBaseHinter(ht)
+
+	var lock types.Lock
+	if err := lock.DecodeBSON(u.Lock, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Lock = lock
+
+	return nil
+}
+
+func (sv MerchantStateValue) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":    sv.Hint().String(),
+			"merchant": sv.Merchant,
+		},
+	)
+}
+
+type MerchantStateValueBSONUnmarshaler struct {
+	Hint     string   `bson:"_hint"`
+	Merchant bson.Raw `bson:"merchant"`
+}
+
+func (sv *MerchantStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of MerchantStateValue")
+
+	var u MerchantStateValueBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	sv.BaseHinter = hint.NewBaseHinter(ht)
+
+	var merchant types.Merchant
+	if err := merchant.DecodeBSON(u.Merchant, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Merchant = merchant
+
+	return nil
+}
@@ -77,3 +77,139 @@ func (sv *DepositRecordStateValue) DecodeJSON(b []byte, enc encoder.Encoder) err
 
 	return nil
 }
+
+type EventLogStateValueJSONMarshaler struct {
+	hint.BaseHinter
+	Log types.EventLog `json:"event_log"`
+}
+
+func (sv EventLogStateValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(
+		EventLogStateValueJSONMarshaler(sv),
+	)
+}
+
+type EventLogStateValueJSONUnmarshaler struct {
+	Hint     hint.Hint       `json:"_hint"`
+	EventLog json.RawMessage `json:"event_log"`
+}
+
+func (sv *EventLogStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of EventLogStateValue")
+
+	var u EventLogStateValueJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	sv.BaseHinter = hint.NewBaseHinter(u.Hint)
+	var log types.EventLog
+	if err := log.DecodeJSON(u.EventLog, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Log = log
+
+	return nil
+}
+
+type ChannelStateValueJSONMarshaler struct {
+	hint.BaseHinter
+	Channel types.Channel `json:"channel"`
+}
+
+func (sv ChannelStateValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(
+		ChannelStateValueJSONMarshaler(sv),
+	)
+}
+
+type ChannelStateValueJSONUnmarshaler struct {
+	Hint    hint.Hint       `json:"_hint"`
+	Channel json.RawMessage `json:"channel"`
+}
+
+func (sv *ChannelStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of ChannelStateValue")
+
+	var u ChannelStateValueJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	sv.BaseHinter = hint.NewBaseHinter(u.Hint)
+	var channel types.Channel
+	if err := channel.DecodeJSON(u.Channel, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Channel = channel
+
+	return nil
+}
+
+type LockStateValueJSONMarshaler struct {
+	hint.BaseHinter
+	Lock types.Lock `json:"lock"`
+}
+
+func (sv LockStateValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(
+		LockStateValueJSONMarshaler(sv),
+	)
+}
+
+type LockStateValueJSONUnmarshaler struct {
+	Hint hint.Hint       `json:"_hint"`
+	Lock json.RawMessage `json:"lock"`
+}
+
+func (sv *LockStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of LockStateValue")
+
+	var u LockStateValueJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	sv.BaseHinter = hint.NewBaseHinter(u.Hint)
+	var lock types.Lock
+	if err := lock.DecodeJSON(u.Lock, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Lock = lock
+
+	return nil
+}
+
+type MerchantStateValueJSONMarshaler struct {
+	hint.BaseHinter
+	Merchant types.Merchant `json:"merchant"`
+}
+
+func (sv MerchantStateValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(
+		MerchantStateValueJSONMarshaler(sv),
+	)
+}
+
+type MerchantStateValueJSONUnmarshaler struct {
+	Hint     hint.Hint       `json:"_hint"`
+	Merchant json.RawMessage `json:"merchant"`
+}
+
+func (sv *MerchantStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of MerchantStateValue")
+
+	var u MerchantStateValueJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	sv.BaseHinter = hint.NewBaseHinter(u.Hint)
+	var merchant types.Merchant
+	if err := merchant.DecodeJSON(u.Merchant, enc); err != nil {
+		return e.Wrap(err)
+	}
+	sv.Merchant = merchant
+
+	return nil
+}
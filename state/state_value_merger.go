@@ -0,0 +1,440 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/payment-model/types"
+	"github.com/pkg/errors"
+)
+
+// EventLogStateValueMerger collects the events appended to one event log in
+// a block. The events are sorted by fact hash before appending, so the result
+// does not depend on the order of the operations being merged.
+type EventLogStateValueMerger struct {
+	*common.BaseStateValueMerger
+	existing *types.EventLog
+	events   []types.Event
+	sync.Mutex
+}
+
+func NewEventLogStateValueMerger(height base.Height, key string, st base.State) *EventLogStateValueMerger {
+	nst := st
+	if st == nil {
+		nst = common.NewBaseState(base.NilHeight, key, nil, nil, nil)
+	}
+
+	s := &EventLogStateValueMerger{
+		BaseStateValueMerger: common.NewBaseStateValueMerger(height, nst.Key(), nst),
+	}
+
+	if nst.Value() != nil {
+		log := nst.Value().(EventLogStateValue).Log //nolint:forcetypeassert //...
+		s.existing = &log
+	}
+
+	return s
+}
+
+func (s *EventLogStateValueMerger) Merge(value base.StateValue, ops util.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	switch t := value.(type) {
+	case AppendEventLogStateValue:
+		if s.existing == nil {
+			log := types.NewEventLog(t.Account)
+			s.existing = &log
+		}
+
+		s.events = append(s.events, t.Event)
+	default:
+		return errors.Errorf("unsupported event log state value, %T", value)
+	}
+
+	s.AddOperation(ops)
+
+	return nil
+}
+
+func (s *EventLogStateValueMerger) CloseValue() (base.State, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	newValue, err := s.closeValue()
+	if err != nil {
+		return nil, errors.WithMessage(err, "close EventLogStateValueMerger")
+	}
+
+	s.BaseStateValueMerger.SetValue(newValue)
+
+	return s.BaseStateValueMerger.CloseValue()
+}
+
+func (s *EventLogStateValueMerger) closeValue() (base.StateValue, error) {
+	if s.existing == nil {
+		return nil, errors.Errorf("empty event log")
+	}
+
+	sort.SliceStable(s.events, func(i, j int) bool {
+		return s.events[i].FactHash < s.events[j].FactHash
+	})
+
+	log := *s.existing
+	log.Append(s.events...)
+
+	return NewEventLogStateValue(log), nil
+}
+
+// DesignStateValueMerger applies the changes of the account settings to one
+// design in a block. Each change carries the setting read by the operation and
+// only the changed currencies, keys and lock of the account are applied, so
+// the operations of the different depositors of a contract and the different
+// currencies of a depositor can be processed together. The changes are applied
+// in the order of the fact hashes and the new accounts over the limit are not
+// added.
+type DesignStateValueMerger struct {
+	*common.BaseStateValueMerger
+	existing *types.Design
+	updates  []designAccountUpdate
+	sync.Mutex
+}
+
+type designAccountUpdate struct {
+	UpdateDesignAccountStateValue
+	factHash string
+}
+
+func NewDesignStateValueMerger(height base.Height, key string, st base.State) *DesignStateValueMerger {
+	nst := st
+	if st == nil {
+		nst = common.NewBaseState(base.NilHeight, key, nil, nil, nil)
+	}
+
+	s := &DesignStateValueMerger{
+		BaseStateValueMerger: common.NewBaseStateValueMerger(height, nst.Key(), nst),
+	}
+
+	if nst.Value() != nil {
+		design := nst.Value().(DesignStateValue).Design //nolint:forcetypeassert //...
+		s.existing = &design
+	}
+
+	return s
+}
+
+func (s *DesignStateValueMerger) Merge(value base.StateValue, ops util.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	switch t := value.(type) {
+	case DesignStateValue:
+		design := t.Design
+		s.existing = &design
+	case UpdateDesignAccountStateValue:
+		s.updates = append(s.updates, designAccountUpdate{UpdateDesignAccountStateValue: t, factHash: ops.String()})
+	default:
+		return errors.Errorf("unsupported design state value, %T", value)
+	}
+
+	s.AddOperation(ops)
+
+	return nil
+}
+
+func (s *DesignStateValueMerger) CloseValue() (base.State, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	newValue, err := s.closeValue()
+	if err != nil {
+		return nil, errors.WithMessage(err, "close DesignStateValueMerger")
+	}
+
+	s.BaseStateValueMerger.SetValue(newValue)
+
+	return s.BaseStateValueMerger.CloseValue()
+}
+
+func (s *DesignStateValueMerger) closeValue() (base.StateValue, error) {
+	if s.existing == nil {
+		return nil, errors.Errorf("empty design")
+	}
+
+	design := types.NewDesign()
+	for _, v := range s.existing.AccountSettings() {
+		if err := design.AddAccountSetting(v); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(s.updates, func(i, j int) bool {
+		return s.updates[i].factHash < s.updates[j].factHash
+	})
+
+	// NOTE the changes of the existing accounts are applied before adding the
+	// new accounts, so the accounts removed in the block make room for them.
+	var adds []designAccountUpdate
+
+	for i := range s.updates {
+		u := s.updates[i]
+
+		setting := design.AccountSetting(u.Account.String())
+		if setting == nil {
+			adds = append(adds, u)
+
+			continue
+		}
+
+		switch nSetting := applySettingChanges(*setting, u.Prev, u.Setting); {
+		case len(nSetting.Items()) < 1:
+			if err := design.RemoveAccountSetting(u.Account); err != nil {
+				return nil, err
+			}
+		default:
+			if err := design.UpdateAccountSetting(nSetting); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// NOTE the new accounts over the limit are not added in the order of fact
+	// hashes; their deposits can be withdrawn without the setting.
+	for i := range adds {
+		u := adds[i]
+
+		nSetting := applySettingChanges(types.NewSettings(u.Account), u.Prev, u.Setting)
+		if len(nSetting.Items()) < 1 || design.CheckAccountsLimit() != nil {
+			continue
+		}
+
+		if err := design.AddAccountSetting(nSetting); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := design.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return NewDesignStateValue(design), nil
+}
+
+// applySettingChanges returns the copy of setting with the changes from prev to
+// next; nil prev is the empty setting and nil next removes the currencies of
+// prev.
+func applySettingChanges(setting types.Setting, prev, next *types.Setting) types.Setting {
+	nSetting := types.NewSettings(setting.Address())
+	for k, v := range setting.Items() {
+		nSetting.SetItem(k, v.TransferLimit, v.StartTime, v.EndTime, v.Duration)
+		nSetting.SetCategoryLimits(k, v.CategoryLimits)
+		nSetting.SetLockUp(k, v.LockUp)
+	}
+	nSetting.SetKeys(setting.LockKey(), setting.RecoveryKey())
+	nSetting.SetLocked(setting.IsLocked())
+
+	prevItems := map[string]types.SettingItem{}
+	if prev != nil {
+		prevItems = prev.Items()
+	}
+
+	if next == nil {
+		for k := range prevItems {
+			nSetting.Remove(k)
+		}
+
+		return nSetting
+	}
+
+	for k := range prevItems {
+		if _, found := next.Items()[k]; !found {
+			nSetting.Remove(k)
+		}
+	}
+
+	for k, v := range next.Items() {
+		if p, found := prevItems[k]; found && equalSettingItem(p, v) {
+			continue
+		}
+
+		nSetting.SetItem(k, v.TransferLimit, v.StartTime, v.EndTime, v.Duration)
+		nSetting.SetCategoryLimits(k, v.CategoryLimits)
+		nSetting.SetLockUp(k, v.LockUp)
+	}
+
+	var prevLockKey, prevRecoveryKey base.Publickey
+	var prevLocked bool
+
+	if prev != nil {
+		prevLockKey, prevRecoveryKey, prevLocked = prev.LockKey(), prev.RecoveryKey(), prev.IsLocked()
+	}
+
+	if !equalPublickey(prevLockKey, next.LockKey()) || !equalPublickey(prevRecoveryKey, next.RecoveryKey()) {
+		nSetting.SetKeys(next.LockKey(), next.RecoveryKey())
+	}
+
+	if prevLocked != next.IsLocked() {
+		nSetting.SetLocked(next.IsLocked())
+	}
+
+	return nSetting
+}
+
+func equalSettingItem(a, b types.SettingItem) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ab, bb)
+}
+
+func equalPublickey(a, b base.Publickey) bool {
+	switch {
+	case a == nil || b == nil:
+		return a == nil && b == nil
+	default:
+		return a.Equal(b)
+	}
+}
+
+// DepositRecordStateValueMerger applies the changes of the amounts of the
+// currencies to one deposit record in a block.
+type DepositRecordStateValueMerger struct {
+	*common.BaseStateValueMerger
+	existing *types.DepositRecord
+	account  base.Address
+	updates  []UpdateDepositRecordStateValue
+	sync.Mutex
+}
+
+func NewDepositRecordStateValueMerger(
+	height base.Height, key string, st base.State,
+) *DepositRecordStateValueMerger {
+	nst := st
+	if st == nil {
+		nst = common.NewBaseState(base.NilHeight, key, nil, nil, nil)
+	}
+
+	s := &DepositRecordStateValueMerger{
+		BaseStateValueMerger: common.NewBaseStateValueMerger(height, nst.Key(), nst),
+	}
+
+	if nst.Value() != nil {
+		record := nst.Value().(DepositRecordStateValue).Record //nolint:forcetypeassert //...
+		s.existing = &record
+	}
+
+	return s
+}
+
+func (s *DepositRecordStateValueMerger) Merge(value base.StateValue, ops util.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	switch t := value.(type) {
+	case DepositRecordStateValue:
+		record := t.Record
+		s.existing = &record
+	case UpdateDepositRecordStateValue:
+		s.account = t.Account
+		s.updates = append(s.updates, t)
+	default:
+		return errors.Errorf("unsupported deposit record state value, %T", value)
+	}
+
+	s.AddOperation(ops)
+
+	return nil
+}
+
+func (s *DepositRecordStateValueMerger) CloseValue() (base.State, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	newValue, err := s.closeValue()
+	if err != nil {
+		return nil, errors.WithMessage(err, "close DepositRecordStateValueMerger")
+	}
+
+	s.BaseStateValueMerger.SetValue(newValue)
+
+	return s.BaseStateValueMerger.CloseValue()
+}
+
+func (s *DepositRecordStateValueMerger) closeValue() (base.StateValue, error) {
+	var record types.DepositRecord
+
+	switch {
+	case s.existing != nil:
+		record = types.NewDepositRecord(s.existing.Address())
+		for k, v := range s.existing.Items() {
+			record.SetItem(k, v.Amount, v.TransferredAt)
+		}
+	case s.account != nil:
+		record = types.NewDepositRecord(s.account)
+	default:
+		return nil, errors.Errorf("empty deposit record")
+	}
+
+	for i := range s.updates {
+		u := s.updates[i]
+
+		amount := common.ZeroBig
+		var transferredAt uint64
+
+		if am := record.Amount(u.Currency); am != nil {
+			amount = *am
+			transferredAt = *record.TransferredAt(u.Currency)
+		}
+
+		amount = amount.Add(u.Add).Sub(u.Deduct)
+		if amount.Compare(common.ZeroBig) < 0 {
+			return nil, errors.Errorf("deposit of currency, %v under zero", u.Currency)
+		}
+
+		// NOTE the latest transferred time is kept regardless of the order of
+		// the operations.
+		if u.TransferredAt != nil && *u.TransferredAt > transferredAt {
+			transferredAt = *u.TransferredAt
+		}
+
+		record.SetItem(u.Currency, amount, transferredAt)
+	}
+
+	if err := record.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return NewDepositRecordStateValue(record), nil
+}
+
+func NewDesignStateMergeValue(key string, value base.StateValue) base.StateMergeValue {
+	return common.NewBaseStateMergeValue(
+		key,
+		value,
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return NewDesignStateValueMerger(height, key, st)
+		},
+	)
+}
+
+func NewDepositRecordStateMergeValue(key string, value base.StateValue) base.StateMergeValue {
+	return common.NewBaseStateMergeValue(
+		key,
+		value,
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return NewDepositRecordStateValueMerger(height, key, st)
+		},
+	)
+}
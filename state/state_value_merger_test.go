@@ -0,0 +1,230 @@
+package state_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/state"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+var designKey = state.DesignStateKey("contract")
+
+func newSetting(account base.Address, limits map[string]int64) types.Setting {
+	s := types.NewSettings(account)
+	for cid, limit := range limits {
+		s.SetItem(cid, common.NewBig(limit), 1, 100, 1)
+	}
+
+	return s
+}
+
+func designState(t *testing.T, settings ...types.Setting) base.State {
+	design := types.NewDesign()
+	for i := range settings {
+		if err := design.AddAccountSetting(settings[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return common.NewBaseState(base.Height(1), designKey, state.NewDesignStateValue(design), nil, []util.Hash{})
+}
+
+func mergeDesign(t *testing.T, st base.State, values ...base.StateValue) (types.Design, error) {
+	t.Helper()
+
+	merger := state.NewDesignStateValueMerger(base.Height(2), designKey, st)
+	for i := range values {
+		if err := merger.Merge(values[i], valuehash.RandomSHA256()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nst, err := merger.CloseValue()
+	if err != nil {
+		return types.Design{}, err
+	}
+
+	return state.GetDesignFromState(nst)
+}
+
+func TestDesignStateValueMergerCurrencies(t *testing.T) {
+	a := ctypes.NewStringAddress("accounta")
+	prev := newSetting(a, map[string]int64{"MCC": 10, "PEN": 20})
+
+	// NOTE the both updates are made from the same previous setting.
+	update := newSetting(a, map[string]int64{"MCC": 11, "PEN": 20})
+	remove := newSetting(a, map[string]int64{"MCC": 10})
+
+	design, err := mergeDesign(t, designState(t, prev),
+		state.NewSetDesignAccountStateValue(&prev, update),
+		state.NewSetDesignAccountStateValue(&prev, remove),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setting := design.AccountSetting(a.String())
+	if setting == nil {
+		t.Fatal("account removed")
+	}
+
+	if i := setting.TransferLimit("MCC"); i == nil || !i.Equal(common.NewBig(11)) {
+		t.Errorf("limit of MCC, %v", i)
+	}
+
+	if i := setting.TransferLimit("PEN"); i != nil {
+		t.Errorf("limit of PEN, %v not removed", i)
+	}
+}
+
+func TestDesignStateValueMergerAccounts(t *testing.T) {
+	a, b := ctypes.NewStringAddress("accounta"), ctypes.NewStringAddress("accountb")
+	prev := newSetting(a, map[string]int64{"MCC": 10})
+
+	design, err := mergeDesign(t, designState(t, prev),
+		state.NewRemoveDesignAccountStateValue(prev),
+		state.NewSetDesignAccountStateValue(nil, newSetting(b, map[string]int64{"MCC": 30})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if design.AccountSetting(a.String()) != nil {
+		t.Error("account not removed")
+	}
+
+	if setting := design.AccountSetting(b.String()); setting == nil {
+		t.Error("account not added")
+	}
+}
+
+func TestDesignStateValueMergerLocked(t *testing.T) {
+	a := ctypes.NewStringAddress("accounta")
+	lockKey := base.NewMPrivatekey().Publickey()
+	recoveryKey := base.NewMPrivatekey().Publickey()
+
+	prev := newSetting(a, map[string]int64{"MCC": 10})
+	prev.SetKeys(lockKey, recoveryKey)
+
+	locked := newSetting(a, map[string]int64{"MCC": 10})
+	locked.SetKeys(lockKey, recoveryKey)
+	locked.SetLocked(true)
+
+	limited := newSetting(a, map[string]int64{"MCC": 20})
+	limited.SetKeys(lockKey, recoveryKey)
+
+	design, err := mergeDesign(t, designState(t, prev),
+		state.NewSetDesignAccountStateValue(&prev, locked),
+		state.NewSetDesignAccountStateValue(&prev, limited),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setting := design.AccountSetting(a.String())
+
+	if !setting.IsLocked() {
+		t.Error("lock lost")
+	}
+
+	if i := setting.TransferLimit("MCC"); !i.Equal(common.NewBig(20)) {
+		t.Errorf("limit of MCC, %v", i)
+	}
+}
+
+func TestDesignStateValueMergerAccountsLimit(t *testing.T) {
+	settings := make([]types.Setting, 999)
+	for i := range settings {
+		settings[i] = newSetting(ctypes.NewStringAddress(fmt.Sprintf("account%d", i)), map[string]int64{"MCC": 10})
+	}
+
+	x, y := ctypes.NewStringAddress("accountx"), ctypes.NewStringAddress("accounty")
+	values := []base.StateValue{
+		state.NewSetDesignAccountStateValue(nil, newSetting(x, map[string]int64{"MCC": 10})),
+		state.NewSetDesignAccountStateValue(nil, newSetting(y, map[string]int64{"MCC": 10})),
+	}
+	hashes := []util.Hash{valuehash.RandomSHA256(), valuehash.RandomSHA256()}
+
+	// NOTE the account of the lower fact hash is added.
+	added, rejected := x, y
+	if hashes[1].String() < hashes[0].String() {
+		added, rejected = y, x
+	}
+
+	merger := state.NewDesignStateValueMerger(base.Height(2), designKey, designState(t, settings...))
+	for i := range values {
+		if err := merger.Merge(values[i], hashes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nst, err := merger.CloseValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	design, _ := state.GetDesignFromState(nst)
+
+	switch {
+	case len(design.AccountSettings()) != 1000:
+		t.Errorf("%d accounts", len(design.AccountSettings()))
+	case design.AccountSetting(added.String()) == nil:
+		t.Error("account of lower fact hash not added")
+	case design.AccountSetting(rejected.String()) != nil:
+		t.Error("account over limit added")
+	}
+
+	// NOTE the removed account makes room for the new accounts.
+	design, err = mergeDesign(t, designState(t, settings...),
+		state.NewSetDesignAccountStateValue(nil, newSetting(x, map[string]int64{"MCC": 10})),
+		state.NewSetDesignAccountStateValue(nil, newSetting(y, map[string]int64{"MCC": 10})),
+		state.NewRemoveDesignAccountStateValue(settings[0]),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if design.AccountSetting(x.String()) == nil || design.AccountSetting(y.String()) == nil {
+		t.Error("new accounts not added after removal")
+	}
+}
+
+func TestDesignStateValueMergerOrder(t *testing.T) {
+	a := ctypes.NewStringAddress("accounta")
+	prev := newSetting(a, map[string]int64{"MCC": 10})
+
+	values := []base.StateValue{
+		state.NewSetDesignAccountStateValue(&prev, newSetting(a, map[string]int64{"MCC": 11})),
+		state.NewSetDesignAccountStateValue(&prev, newSetting(a, map[string]int64{"MCC": 12})),
+	}
+	hashes := []util.Hash{valuehash.RandomSHA256(), valuehash.RandomSHA256()}
+
+	var limits []string
+
+	for _, order := range [][2]int{{0, 1}, {1, 0}} {
+		merger := state.NewDesignStateValueMerger(base.Height(2), designKey, designState(t, prev))
+		for _, i := range order {
+			if err := merger.Merge(values[i], hashes[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		nst, err := merger.CloseValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		design, _ := state.GetDesignFromState(nst)
+		limits = append(limits, design.AccountSetting(a.String()).TransferLimit("MCC").String())
+	}
+
+	if limits[0] != limits[1] {
+		t.Errorf("merged by the order of operations, %v", limits)
+	}
+}
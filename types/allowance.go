@@ -0,0 +1,70 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+)
+
+// TransferAllowance is the transfer rules of a currency in the setting of an
+// account, evaluated at a time with the same rules of the transfer processors:
+// the transfer is allowed in the window from the start time to the end time,
+// after the duration from the last transfer, up to the transfer limit and the
+// deposit, unless the setting is locked.
+type TransferAllowance struct {
+	// NextTransferAt is the earliest time of the next transfer; 0 when no
+	// more transfer is allowed in the window.
+	NextTransferAt uint64 `json:"next_transfer_at"`
+	WindowOpen     bool   `json:"window_open"`
+	// MaxTransferable is the maximum amount which can be transferred now.
+	MaxTransferable common.Big `json:"max_transferable"`
+	// ExpiresIn is the seconds until the end time.
+	ExpiresIn uint64 `json:"expires_in"`
+}
+
+// NewTransferAllowance evaluates the transfer rules of cid at now; false when
+// the setting has no item of cid.
+func NewTransferAllowance(setting Setting, record DepositRecord, cid string, now uint64) (TransferAllowance, bool) {
+	item, found := setting.Items()[cid]
+	if !found {
+		return TransferAllowance{}, false
+	}
+
+	a := TransferAllowance{
+		WindowOpen:      item.StartTime <= now && now <= item.EndTime,
+		MaxTransferable: common.ZeroBig,
+	}
+
+	if item.EndTime > now {
+		a.ExpiresIn = item.EndTime - now
+	}
+
+	lastTime := record.TransferredAt(cid)
+	amount := record.Amount(cid)
+
+	if lastTime == nil || amount == nil || !amount.OverZero() || setting.IsLocked() {
+		return a, true
+	}
+
+	next := item.StartTime
+	if cool := *lastTime + item.Duration; cool > next {
+		next = cool
+	}
+
+	if next < now {
+		next = now
+	}
+
+	if next > item.EndTime {
+		return a, true
+	}
+
+	a.NextTransferAt = next
+
+	if next == now {
+		a.MaxTransferable = *amount
+		if item.TransferLimit.Compare(*amount) < 0 {
+			a.MaxTransferable = item.TransferLimit
+		}
+	}
+
+	return a, true
+}
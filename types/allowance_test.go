@@ -0,0 +1,65 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestTransferAllowance(t *testing.T) {
+	account := ctypes.NewStringAddress("account")
+
+	setting := types.NewSettings(account)
+	setting.SetItem("MCC", common.NewBig(10), 100, 200, 20)
+
+	locked := setting
+	locked.SetLocked(true)
+
+	newRecord := func(amount int64, lastTime uint64) types.DepositRecord {
+		record := types.NewDepositRecord(account)
+		record.SetItem("MCC", common.NewBig(amount), lastTime)
+
+		return record
+	}
+
+	for name, c := range map[string]struct {
+		setting   types.Setting
+		record    types.DepositRecord
+		now       uint64
+		next      uint64
+		open      bool
+		max       int64
+		expiresIn uint64
+	}{
+		"before start":         {setting, newRecord(50, 10), 50, 100, false, 0, 150},
+		"in duration":          {setting, newRecord(50, 150), 160, 170, true, 0, 40},
+		"after duration":       {setting, newRecord(50, 150), 170, 170, true, 10, 30},
+		"under limit":          {setting, newRecord(5, 150), 180, 180, true, 5, 20},
+		"duration over end":    {setting, newRecord(50, 185), 190, 0, true, 0, 10},
+		"at end":               {setting, newRecord(50, 150), 200, 200, true, 10, 0},
+		"after end":            {setting, newRecord(50, 150), 250, 0, false, 0, 0},
+		"empty deposit":        {setting, newRecord(0, 150), 170, 0, true, 0, 30},
+		"locked":               {locked, newRecord(50, 150), 170, 0, true, 0, 30},
+		"deposit of other cid": {setting, types.NewDepositRecord(account), 170, 0, true, 0, 30},
+	} {
+		a, found := types.NewTransferAllowance(c.setting, c.record, "MCC", c.now)
+		switch {
+		case !found:
+			t.Errorf("%s: not found", name)
+		case a.NextTransferAt != c.next:
+			t.Errorf("%s: next transfer at %d, not %d", name, a.NextTransferAt, c.next)
+		case a.WindowOpen != c.open:
+			t.Errorf("%s: window open %v", name, a.WindowOpen)
+		case !a.MaxTransferable.Equal(common.NewBig(c.max)):
+			t.Errorf("%s: max transferable %v, not %d", name, a.MaxTransferable, c.max)
+		case a.ExpiresIn != c.expiresIn:
+			t.Errorf("%s: expires in %d, not %d", name, a.ExpiresIn, c.expiresIn)
+		}
+	}
+
+	if _, found := types.NewTransferAllowance(setting, newRecord(50, 150), "PEN", 170); found {
+		t.Error("allowance of currency not in setting")
+	}
+}
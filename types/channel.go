@@ -0,0 +1,121 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/pkg/errors"
+)
+
+var ChannelHint = hint.MustNewHint("mitum-payment-channel-v0.0.1")
+
+// Channel is the payment channel of a depositor for one currency. The
+// receiver redeems the vouchers signed by the depositor until the channel is
+// closed; the nonce is increased whenever the channel is opened again, so the
+// vouchers of the previous channel can not be redeemed.
+type Channel struct {
+	hint.BaseHinter
+	depositor     base.Address
+	receiver      base.Address
+	currency      ctypes.CurrencyID
+	capacity      common.Big
+	claimed       common.Big
+	nonce         uint64
+	disputeWindow uint64
+	closingAt     uint64
+}
+
+func NewChannel(
+	depositor, receiver base.Address,
+	currency ctypes.CurrencyID,
+	capacity, claimed common.Big,
+	nonce, disputeWindow, closingAt uint64,
+) Channel {
+	return Channel{
+		BaseHinter:    hint.NewBaseHinter(ChannelHint),
+		depositor:     depositor,
+		receiver:      receiver,
+		currency:      currency,
+		capacity:      capacity,
+		claimed:       claimed,
+		nonce:         nonce,
+		disputeWindow: disputeWindow,
+		closingAt:     closingAt,
+	}
+}
+
+func (c Channel) IsValid([]byte) error {
+	if err := util.CheckIsValiders(nil, false,
+		c.BaseHinter,
+		c.depositor,
+		c.receiver,
+		c.currency,
+		c.capacity,
+		c.claimed,
+	); err != nil {
+		return err
+	}
+
+	if c.depositor.Equal(c.receiver) {
+		return errors.Errorf("depositor %v is same with receiver", c.depositor)
+	}
+
+	if c.claimed.Compare(c.capacity) > 0 {
+		return errors.Errorf("claimed, %v over capacity, %v", c.claimed, c.capacity)
+	}
+
+	return nil
+}
+
+func (c Channel) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		c.depositor.Bytes(),
+		c.receiver.Bytes(),
+		c.currency.Bytes(),
+		c.capacity.Bytes(),
+		c.claimed.Bytes(),
+		util.Uint64ToBytes(c.nonce),
+		util.Uint64ToBytes(c.disputeWindow),
+		util.Uint64ToBytes(c.closingAt),
+	)
+}
+
+func (c Channel) Depositor() base.Address {
+	return c.depositor
+}
+
+func (c Channel) Receiver() base.Address {
+	return c.receiver
+}
+
+func (c Channel) Currency() ctypes.CurrencyID {
+	return c.currency
+}
+
+func (c Channel) Capacity() common.Big {
+	return c.capacity
+}
+
+func (c Channel) Claimed() common.Big {
+	return c.claimed
+}
+
+func (c Channel) Nonce() uint64 {
+	return c.nonce
+}
+
+func (c Channel) DisputeWindow() uint64 {
+	return c.disputeWindow
+}
+
+// ClosingAt returns the time when the channel is closed; zero means the
+// depositor has not requested closing.
+func (c Channel) ClosingAt() uint64 {
+	return c.closingAt
+}
+
+func (c Channel) IsClosed(now uint64) bool {
+	return c.closingAt > 0 && c.closingAt <= now
+}
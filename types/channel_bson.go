@@ -0,0 +1,57 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (c Channel) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bson.M{
+		"_hint":          c.Hint().String(),
+		"depositor":      c.depositor,
+		"receiver":       c.receiver,
+		"currency":       c.currency,
+		"capacity":       c.capacity,
+		"claimed":        c.claimed,
+		"nonce":          c.nonce,
+		"dispute_window": c.disputeWindow,
+		"closing_at":     c.closingAt,
+	})
+}
+
+type ChannelBSONUnmarshaler struct {
+	Hint          string     `bson:"_hint"`
+	Depositor     string     `bson:"depositor"`
+	Receiver      string     `bson:"receiver"`
+	Currency      string     `bson:"currency"`
+	Capacity      common.Big `bson:"capacity"`
+	Claimed       common.Big `bson:"claimed"`
+	Nonce         uint64     `bson:"nonce"`
+	DisputeWindow uint64     `bson:"dispute_window"`
+	ClosingAt     uint64     `bson:"closing_at"`
+}
+
+func (c *Channel) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of Channel")
+
+	var u ChannelBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := c.unpack(enc, ht,
+		u.Depositor, u.Receiver, u.Currency, u.Capacity, u.Claimed, u.Nonce, u.DisputeWindow, u.ClosingAt,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
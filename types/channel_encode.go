@@ -0,0 +1,40 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+func (c *Channel) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	da, ra, cid string,
+	capacity, claimed common.Big,
+	nonce, disputeWindow, closingAt uint64,
+) error {
+	c.BaseHinter = hint.NewBaseHinter(ht)
+
+	depositor, err := base.DecodeAddress(da, enc)
+	if err != nil {
+		return err
+	}
+	c.depositor = depositor
+
+	receiver, err := base.DecodeAddress(ra, enc)
+	if err != nil {
+		return err
+	}
+	c.receiver = receiver
+
+	c.currency = ctypes.CurrencyID(cid)
+	c.capacity = capacity
+	c.claimed = claimed
+	c.nonce = nonce
+	c.disputeWindow = disputeWindow
+	c.closingAt = closingAt
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+type ChannelJSONMarshaler struct {
+	hint.BaseHinter
+	Depositor     base.Address      `json:"depositor"`
+	Receiver      base.Address      `json:"receiver"`
+	Currency      ctypes.CurrencyID `json:"currency"`
+	Capacity      common.Big        `json:"capacity"`
+	Claimed       common.Big        `json:"claimed"`
+	Nonce         uint64            `json:"nonce"`
+	DisputeWindow uint64            `json:"dispute_window"`
+	ClosingAt     uint64            `json:"closing_at"`
+}
+
+func (c Channel) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(ChannelJSONMarshaler{
+		BaseHinter:    c.BaseHinter,
+		Depositor:     c.depositor,
+		Receiver:      c.receiver,
+		Currency:      c.currency,
+		Capacity:      c.capacity,
+		Claimed:       c.claimed,
+		Nonce:         c.nonce,
+		DisputeWindow: c.disputeWindow,
+		ClosingAt:     c.closingAt,
+	})
+}
+
+type ChannelJSONUnmarshaler struct {
+	Hint          hint.Hint  `json:"_hint"`
+	Depositor     string     `json:"depositor"`
+	Receiver      string     `json:"receiver"`
+	Currency      string     `json:"currency"`
+	Capacity      common.Big `json:"capacity"`
+	Claimed       common.Big `json:"claimed"`
+	Nonce         uint64     `json:"nonce"`
+	DisputeWindow uint64     `json:"dispute_window"`
+	ClosingAt     uint64     `json:"closing_at"`
+}
+
+func (c *Channel) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of Channel")
+
+	var u ChannelJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := c.unpack(enc, u.Hint,
+		u.Depositor, u.Receiver, u.Currency, u.Capacity, u.Claimed, u.Nonce, u.DisputeWindow, u.ClosingAt,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -72,6 +72,16 @@ func (de Design) AccountSetting(account string) *Setting {
 	return &v
 }
 
+// CheckAccountsLimit returns error when no more account can be added.
+func (de Design) CheckAccountsLimit() error {
+	if len(de.settings) >= maxAccounts {
+		return common.ErrValOOR.Wrap(
+			errors.Errorf("accounts over allowed, %d >= %d", len(de.settings), maxAccounts))
+	}
+
+	return nil
+}
+
 func (de *Design) AddAccountSetting(setting Setting) error {
 	de.settings[setting.Address().String()] = setting
 
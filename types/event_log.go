@@ -0,0 +1,185 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var EventLogHint = hint.MustNewHint("mitum-payment-event-log-v0.0.1")
+
+var MaxEventLogSize = 100
+
+const (
+	EventTypeDeposit             = "deposit"
+	EventTypeWithdraw            = "withdraw"
+	EventTypeWithdrawPenalty     = "withdraw_penalty"
+	EventTypeTransfer            = "transfer"
+	EventTypeUpdateSetting       = "update_setting"
+	EventTypeClaimVoucher        = "claim_voucher"
+	EventTypeLockTransfer        = "lock_transfer"
+	EventTypeClaimTransfer       = "claim_transfer"
+	EventTypeRefundTransfer      = "refund_transfer"
+	EventTypeRegisterDepositKeys = "register_deposit_keys"
+	EventTypeLockDeposit         = "lock_deposit"
+	EventTypeUnlockDeposit       = "unlock_deposit"
+)
+
+// EventLog keeps the latest events of an account in a contract account.
+// Older events are dropped when the log exceeds MaxEventLogSize, but the
+// events of the latest height are kept until they are indexed by the digest.
+type EventLog struct {
+	hint.BaseHinter
+	address  base.Address
+	sequence uint64
+	events   []Event
+}
+
+func NewEventLog(address base.Address) EventLog {
+	return EventLog{
+		BaseHinter: hint.NewBaseHinter(EventLogHint),
+		address:    address,
+		events:     []Event{},
+	}
+}
+
+func (l EventLog) IsValid([]byte) error {
+	if err := l.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if err := util.CheckIsValiders(nil, false,
+		l.address,
+	); err != nil {
+		return err
+	}
+
+	if n := len(l.events); n > MaxEventLogSize && l.events[0].Height != l.events[n-1].Height {
+		return errors.Errorf("events over allowed, %d > %d", n, MaxEventLogSize)
+	}
+
+	for i := range l.events {
+		if err := l.events[i].IsValid(nil); err != nil {
+			return err
+		}
+
+		if l.events[i].Sequence >= l.sequence {
+			return errors.Errorf("event sequence, %d not less than log sequence, %d", l.events[i].Sequence, l.sequence)
+		}
+	}
+
+	return nil
+}
+
+func (l EventLog) Bytes() []byte {
+	var evs []byte
+	if l.events != nil {
+		b, _ := json.Marshal(l.events)
+		evs = valuehash.NewSHA256(b).Bytes()
+	} else {
+		evs = []byte{}
+	}
+
+	return util.ConcatBytesSlice(
+		l.address.Bytes(),
+		util.Uint64ToBytes(l.sequence),
+		evs,
+	)
+}
+
+func (l EventLog) Address() base.Address {
+	return l.address
+}
+
+// Sequence returns the sequence which will be given to the next event.
+func (l EventLog) Sequence() uint64 {
+	return l.sequence
+}
+
+func (l EventLog) Events() []Event {
+	return l.events
+}
+
+// Append numbers the given events in order and appends them, dropping the
+// oldest events over MaxEventLogSize. The events of the latest height are not
+// dropped, so the log can be over MaxEventLogSize when more events are
+// appended in one block.
+func (l *EventLog) Append(evs ...Event) {
+	nevs := make([]Event, len(l.events), len(l.events)+len(evs))
+	copy(nevs, l.events)
+	l.events = nevs
+
+	for i := range evs {
+		ev := evs[i]
+		ev.Sequence = l.sequence
+		l.sequence++
+
+		l.events = append(l.events, ev)
+	}
+
+	n := len(l.events)
+	if n <= MaxEventLogSize {
+		return
+	}
+
+	drop := n - MaxEventLogSize
+	for drop > 0 && l.events[drop-1].Height == l.events[n-1].Height {
+		drop--
+	}
+
+	l.events = append([]Event{}, l.events[drop:]...)
+}
+
+type Event struct {
+	Sequence     uint64     `bson:"sequence" json:"sequence"`
+	Type         string     `bson:"type" json:"type"`
+	Currency     string     `bson:"currency" json:"currency"`
+	Amount       common.Big `bson:"amount" json:"amount"`
+	Counterparty string     `bson:"counterparty" json:"counterparty"`
+	FactHash     string     `bson:"fact_hash" json:"fact_hash"`
+	ProposedAt   uint64     `bson:"proposed_at" json:"proposed_at"`
+	Height       int64      `bson:"height" json:"height"`
+}
+
+func NewEvent(
+	typ, cid string, am common.Big, counterparty string, factHash util.Hash, proposedAt uint64, height base.Height,
+) Event {
+	return Event{
+		Type:         typ,
+		Currency:     cid,
+		Amount:       am,
+		Counterparty: counterparty,
+		FactHash:     factHash.String(),
+		ProposedAt:   proposedAt,
+		Height:       height.Int64(),
+	}
+}
+
+// IsEventType checks whether t is the known event type.
+func IsEventType(t string) bool {
+	switch t {
+	case EventTypeDeposit, EventTypeWithdraw, EventTypeTransfer, EventTypeUpdateSetting,
+		EventTypeClaimVoucher, EventTypeLockTransfer, EventTypeClaimTransfer, EventTypeRefundTransfer,
+		EventTypeRegisterDepositKeys, EventTypeLockDeposit, EventTypeUnlockDeposit, EventTypeWithdrawPenalty:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e Event) IsValid([]byte) error {
+	if !IsEventType(e.Type) {
+		return errors.Errorf("unknown event type, %q", e.Type)
+	}
+
+	if len(e.FactHash) < 1 {
+		return errors.Errorf("empty fact hash of event")
+	}
+
+	return util.CheckIsValiders(nil, false, e.Amount)
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (l EventLog) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bson.M{
+		"_hint":    l.Hint().String(),
+		"address":  l.address,
+		"sequence": l.sequence,
+		"events":   l.events,
+	})
+}
+
+type EventLogBSONUnmarshaler struct {
+	Hint     string  `bson:"_hint"`
+	Address  string  `bson:"address"`
+	Sequence uint64  `bson:"sequence"`
+	Events   []Event `bson:"events"`
+}
+
+func (l *EventLog) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of EventLog")
+
+	var u EventLogBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := l.unpack(enc, ht, u.Address, u.Sequence, u.Events); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
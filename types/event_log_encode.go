@@ -0,0 +1,30 @@
+package types
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+func (l *EventLog) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	addr string,
+	sequence uint64,
+	events []Event,
+) error {
+	l.BaseHinter = hint.NewBaseHinter(ht)
+	address, err := base.DecodeAddress(addr, enc)
+	if err != nil {
+		return err
+	}
+	l.address = address
+	l.sequence = sequence
+
+	if events == nil {
+		events = []Event{}
+	}
+	l.events = events
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+type EventLogJSONMarshaler struct {
+	hint.BaseHinter
+	Address  base.Address `json:"address"`
+	Sequence uint64       `json:"sequence"`
+	Events   []Event      `json:"events"`
+}
+
+func (l EventLog) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(EventLogJSONMarshaler{
+		BaseHinter: l.BaseHinter,
+		Address:    l.address,
+		Sequence:   l.sequence,
+		Events:     l.events,
+	})
+}
+
+type EventLogJSONUnmarshaler struct {
+	Hint     hint.Hint `json:"_hint"`
+	Address  string    `json:"address"`
+	Sequence uint64    `json:"sequence"`
+	Events   []Event   `json:"events"`
+}
+
+func (l *EventLog) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of EventLog")
+
+	var u EventLogJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := l.unpack(enc, u.Hint, u.Address, u.Sequence, u.Events); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func newEvents(n int, height base.Height) []types.Event {
+	evs := make([]types.Event, n)
+	for i := range evs {
+		evs[i] = types.NewEvent(
+			types.EventTypeClaimTransfer, "MCC", common.NewBig(1), "receiver", valuehash.RandomSHA256(), 1, height)
+	}
+
+	return evs
+}
+
+func TestEventLogAppend(t *testing.T) {
+	log := types.NewEventLog(ctypes.NewStringAddress("accounta"))
+
+	log.Append(newEvents(types.MaxEventLogSize, base.Height(1))...)
+	log.Append(newEvents(10, base.Height(2))...)
+
+	evs := log.Events()
+
+	switch {
+	case len(evs) != types.MaxEventLogSize:
+		t.Errorf("%d events", len(evs))
+	case evs[0].Sequence != 10:
+		t.Errorf("oldest sequence %d", evs[0].Sequence)
+	case log.Sequence() != uint64(types.MaxEventLogSize+10):
+		t.Errorf("sequence %d", log.Sequence())
+	}
+
+	if err := log.IsValid(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEventLogAppendOverSizeInHeight(t *testing.T) {
+	log := types.NewEventLog(ctypes.NewStringAddress("accounta"))
+
+	log.Append(newEvents(10, base.Height(1))...)
+	log.Append(newEvents(types.MaxEventLogSize+1, base.Height(2))...)
+
+	evs := log.Events()
+	if len(evs) != types.MaxEventLogSize+1 {
+		t.Fatalf("%d events", len(evs))
+	}
+
+	for i := range evs {
+		if evs[i].Height != 2 {
+			t.Fatalf("event of height %d kept", evs[i].Height)
+		}
+	}
+
+	if err := log.IsValid(nil); err != nil {
+		t.Error(err)
+	}
+
+	// NOTE the events of the previous height are dropped in the next height.
+	log.Append(newEvents(1, base.Height(3))...)
+
+	if i := len(log.Events()); i != types.MaxEventLogSize {
+		t.Errorf("%d events", i)
+	}
+}
@@ -0,0 +1,194 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/pkg/errors"
+)
+
+var LockHint = hint.MustNewHint("mitum-payment-lock-v0.0.1")
+
+type LockStatus string
+
+const (
+	LockStatusLocked   LockStatus = "locked"
+	LockStatusClaimed  LockStatus = "claimed"
+	LockStatusRefunded LockStatus = "refunded"
+)
+
+func (s LockStatus) IsValid([]byte) error {
+	switch s {
+	case LockStatusLocked, LockStatusClaimed, LockStatusRefunded:
+		return nil
+	default:
+		return errors.Errorf("unknown lock status, %q", s)
+	}
+}
+
+func (s LockStatus) String() string {
+	return string(s)
+}
+
+// Lock is the hash time-locked transfer reserved from the deposit of sender.
+// The receiver claims the amount with the preimage of hash lock before the
+// timeout; after the timeout the sender can get the amount back.
+type Lock struct {
+	hint.BaseHinter
+	sender   base.Address
+	receiver base.Address
+	currency ctypes.CurrencyID
+	amount   common.Big
+	hashLock string
+	timeout  uint64
+	preimage string
+	status   LockStatus
+}
+
+func NewLock(
+	sender, receiver base.Address,
+	currency ctypes.CurrencyID,
+	amount common.Big,
+	hashLock string,
+	timeout uint64,
+	preimage string,
+	status LockStatus,
+) Lock {
+	return Lock{
+		BaseHinter: hint.NewBaseHinter(LockHint),
+		sender:     sender,
+		receiver:   receiver,
+		currency:   currency,
+		amount:     amount,
+		hashLock:   hashLock,
+		timeout:    timeout,
+		preimage:   preimage,
+		status:     status,
+	}
+}
+
+func (l Lock) IsValid([]byte) error {
+	if err := util.CheckIsValiders(nil, false,
+		l.BaseHinter,
+		l.sender,
+		l.receiver,
+		l.currency,
+		l.amount,
+		l.status,
+	); err != nil {
+		return err
+	}
+
+	if l.sender.Equal(l.receiver) {
+		return errors.Errorf("sender %v is same with receiver", l.sender)
+	}
+
+	if !l.amount.OverZero() {
+		return errors.Errorf("amount must be over zero")
+	}
+
+	if err := IsValidHashLock(l.hashLock); err != nil {
+		return err
+	}
+
+	switch {
+	case l.status == LockStatusClaimed:
+		if err := VerifyPreimage(l.hashLock, l.preimage); err != nil {
+			return err
+		}
+	case len(l.preimage) > 0:
+		return errors.Errorf("preimage of %v lock must be empty", l.status)
+	}
+
+	return nil
+}
+
+func (l Lock) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		l.sender.Bytes(),
+		l.receiver.Bytes(),
+		l.currency.Bytes(),
+		l.amount.Bytes(),
+		[]byte(l.hashLock),
+		util.Uint64ToBytes(l.timeout),
+		[]byte(l.preimage),
+		[]byte(l.status),
+	)
+}
+
+func (l Lock) Sender() base.Address {
+	return l.sender
+}
+
+func (l Lock) Receiver() base.Address {
+	return l.receiver
+}
+
+func (l Lock) Currency() ctypes.CurrencyID {
+	return l.currency
+}
+
+func (l Lock) Amount() common.Big {
+	return l.amount
+}
+
+func (l Lock) HashLock() string {
+	return l.hashLock
+}
+
+func (l Lock) Timeout() uint64 {
+	return l.timeout
+}
+
+// Preimage is revealed when the receiver claims the lock.
+func (l Lock) Preimage() string {
+	return l.preimage
+}
+
+func (l Lock) Status() LockStatus {
+	return l.status
+}
+
+func (l Lock) IsExpired(now uint64) bool {
+	return l.timeout <= now
+}
+
+// IsValidHashLock checks the hash lock is the hex encoded sha256 digest.
+func IsValidHashLock(hashLock string) error {
+	b, err := hex.DecodeString(hashLock)
+	if err != nil {
+		return errors.Errorf("invalid hash lock, %q: %v", hashLock, err)
+	} else if len(b) != sha256.Size {
+		return errors.Errorf("invalid hash lock, %q: length must be %d bytes", hashLock, sha256.Size)
+	}
+
+	return nil
+}
+
+// VerifyPreimage checks the sha256 digest of the hex encoded preimage matches
+// with the hash lock.
+func VerifyPreimage(hashLock, preimage string) error {
+	hl, err := hex.DecodeString(hashLock)
+	if err != nil {
+		return errors.Errorf("invalid hash lock, %q: %v", hashLock, err)
+	}
+
+	pi, err := hex.DecodeString(preimage)
+	if err != nil {
+		return errors.Errorf("invalid preimage, %q: %v", preimage, err)
+	} else if len(pi) < 1 {
+		return errors.Errorf("empty preimage")
+	}
+
+	if h := sha256.Sum256(pi); !bytes.Equal(h[:], hl) {
+		return errors.Errorf("preimage does not match with hash lock, %q", hashLock)
+	}
+
+	return nil
+}
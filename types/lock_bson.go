@@ -0,0 +1,57 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (l Lock) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bson.M{
+		"_hint":     l.Hint().String(),
+		"sender":    l.sender,
+		"receiver":  l.receiver,
+		"currency":  l.currency,
+		"amount":    l.amount,
+		"hash_lock": l.hashLock,
+		"timeout":   l.timeout,
+		"preimage":  l.preimage,
+		"status":    l.status.String(),
+	})
+}
+
+type LockBSONUnmarshaler struct {
+	Hint     string     `bson:"_hint"`
+	Sender   string     `bson:"sender"`
+	Receiver string     `bson:"receiver"`
+	Currency string     `bson:"currency"`
+	Amount   common.Big `bson:"amount"`
+	HashLock string     `bson:"hash_lock"`
+	Timeout  uint64     `bson:"timeout"`
+	Preimage string     `bson:"preimage"`
+	Status   string     `bson:"status"`
+}
+
+func (l *Lock) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of Lock")
+
+	var u LockBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := l.unpack(enc, ht,
+		u.Sender, u.Receiver, u.Currency, u.Amount, u.HashLock, u.Timeout, u.Preimage, u.Status,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
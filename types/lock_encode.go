@@ -0,0 +1,42 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+func (l *Lock) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	sa, ra, cid string,
+	amount common.Big,
+	hashLock string,
+	timeout uint64,
+	preimage, status string,
+) error {
+	l.BaseHinter = hint.NewBaseHinter(ht)
+
+	sender, err := base.DecodeAddress(sa, enc)
+	if err != nil {
+		return err
+	}
+	l.sender = sender
+
+	receiver, err := base.DecodeAddress(ra, enc)
+	if err != nil {
+		return err
+	}
+	l.receiver = receiver
+
+	l.currency = ctypes.CurrencyID(cid)
+	l.amount = amount
+	l.hashLock = hashLock
+	l.timeout = timeout
+	l.preimage = preimage
+	l.status = LockStatus(status)
+
+	return nil
+}
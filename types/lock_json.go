@@ -0,0 +1,65 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+type LockJSONMarshaler struct {
+	hint.BaseHinter
+	Sender   base.Address      `json:"sender"`
+	Receiver base.Address      `json:"receiver"`
+	Currency ctypes.CurrencyID `json:"currency"`
+	Amount   common.Big        `json:"amount"`
+	HashLock string            `json:"hash_lock"`
+	Timeout  uint64            `json:"timeout"`
+	Preimage string            `json:"preimage"`
+	Status   LockStatus        `json:"status"`
+}
+
+func (l Lock) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(LockJSONMarshaler{
+		BaseHinter: l.BaseHinter,
+		Sender:     l.sender,
+		Receiver:   l.receiver,
+		Currency:   l.currency,
+		Amount:     l.amount,
+		HashLock:   l.hashLock,
+		Timeout:    l.timeout,
+		Preimage:   l.preimage,
+		Status:     l.status,
+	})
+}
+
+type LockJSONUnmarshaler struct {
+	Hint     hint.Hint  `json:"_hint"`
+	Sender   string     `json:"sender"`
+	Receiver string     `json:"receiver"`
+	Currency string     `json:"currency"`
+	Amount   common.Big `json:"amount"`
+	HashLock string     `json:"hash_lock"`
+	Timeout  uint64     `json:"timeout"`
+	Preimage string     `json:"preimage"`
+	Status   string     `json:"status"`
+}
+
+func (l *Lock) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of Lock")
+
+	var u LockJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := l.unpack(enc, u.Hint,
+		u.Sender, u.Receiver, u.Currency, u.Amount, u.HashLock, u.Timeout, u.Preimage, u.Status,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package types_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestVerifyPreimage(t *testing.T) {
+	h := sha256.Sum256([]byte("preimage"))
+	hashLock := hex.EncodeToString(h[:])
+
+	if err := types.VerifyPreimage(hashLock, hex.EncodeToString([]byte("preimage"))); err != nil {
+		t.Error(err)
+	}
+
+	for name, preimage := range map[string]string{
+		"other preimage": hex.EncodeToString([]byte("other")),
+		"empty":          "",
+		"not hex":        "preimage",
+		"hash lock":      hashLock,
+	} {
+		if err := types.VerifyPreimage(hashLock, preimage); err == nil {
+			t.Errorf("%s: preimage verified", name)
+		}
+	}
+
+	if err := types.VerifyPreimage("hashlock", hex.EncodeToString([]byte("preimage"))); err == nil {
+		t.Error("invalid hash lock verified")
+	}
+}
+
+func TestLockIsExpired(t *testing.T) {
+	h := sha256.Sum256([]byte("preimage"))
+	lock := types.NewLock(
+		ctypes.NewStringAddress("sender"), ctypes.NewStringAddress("receiver"), "MCC",
+		common.NewBig(10), hex.EncodeToString(h[:]), 100, "", types.LockStatusLocked,
+	)
+
+	// NOTE the lock expires at the timeout; it can not be claimed at the
+	// timeout and can be refunded.
+	for now, expired := range map[uint64]bool{99: false, 100: true, 101: true} {
+		if lock.IsExpired(now) != expired {
+			t.Errorf("now %d: expired %v", now, !expired)
+		}
+	}
+}
+
+func TestLockIsValid(t *testing.T) {
+	h := sha256.Sum256([]byte("preimage"))
+	hashLock, preimage := hex.EncodeToString(h[:]), hex.EncodeToString([]byte("preimage"))
+	sender, receiver := ctypes.NewStringAddress("sender"), ctypes.NewStringAddress("receiver")
+
+	newLock := func(preimage string, status types.LockStatus) types.Lock {
+		return types.NewLock(sender, receiver, "MCC", common.NewBig(10), hashLock, 100, preimage, status)
+	}
+
+	for name, c := range map[string]struct {
+		lock  types.Lock
+		valid bool
+	}{
+		"locked":                 {newLock("", types.LockStatusLocked), true},
+		"claimed":                {newLock(preimage, types.LockStatusClaimed), true},
+		"refunded":               {newLock("", types.LockStatusRefunded), true},
+		"claimed without match":  {newLock(hex.EncodeToString([]byte("other")), types.LockStatusClaimed), false},
+		"locked with preimage":   {newLock(preimage, types.LockStatusLocked), false},
+		"refunded with preimage": {newLock(preimage, types.LockStatusRefunded), false},
+	} {
+		if err := c.lock.IsValid(nil); (err == nil) != c.valid {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"regexp"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/pkg/errors"
+)
+
+var MerchantHint = hint.MustNewHint("mitum-payment-merchant-v0.0.1")
+
+var (
+	MaxMerchantCategories = 10
+	categoryRegexp        = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+)
+
+// IsValidCategory checks the category tag of merchant, like "food" or
+// "travel".
+func IsValidCategory(category string) bool {
+	return categoryRegexp.MatchString(category)
+}
+
+// Merchant is the registered receiver address in the contract account with its
+// category tags. Merchant without category is not categorized.
+type Merchant struct {
+	hint.BaseHinter
+	address    base.Address
+	categories []string
+}
+
+func NewMerchant(address base.Address, categories []string) Merchant {
+	return Merchant{
+		BaseHinter: hint.NewBaseHinter(MerchantHint),
+		address:    address,
+		categories: categories,
+	}
+}
+
+func (m Merchant) IsValid([]byte) error {
+	if err := util.CheckIsValiders(nil, false,
+		m.BaseHinter,
+		m.address,
+	); err != nil {
+		return err
+	}
+
+	if len(m.categories) > MaxMerchantCategories {
+		return errors.Errorf("categories over max, %d > %d", len(m.categories), MaxMerchantCategories)
+	}
+
+	founds := map[string]struct{}{}
+	for i := range m.categories {
+		if !IsValidCategory(m.categories[i]) {
+			return errors.Errorf("invalid category, %q", m.categories[i])
+		}
+
+		if _, found := founds[m.categories[i]]; found {
+			return errors.Errorf("duplicated category, %q", m.categories[i])
+		}
+
+		founds[m.categories[i]] = struct{}{}
+	}
+
+	return nil
+}
+
+func (m Merchant) Bytes() []byte {
+	bs := make([][]byte, len(m.categories))
+	for i := range m.categories {
+		bs[i] = []byte(m.categories[i])
+	}
+
+	return util.ConcatBytesSlice(
+		m.address.Bytes(),
+		util.ConcatBytesSlice(bs...),
+	)
+}
+
+func (m Merchant) Address() base.Address {
+	return m.address
+}
+
+func (m Merchant) Categories() []string {
+	return m.categories
+}
+
+func (m Merchant) HasCategory(category string) bool {
+	for i := range m.categories {
+		if m.categories[i] == category {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (m Merchant) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bson.M{
+		"_hint":      m.Hint().String(),
+		"address":    m.address,
+		"categories": m.categories,
+	})
+}
+
+type MerchantBSONUnmarshaler struct {
+	Hint       string   `bson:"_hint"`
+	Address    string   `bson:"address"`
+	Categories []string `bson:"categories"`
+}
+
+func (m *Merchant) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of Merchant")
+
+	var u MerchantBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := m.unpack(enc, ht, u.Address, u.Categories); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+func (m *Merchant) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	addr string,
+	categories []string,
+) error {
+	m.BaseHinter = hint.NewBaseHinter(ht)
+
+	switch a, err := base.DecodeAddress(addr, enc); {
+	case err != nil:
+		return err
+	default:
+		m.address = a
+	}
+
+	m.categories = categories
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+type MerchantJSONMarshaler struct {
+	hint.BaseHinter
+	Address    base.Address `json:"address"`
+	Categories []string     `json:"categories"`
+}
+
+func (m Merchant) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(MerchantJSONMarshaler{
+		BaseHinter: m.BaseHinter,
+		Address:    m.address,
+		Categories: m.categories,
+	})
+}
+
+type MerchantJSONUnmarshaler struct {
+	Hint       hint.Hint `json:"_hint"`
+	Address    string    `json:"address"`
+	Categories []string  `json:"categories"`
+}
+
+func (m *Merchant) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of Merchant")
+
+	var u MerchantJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := m.unpack(enc, u.Hint, u.Address, u.Categories); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
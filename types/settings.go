@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/hint"
@@ -14,8 +15,11 @@ var SettingHint = hint.MustNewHint("mitum-payment-setting-v0.0.1")
 
 type Setting struct {
 	hint.BaseHinter
-	address base.Address
-	items   map[string]SettingItem
+	address     base.Address
+	items       map[string]SettingItem
+	lockKey     base.Publickey
+	recoveryKey base.Publickey
+	locked      bool
 }
 
 func NewSettings(
@@ -48,6 +52,26 @@ func (s Setting) IsValid([]byte) error {
 		}
 	}
 
+	switch {
+	case s.lockKey == nil && s.recoveryKey == nil:
+		if s.locked {
+			return common.ErrValueInvalid.Errorf("locked setting without lock key")
+		}
+	case s.lockKey == nil || s.recoveryKey == nil:
+		return common.ErrValueInvalid.Errorf("lock key and recovery key must be set together")
+	default:
+		if err := util.CheckIsValiders(nil, false,
+			s.lockKey,
+			s.recoveryKey,
+		); err != nil {
+			return err
+		}
+
+		if s.lockKey.Equal(s.recoveryKey) {
+			return common.ErrDupVal.Errorf("lock key is same with recovery key")
+		}
+	}
+
 	return nil
 }
 
@@ -60,9 +84,21 @@ func (s Setting) Bytes() []byte {
 		itm = []byte{}
 	}
 
+	var keys []byte
+	if s.lockKey != nil {
+		keys = util.ConcatBytesSlice(s.lockKey.Bytes(), s.recoveryKey.Bytes())
+	}
+
+	var locked []byte
+	if s.locked {
+		locked = []byte{1}
+	}
+
 	return util.ConcatBytesSlice(
 		s.address.Bytes(),
 		itm,
+		keys,
+		locked,
 	)
 }
 
@@ -74,8 +110,92 @@ func (s Setting) Items() map[string]SettingItem {
 	return s.items
 }
 
+func (s Setting) LockKey() base.Publickey {
+	return s.lockKey
+}
+
+func (s Setting) RecoveryKey() base.Publickey {
+	return s.recoveryKey
+}
+
+func (s Setting) IsLocked() bool {
+	return s.locked
+}
+
+func (s *Setting) SetKeys(lockKey, recoveryKey base.Publickey) {
+	s.lockKey = lockKey
+	s.recoveryKey = recoveryKey
+}
+
+func (s *Setting) SetLocked(locked bool) {
+	s.locked = locked
+}
+
+// SetItem sets the limit and the period of cid; the category limits and the
+// lock-up of cid are kept.
 func (s *Setting) SetItem(cid string, tLimit common.Big, startTime, endTime, duration uint64) {
-	s.items[cid] = NewSettingItem(tLimit, startTime, endTime, duration)
+	itm := NewSettingItem(tLimit, startTime, endTime, duration)
+	if o, found := s.items[cid]; found {
+		itm.CategoryLimits = o.CategoryLimits
+		itm.LockUp = o.LockUp
+	}
+
+	s.items[cid] = itm
+}
+
+// SetCategoryLimits replaces the category limits of cid; empty limits remove
+// the category rule.
+func (s *Setting) SetCategoryLimits(cid string, limits map[string]common.Big) {
+	itm, found := s.items[cid]
+	if !found {
+		return
+	}
+
+	if len(limits) < 1 {
+		itm.CategoryLimits = nil
+	} else {
+		itm.CategoryLimits = make(map[string]common.Big, len(limits))
+		for k, v := range limits {
+			itm.CategoryLimits[k] = v
+		}
+	}
+
+	s.items[cid] = itm
+}
+
+func (s Setting) CategoryLimits(cid string) map[string]common.Big {
+	itm, found := s.items[cid]
+	if !found {
+		return nil
+	}
+
+	return itm.CategoryLimits
+}
+
+// SetLockUp replaces the lock-up of cid; nil removes it.
+func (s *Setting) SetLockUp(cid string, lockUp *LockUp) {
+	itm, found := s.items[cid]
+	if !found {
+		return
+	}
+
+	if lockUp == nil {
+		itm.LockUp = nil
+	} else {
+		l := *lockUp
+		itm.LockUp = &l
+	}
+
+	s.items[cid] = itm
+}
+
+func (s Setting) LockUp(cid string) *LockUp {
+	itm, found := s.items[cid]
+	if !found {
+		return nil
+	}
+
+	return itm.LockUp
 }
 
 func (s Setting) TransferLimit(cid string) *common.Big {
@@ -112,6 +232,11 @@ type SettingItem struct {
 	StartTime     uint64     `bson:"start_time" json:"start_time"`
 	EndTime       uint64     `bson:"end_time" json:"end_time"`
 	Duration      uint64     `bson:"duration" json:"duration"`
+	// CategoryLimits restricts the receivers to the merchants of the given
+	// categories with the limit of each category.
+	CategoryLimits map[string]common.Big `bson:"category_limits,omitempty" json:"category_limits,omitempty"`
+	// LockUp blocks the withdraw before the maturity time.
+	LockUp *LockUp `bson:"lock_up,omitempty" json:"lock_up,omitempty"`
 }
 
 func NewSettingItem(tL common.Big, st, et, dur uint64) SettingItem {
@@ -133,5 +258,108 @@ func (t SettingItem) IsValid([]byte) error {
 		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Errorf("time data must be greater than zero"))
 	}
 
+	if len(t.CategoryLimits) > MaxMerchantCategories {
+		return common.ErrValueInvalid.Errorf(
+			"category limits over max, %d > %d", len(t.CategoryLimits), MaxMerchantCategories)
+	}
+
+	for k, v := range t.CategoryLimits {
+		if !IsValidCategory(k) {
+			return common.ErrValueInvalid.Errorf("invalid category, %q", k)
+		}
+
+		if err := v.IsValid(nil); err != nil {
+			return err
+		}
+	}
+
+	if t.LockUp != nil {
+		if err := t.LockUp.IsValid(nil); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// LockUpPenaltyBasis is the base of the penalty rate in basis points.
+const LockUpPenaltyBasis uint64 = 10000
+
+// LockUp rejects the withdraw before MaturityTime. When PenaltyReceiver is
+// set, the early withdraw is allowed and PenaltyRate basis points of the
+// amount are sent to PenaltyReceiver.
+type LockUp struct {
+	MaturityTime    uint64 `bson:"maturity_time" json:"maturity_time"`
+	PenaltyRate     uint64 `bson:"penalty_rate,omitempty" json:"penalty_rate,omitempty"`
+	PenaltyReceiver string `bson:"penalty_receiver,omitempty" json:"penalty_receiver,omitempty"`
+}
+
+func NewLockUp(maturityTime, penaltyRate uint64, penaltyReceiver base.Address) LockUp {
+	l := LockUp{
+		MaturityTime: maturityTime,
+		PenaltyRate:  penaltyRate,
+	}
+
+	if penaltyReceiver != nil {
+		l.PenaltyReceiver = penaltyReceiver.String()
+	}
+
+	return l
+}
+
+func (l LockUp) IsValid([]byte) error {
+	if l.MaturityTime < 1 {
+		return common.ErrValueInvalid.Errorf("maturity time must be greater than zero")
+	}
+
+	if l.PenaltyRate > LockUpPenaltyBasis {
+		return common.ErrValueInvalid.Errorf("penalty rate over %d, %d", LockUpPenaltyBasis, l.PenaltyRate)
+	}
+
+	if len(l.PenaltyReceiver) < 1 {
+		if l.PenaltyRate > 0 {
+			return common.ErrValueInvalid.Errorf("penalty rate without penalty receiver")
+		}
+
+		return nil
+	}
+
+	if _, err := l.Receiver(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AllowsEarlyWithdraw returns true when the withdraw before the maturity time
+// is allowed with the penalty.
+func (l LockUp) AllowsEarlyWithdraw() bool {
+	return len(l.PenaltyReceiver) > 0
+}
+
+func (l LockUp) Receiver() (base.Address, error) {
+	return ctypes.NewAddressFromString(l.PenaltyReceiver)
+}
+
+// Penalty returns the penalty of the withdraw of amount.
+func (l LockUp) Penalty(amount common.Big) common.Big {
+	return amount.MulInt64(int64(l.PenaltyRate)).Div(common.NewBig(int64(LockUpPenaltyBasis)))
+}
+
+// Weakens returns true when l releases the deposit earlier or cheaper than
+// prev at now; while prev is in force, any change of the penalty receiver
+// weakens it.
+func (l LockUp) Weakens(prev LockUp, now uint64) bool {
+	switch {
+	case l.MaturityTime < prev.MaturityTime:
+		return true
+	case !prev.AllowsEarlyWithdraw():
+		return l.AllowsEarlyWithdraw()
+	case now < prev.MaturityTime && l.PenaltyReceiver != prev.PenaltyReceiver:
+		return true
+	case !l.AllowsEarlyWithdraw():
+		return false
+	default:
+		return l.PenaltyRate < prev.PenaltyRate
+	}
+}
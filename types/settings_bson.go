@@ -8,17 +8,31 @@ import (
 )
 
 func (s Setting) MarshalBSON() ([]byte, error) {
-	return bsonenc.Marshal(bson.M{
+	m := bson.M{
 		"_hint":   s.Hint().String(),
 		"address": s.address,
 		"items":   s.items,
-	})
+	}
+
+	if s.lockKey != nil {
+		m["lock_key"] = s.lockKey.String()
+		m["recovery_key"] = s.recoveryKey.String()
+	}
+
+	if s.locked {
+		m["locked"] = s.locked
+	}
+
+	return bsonenc.Marshal(m)
 }
 
 type SettingBSONUnmarshaler struct {
-	Hint    string                 `bson:"_hint"`
-	Address string                 `bson:"address"`
-	Items   map[string]SettingItem `bson:"items"`
+	Hint        string                 `bson:"_hint"`
+	Address     string                 `bson:"address"`
+	Items       map[string]SettingItem `bson:"items"`
+	LockKey     string                 `bson:"lock_key"`
+	RecoveryKey string                 `bson:"recovery_key"`
+	Locked      bool                   `bson:"locked"`
 }
 
 func (s *Setting) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
@@ -36,7 +50,7 @@ func (s *Setting) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 
 	s.items = u.Items
 
-	err = s.unpack(enc, ht, u.Address)
+	err = s.unpack(enc, ht, u.Address, u.LockKey, u.RecoveryKey, u.Locked)
 	if err != nil {
 		return e.Wrap(err)
 	}
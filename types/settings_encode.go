@@ -9,7 +9,8 @@ import (
 func (s *Setting) unpack(
 	enc encoder.Encoder,
 	ht hint.Hint,
-	addr string,
+	addr, lockKey, recoveryKey string,
+	locked bool,
 ) error {
 	s.BaseHinter = hint.NewBaseHinter(ht)
 	address, err := base.DecodeAddress(addr, enc)
@@ -18,5 +19,23 @@ func (s *Setting) unpack(
 	}
 	s.address = address
 
+	if len(lockKey) > 0 {
+		pub, err := base.DecodePublickeyFromString(lockKey, enc)
+		if err != nil {
+			return err
+		}
+		s.lockKey = pub
+	}
+
+	if len(recoveryKey) > 0 {
+		pub, err := base.DecodePublickeyFromString(recoveryKey, enc)
+		if err != nil {
+			return err
+		}
+		s.recoveryKey = pub
+	}
+
+	s.locked = locked
+
 	return nil
 }
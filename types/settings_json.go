@@ -9,22 +9,39 @@ import (
 
 type SettingJSONMarshaler struct {
 	hint.BaseHinter
-	Address base.Address           `json:"address"`
-	Items   map[string]SettingItem `json:"items"`
+	Address     base.Address           `json:"address"`
+	Items       map[string]SettingItem `json:"items"`
+	LockKey     string                 `json:"lock_key,omitempty"`
+	RecoveryKey string                 `json:"recovery_key,omitempty"`
+	Locked      bool                   `json:"locked,omitempty"`
 }
 
 func (s Setting) MarshalJSON() ([]byte, error) {
+	var lockKey, recoveryKey string
+	if s.lockKey != nil {
+		lockKey = s.lockKey.String()
+	}
+	if s.recoveryKey != nil {
+		recoveryKey = s.recoveryKey.String()
+	}
+
 	return util.MarshalJSON(SettingJSONMarshaler{
-		BaseHinter: s.BaseHinter,
-		Address:    s.address,
-		Items:      s.items,
+		BaseHinter:  s.BaseHinter,
+		Address:     s.address,
+		Items:       s.items,
+		LockKey:     lockKey,
+		RecoveryKey: recoveryKey,
+		Locked:      s.locked,
 	})
 }
 
 type SettingJSONUnmarshaler struct {
-	Hint    hint.Hint              `json:"_hint"`
-	Address string                 `json:"address"`
-	Items   map[string]SettingItem `json:"items"`
+	Hint        hint.Hint              `json:"_hint"`
+	Address     string                 `json:"address"`
+	Items       map[string]SettingItem `json:"items"`
+	LockKey     string                 `json:"lock_key"`
+	RecoveryKey string                 `json:"recovery_key"`
+	Locked      bool                   `json:"locked"`
 }
 
 func (s *Setting) DecodeJSON(b []byte, enc encoder.Encoder) error {
@@ -37,7 +54,7 @@ func (s *Setting) DecodeJSON(b []byte, enc encoder.Encoder) error {
 
 	s.items = u.Items
 
-	err := s.unpack(enc, u.Hint, u.Address)
+	err := s.unpack(enc, u.Hint, u.Address, u.LockKey, u.RecoveryKey, u.Locked)
 	if err != nil {
 		return e.Wrap(err)
 	}
@@ -0,0 +1,40 @@
+package types_test
+
+import (
+	"testing"
+
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/payment-model/types"
+)
+
+func TestLockUpWeakens(t *testing.T) {
+	a := ctypes.NewStringAddress("receivera")
+	b := ctypes.NewStringAddress("receiverb")
+
+	prev := types.NewLockUp(100, 1000, a)
+
+	for name, c := range map[string]struct {
+		l       types.LockUp
+		now     uint64
+		weakens bool
+	}{
+		"same":               {l: prev, now: 50},
+		"later maturity":     {l: types.NewLockUp(200, 1000, a), now: 50},
+		"higher rate":        {l: types.NewLockUp(100, 2000, a), now: 50},
+		"earlier maturity":   {l: types.NewLockUp(90, 1000, a), now: 50, weakens: true},
+		"lower rate":         {l: types.NewLockUp(100, 500, a), now: 50, weakens: true},
+		"other receiver":     {l: types.NewLockUp(100, 1000, b), now: 50, weakens: true},
+		"cleared receiver":   {l: types.NewLockUp(100, 0, nil), now: 50, weakens: true},
+		"receiver in force":  {l: types.NewLockUp(200, 5000, b), now: 99, weakens: true},
+		"receiver after end": {l: types.NewLockUp(200, 1000, b), now: 100},
+	} {
+		if i := c.l.Weakens(prev, c.now); i != c.weakens {
+			t.Errorf("%s: weakens %v, not %v", name, i, c.weakens)
+		}
+	}
+
+	locked := types.NewLockUp(100, 0, nil)
+	if !types.NewLockUp(100, 0, a).Weakens(locked, 50) {
+		t.Error("early withdraw allowed on the lock-up without it")
+	}
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/pkg/errors"
+)
+
+var VoucherHint = hint.MustNewHint("mitum-payment-voucher-v0.0.1")
+
+// Voucher is signed off-chain by the depositor of a channel. The amount is the
+// cumulative amount which the receiver can redeem from the channel.
+type Voucher struct {
+	hint.BaseHinter
+	contract  base.Address
+	depositor base.Address
+	receiver  base.Address
+	currency  ctypes.CurrencyID
+	nonce     uint64
+	amount    common.Big
+	signs     []base.Sign
+}
+
+func NewVoucher(
+	contract, depositor, receiver base.Address,
+	currency ctypes.CurrencyID,
+	nonce uint64,
+	amount common.Big,
+) Voucher {
+	return Voucher{
+		BaseHinter: hint.NewBaseHinter(VoucherHint),
+		contract:   contract,
+		depositor:  depositor,
+		receiver:   receiver,
+		currency:   currency,
+		nonce:      nonce,
+		amount:     amount,
+	}
+}
+
+func (v Voucher) IsValid([]byte) error {
+	if err := util.CheckIsValiders(nil, false,
+		v.BaseHinter,
+		v.contract,
+		v.depositor,
+		v.receiver,
+		v.currency,
+		v.amount,
+	); err != nil {
+		return err
+	}
+
+	if !v.amount.OverZero() {
+		return errors.Errorf("amount must be over zero")
+	}
+
+	if len(v.signs) < 1 {
+		return errors.Errorf("empty signs")
+	}
+
+	founds := map[string]struct{}{}
+	for i := range v.signs {
+		if err := v.signs[i].IsValid(nil); err != nil {
+			return err
+		}
+
+		k := v.signs[i].Signer().String()
+		if _, found := founds[k]; found {
+			return errors.Errorf("duplicated signer, %v", k)
+		}
+		founds[k] = struct{}{}
+	}
+
+	return nil
+}
+
+// Bytes returns the signed body of the voucher; the signs are not included.
+func (v Voucher) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		v.contract.Bytes(),
+		v.depositor.Bytes(),
+		v.receiver.Bytes(),
+		v.currency.Bytes(),
+		util.Uint64ToBytes(v.nonce),
+		v.amount.Bytes(),
+	)
+}
+
+func (v Voucher) Contract() base.Address {
+	return v.contract
+}
+
+func (v Voucher) Depositor() base.Address {
+	return v.depositor
+}
+
+func (v Voucher) Receiver() base.Address {
+	return v.receiver
+}
+
+func (v Voucher) Currency() ctypes.CurrencyID {
+	return v.currency
+}
+
+func (v Voucher) Nonce() uint64 {
+	return v.nonce
+}
+
+func (v Voucher) Amount() common.Big {
+	return v.amount
+}
+
+func (v Voucher) Signs() []base.Sign {
+	return v.signs
+}
+
+func (v *Voucher) Sign(priv base.Privatekey, networkID base.NetworkID) error {
+	sign, err := base.NewBaseSignFromBytes(priv, networkID, v.Bytes())
+	if err != nil {
+		return err
+	}
+
+	for i := range v.signs {
+		if v.signs[i].Signer().Equal(sign.Signer()) {
+			v.signs[i] = sign
+
+			return nil
+		}
+	}
+
+	v.signs = append(v.signs, sign)
+
+	return nil
+}
+
+// VerifySigns verifies the signatures of the voucher; whether the signers
+// satisfy the keys of the depositor is checked with the account state.
+func (v Voucher) VerifySigns(networkID base.NetworkID) error {
+	for i := range v.signs {
+		if err := v.signs[i].Verify(networkID, v.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
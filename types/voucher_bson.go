@@ -0,0 +1,81 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (v Voucher) MarshalBSON() ([]byte, error) {
+	var signs bson.A
+
+	for i := range v.signs {
+		signs = append(signs, bson.M{
+			"signer":    v.signs[i].Signer().String(),
+			"signature": v.signs[i].Signature().String(),
+			"signed_at": v.signs[i].SignedAt(),
+		})
+	}
+
+	return bsonenc.Marshal(bson.M{
+		"_hint":     v.Hint().String(),
+		"contract":  v.contract,
+		"depositor": v.depositor,
+		"receiver":  v.receiver,
+		"currency":  v.currency,
+		"nonce":     v.nonce,
+		"amount":    v.amount,
+		"signs":     signs,
+	})
+}
+
+type VoucherBSONUnmarshaler struct {
+	Hint      string     `bson:"_hint"`
+	Contract  string     `bson:"contract"`
+	Depositor string     `bson:"depositor"`
+	Receiver  string     `bson:"receiver"`
+	Currency  string     `bson:"currency"`
+	Nonce     uint64     `bson:"nonce"`
+	Amount    common.Big `bson:"amount"`
+	Signs     []bson.Raw `bson:"signs"`
+}
+
+func (v *Voucher) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("decode bson of Voucher")
+
+	var u VoucherBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	signs := make([]base.Sign, len(u.Signs))
+	for i := range u.Signs {
+		var us common.BaseSignBSONUnmarshaler
+		if err := enc.Unmarshal(u.Signs[i], &us); err != nil {
+			return e.Wrap(err)
+		}
+
+		pub, err := base.DecodePublickeyFromString(us.Signer, enc)
+		if err != nil {
+			return e.Wrap(err)
+		}
+
+		signs[i] = base.NewBaseSign(pub, us.Signature, us.SignedAt)
+	}
+
+	if err := v.unpack(enc, ht,
+		u.Contract, u.Depositor, u.Receiver, u.Currency, u.Nonce, u.Amount, signs,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+func (v *Voucher) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	ca, da, ra, cid string,
+	nonce uint64,
+	amount common.Big,
+	signs []base.Sign,
+) error {
+	v.BaseHinter = hint.NewBaseHinter(ht)
+
+	switch a, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		v.contract = a
+	}
+
+	switch a, err := base.DecodeAddress(da, enc); {
+	case err != nil:
+		return err
+	default:
+		v.depositor = a
+	}
+
+	switch a, err := base.DecodeAddress(ra, enc); {
+	case err != nil:
+		return err
+	default:
+		v.receiver = a
+	}
+
+	v.currency = ctypes.CurrencyID(cid)
+	v.nonce = nonce
+	v.amount = amount
+	v.signs = signs
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+type VoucherJSONMarshaler struct {
+	hint.BaseHinter
+	Contract  base.Address      `json:"contract"`
+	Depositor base.Address      `json:"depositor"`
+	Receiver  base.Address      `json:"receiver"`
+	Currency  ctypes.CurrencyID `json:"currency"`
+	Nonce     uint64            `json:"nonce"`
+	Amount    common.Big        `json:"amount"`
+	Signs     []base.Sign       `json:"signs"`
+}
+
+func (v Voucher) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(VoucherJSONMarshaler{
+		BaseHinter: v.BaseHinter,
+		Contract:   v.contract,
+		Depositor:  v.depositor,
+		Receiver:   v.receiver,
+		Currency:   v.currency,
+		Nonce:      v.nonce,
+		Amount:     v.amount,
+		Signs:      v.signs,
+	})
+}
+
+type VoucherJSONUnmarshaler struct {
+	Hint      hint.Hint         `json:"_hint"`
+	Contract  string            `json:"contract"`
+	Depositor string            `json:"depositor"`
+	Receiver  string            `json:"receiver"`
+	Currency  string            `json:"currency"`
+	Nonce     uint64            `json:"nonce"`
+	Amount    common.Big        `json:"amount"`
+	Signs     []json.RawMessage `json:"signs"`
+}
+
+func (v *Voucher) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("failed to decode json of Voucher")
+
+	var u VoucherJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	signs := make([]base.Sign, len(u.Signs))
+	for i := range u.Signs {
+		var ub base.BaseSign
+		if err := ub.DecodeJSON(u.Signs[i], enc); err != nil {
+			return e.Wrap(err)
+		}
+
+		signs[i] = ub
+	}
+
+	if err := v.unpack(enc, u.Hint,
+		u.Contract, u.Depositor, u.Receiver, u.Currency, u.Nonce, u.Amount, signs,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}